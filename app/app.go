@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -40,8 +41,12 @@ import (
 	"github.com/ChainSafe/sygma-relayer/config"
 	"github.com/ChainSafe/sygma-relayer/health"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/keyshare/consistency"
 	"github.com/ChainSafe/sygma-relayer/topology"
 	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/journal"
+	"github.com/ChainSafe/sygma-relayer/tss/presign"
+	"github.com/ChainSafe/sygma-relayer/tss/reputation"
 	"github.com/ethereum/go-ethereum/common"
 	secp256k1 "github.com/ethereum/go-ethereum/crypto"
 	"github.com/libp2p/go-libp2p-core/crypto"
@@ -64,11 +69,11 @@ func Run() error {
 
 	logger.ConfigureLogger(configuration.RelayerConfig.LogLevel, os.Stdout)
 
-	go health.StartHealthEndpoint(configuration.RelayerConfig.HealthPort)
-
 	topologyProvider, err := topology.NewNetworkTopologyProvider(configuration.RelayerConfig.MpcConfig.TopologyConfiguration)
 	panicOnError(err)
 	topologyStore := topology.NewTopologyStore(configuration.RelayerConfig.MpcConfig.TopologyConfiguration.Path)
+	peerMonitor := health.NewPeerMonitor(topologyStore)
+	go health.StartHealthEndpoint(configuration.RelayerConfig.HealthPort, peerMonitor)
 	networkTopology, err := topologyStore.Topology()
 	// if topology is not already in file, read from provider
 	if err != nil {
@@ -93,6 +98,7 @@ func Run() error {
 	}
 
 	blockstore := store.NewBlockStore(db)
+	sessionJournal := journal.NewSessionJournal(db)
 
 	privBytes, err := crypto.ConfigDecodeKey(configuration.RelayerConfig.MpcConfig.Key)
 	panicOnError(err)
@@ -104,13 +110,45 @@ func Run() error {
 	host, err := p2p.NewHost(priv, networkTopology, connectionGate, configuration.RelayerConfig.MpcConfig.Port)
 	panicOnError(err)
 
-	healthComm := p2p.NewCommunication(host, "p2p/health")
-	go comm.ExecuteCommHealthCheck(healthComm, host.Peerstore().Peers())
-
-	communication := p2p.NewCommunication(host, "p2p/sygma")
-	electorFactory := elector.NewCoordinatorElectorFactory(host, configuration.RelayerConfig.BullyConfig)
-	coordinator := tss.NewCoordinator(host, communication, electorFactory)
-	keyshareStore := keyshare.NewKeyshareStore(configuration.RelayerConfig.MpcConfig.KeysharePath)
+	inboundLimiter := p2p.NewInboundLimiter(configuration.RelayerConfig.MessageLimits)
+	http.Handle("/health/inbound", inboundLimiter)
+	healthComm := p2p.NewCommunicationWithLimiter(host, "p2p/health", inboundLimiter)
+	healthChecker := comm.NewHealthChecker(healthComm)
+	peerMonitor.SetPinger(host.ID(), p2p.NewPinger(host))
+	http.Handle("/health/peers", peerMonitor)
+
+	communication := p2p.NewCommunicationWithLimiter(host, "p2p/sygma", inboundLimiter)
+	http.Handle("/health/messages", communication)
+	reputationTracker := reputation.NewTracker(configuration.RelayerConfig.MpcConfig.ReputationWindow)
+	http.Handle("/reputation", reputationTracker)
+	electorFactory := elector.NewCoordinatorElectorFactoryWithCommunication(
+		host,
+		p2p.NewCommunicationWithLimiter(host, elector.ProtocolID, inboundLimiter),
+		configuration.RelayerConfig.BullyConfig,
+	)
+	coordinator := tss.NewCoordinator(host, communication, electorFactory, sessionJournal, reputationTracker)
+	keyshareEncryption, err := keyshare.NewEncryption(
+		configuration.RelayerConfig.MpcConfig.KeysharePassphrase, configuration.RelayerConfig.MpcConfig.KeyshareKeyFile,
+	)
+	panicOnError(err)
+	keyshareBackend, err := keyshare.NewBackend(
+		configuration.RelayerConfig.MpcConfig.KeyshareBackend,
+		configuration.RelayerConfig.MpcConfig.KeysharePath,
+		db,
+		configuration.RelayerConfig.MpcConfig.KeyshareRemoteURL,
+		configuration.RelayerConfig.MpcConfig.KeyshareRemoteToken,
+	)
+	panicOnError(err)
+	keyshareStore := keyshare.NewKeyshareStoreWithBackend(keyshareBackend, keyshareEncryption)
+	migrated, err := keyshareStore.Migrate()
+	panicOnError(err)
+	if migrated {
+		log.Info().Msgf("Encrypted plaintext keyshare in %s keyshare backend", configuration.RelayerConfig.MpcConfig.KeyshareBackend)
+	}
+	keyshareChecker := consistency.NewChecker(host, communication, keyshareStore)
+	http.Handle("/health/keyshare", keyshareChecker)
+	presignaturePool := presign.NewPoolWithEncryption(db, keyshareStore, keyshareEncryption, configuration.RelayerConfig.MpcConfig.PresignaturePoolSize)
+	presigner := presign.NewManager(presignaturePool, host, communication, coordinator, keyshareStore, configuration.RelayerConfig.MpcConfig.PresignaturePoolSize)
 
 	chains := []relayer.RelayedChain{}
 	for _, chainConfig := range configuration.ChainConfigs {
@@ -150,8 +188,10 @@ func Run() error {
 				tssListener := events.NewListener(client)
 				eventHandlers := make([]coreListener.EventHandler, 0)
 				eventHandlers = append(eventHandlers, coreListener.NewDepositEventHandler(depositListener, depositHandler, bridgeAddress, *config.GeneralChainConfig.Id))
-				eventHandlers = append(eventHandlers, listener.NewKeygenEventHandler(tssListener, coordinator, host, communication, keyshareStore, bridgeAddress, networkTopology.Threshold))
-				eventHandlers = append(eventHandlers, listener.NewRefreshEventHandler(topologyProvider, topologyStore, tssListener, coordinator, host, communication, connectionGate, keyshareStore, bridgeAddress))
+				eventHandlers = append(eventHandlers, listener.NewKeygenEventHandler(tssListener, coordinator, host, communication, keyshareStore, sessionJournal, presigner, bridgeAddress, networkTopology.Threshold))
+				refreshEventHandler := listener.NewRefreshEventHandler(topologyProvider, topologyStore, configuration.RelayerConfig.MpcConfig.TopologyConfiguration.HashMode, tssListener, coordinator, host, communication, connectionGate, healthChecker, keyshareStore, sessionJournal, presigner, keyshareChecker, db, bridgeAddress, *config.GeneralChainConfig.Id)
+				http.Handle(fmt.Sprintf("/health/refresh/%d", *config.GeneralChainConfig.Id), refreshEventHandler)
+				eventHandlers = append(eventHandlers, refreshEventHandler)
 				eventHandlers = append(eventHandlers, listener.NewRetryEventHandler(tssListener, depositHandler, bridgeAddress, *config.GeneralChainConfig.Id, config.BlockConfirmations))
 				evmListener := coreListener.NewEVMListener(client, eventHandlers, blockstore, config)
 
@@ -160,7 +200,10 @@ func Run() error {
 				mh.RegisterMessageHandler(config.Erc721Handler, coreExecutor.ERC721MessageHandler)
 				mh.RegisterMessageHandler(config.GenericHandler, coreExecutor.GenericMessageHandler)
 				mh.RegisterMessageHandler(pGenericHandler, executor.PermissionlessGenericMessageHandler)
-				executor := executor.NewExecutor(host, communication, coordinator, mh, bridgeContract, keyshareStore)
+				executor := executor.NewExecutor(host, communication, coordinator, mh, bridgeContract, keyshareStore, sessionJournal, presigner, reputationTracker, *config.GeneralChainConfig.Id)
+				executor.RequireProposalVerification = configuration.RelayerConfig.MpcConfig.RequireProposalVerification
+				err = executor.Resume()
+				panicOnError(err)
 
 				coreEvmChain := coreEvm.NewEVMChain(evmListener, nil, blockstore, config)
 				chain := evm.NewEVMChain(*coreEvmChain, executor)
@@ -181,6 +224,9 @@ func Run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go r.Start(ctx, errChn)
+	go presigner.Start(ctx)
+	go keyshareChecker.Start(ctx, configuration.RelayerConfig.MpcConfig.KeyshareCheckInterval)
+	go peerMonitor.Start(ctx, configuration.RelayerConfig.MpcConfig.PeerCheckInterval)
 
 	sysErr := make(chan os.Signal, 1)
 	signal.Notify(sysErr,
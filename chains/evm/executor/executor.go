@@ -21,6 +21,7 @@ import (
 
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/journal"
 	"github.com/ChainSafe/sygma-relayer/tss/signing"
 )
 
@@ -39,6 +40,12 @@ type BridgeContract interface {
 	ProposalsHash(proposals []*proposal.Proposal) ([]byte, error)
 }
 
+type SessionJournal interface {
+	RecordSession(sessionID string, process journal.ProcessType, proposals []*proposal.Proposal) error
+	UnfinishedSessions(process journal.ProcessType) ([]journal.Session, error)
+	Finish(sessionID string, err error) error
+}
+
 type Executor struct {
 	coordinator *tss.Coordinator
 	host        host.Host
@@ -46,6 +53,14 @@ type Executor struct {
 	fetcher     signing.SaveDataFetcher
 	bridge      BridgeContract
 	mh          MessageHandler
+	journal     SessionJournal
+	presigner   signing.PresignaturePool
+	prioritizer signing.PeerPrioritizer
+	domainID    uint8
+
+	// RequireProposalVerification rejects start params without proposals, sent by
+	// coordinators that don't support proposal verification
+	RequireProposalVerification bool
 }
 
 func NewExecutor(
@@ -55,6 +70,10 @@ func NewExecutor(
 	mh MessageHandler,
 	bridgeContract BridgeContract,
 	fetcher signing.SaveDataFetcher,
+	journal SessionJournal,
+	presigner signing.PresignaturePool,
+	prioritizer signing.PeerPrioritizer,
+	domainID uint8,
 ) *Executor {
 	return &Executor{
 		host:        host,
@@ -63,6 +82,10 @@ func NewExecutor(
 		mh:          mh,
 		bridge:      bridgeContract,
 		fetcher:     fetcher,
+		journal:     journal,
+		presigner:   presigner,
+		prioritizer: prioritizer,
+		domainID:    domainID,
 	}
 }
 
@@ -75,6 +98,47 @@ func (e *Executor) Execute(msgs []*message.Message) error {
 			return err
 		}
 
+		proposals = append(proposals, prop)
+	}
+
+	return e.executeProposals(proposals)
+}
+
+// Resume restarts signing sessions for this domain that were left unfinished
+// in the session journal when the relayer stopped.
+func (e *Executor) Resume() error {
+	sessions, err := e.journal.UnfinishedSessions(journal.SigningProcess)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if len(session.Proposals) == 0 || session.Proposals[0].Destination != e.domainID {
+			continue
+		}
+
+		go func(session journal.Session) {
+			log.Info().Str("SessionID", session.SessionID).Msgf("Resuming unfinished signing session")
+			err := e.executeProposals(session.Proposals)
+			if err != nil {
+				log.Err(err).Str("SessionID", session.SessionID).Msgf("Failed resuming signing session")
+			}
+
+			// proposals can be partially executed which changes the session ID of the
+			// resumed session so the original session has to be closed explicitly
+			err = e.journal.Finish(session.SessionID, err)
+			if err != nil {
+				log.Err(err).Str("SessionID", session.SessionID).Msgf("Unable to finish resumed session")
+			}
+		}(session)
+	}
+
+	return nil
+}
+
+func (e *Executor) executeProposals(props []*proposal.Proposal) error {
+	proposals := make([]*proposal.Proposal, 0)
+	for _, prop := range props {
 		isExecuted, err := e.bridge.IsProposalExecuted(prop)
 		if err != nil {
 			return err
@@ -102,7 +166,15 @@ func (e *Executor) Execute(msgs []*message.Message) error {
 		e.sessionID(propHash),
 		e.host,
 		e.comm,
-		e.fetcher)
+		e.fetcher,
+		e.presigner,
+		e.prioritizer,
+		NewProposalVerifier(e.bridge, proposals, e.RequireProposalVerification))
+	if err != nil {
+		return err
+	}
+
+	err = e.journal.RecordSession(sessionID, journal.SigningProcess, proposals)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/executor/proposal-verifier.go
+
+// Package mock_executor is a generated GoMock package.
+package mock_executor
+
+import (
+	reflect "reflect"
+
+	proposal "github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProposalHasher is a mock of ProposalHasher interface.
+type MockProposalHasher struct {
+	ctrl     *gomock.Controller
+	recorder *MockProposalHasherMockRecorder
+}
+
+// MockProposalHasherMockRecorder is the mock recorder for MockProposalHasher.
+type MockProposalHasherMockRecorder struct {
+	mock *MockProposalHasher
+}
+
+// NewMockProposalHasher creates a new mock instance.
+func NewMockProposalHasher(ctrl *gomock.Controller) *MockProposalHasher {
+	mock := &MockProposalHasher{ctrl: ctrl}
+	mock.recorder = &MockProposalHasherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProposalHasher) EXPECT() *MockProposalHasherMockRecorder {
+	return m.recorder
+}
+
+// ProposalsHash mocks base method.
+func (m *MockProposalHasher) ProposalsHash(proposals []*proposal.Proposal) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProposalsHash", proposals)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProposalsHash indicates an expected call of ProposalsHash.
+func (mr *MockProposalHasherMockRecorder) ProposalsHash(proposals interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProposalsHash", reflect.TypeOf((*MockProposalHasher)(nil).ProposalsHash), proposals)
+}
@@ -0,0 +1,83 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package executor
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
+)
+
+type ProposalHasher interface {
+	ProposalsHash(proposals []*proposal.Proposal) ([]byte, error)
+}
+
+type proposalKey struct {
+	source       uint8
+	depositNonce uint64
+}
+
+// ProposalVerifier verifies proposals sent by the coordinator against proposals this relayer
+// derived from deposit events it observed on the source chains.
+type ProposalVerifier struct {
+	hasher    ProposalHasher
+	proposals []*proposal.Proposal
+	required  bool
+}
+
+// NewProposalVerifier creates a proposal verifier. If required is false start params of coordinators
+// that don't send proposals are accepted without verification.
+func NewProposalVerifier(hasher ProposalHasher, proposals []*proposal.Proposal, required bool) *ProposalVerifier {
+	return &ProposalVerifier{
+		hasher:    hasher,
+		proposals: proposals,
+		required:  required,
+	}
+}
+
+// Required returns true if start params without proposals are rejected.
+func (v *ProposalVerifier) Required() bool {
+	return v.required
+}
+
+// Proposals returns locally derived proposals that are sent with the start message.
+func (v *ProposalVerifier) Proposals() []*proposal.Proposal {
+	return v.proposals
+}
+
+// VerifyProposals checks that every received proposal matches the locally derived one for the same
+// deposit and returns recomputed hash of received proposals.
+func (v *ProposalVerifier) VerifyProposals(proposals []*proposal.Proposal) ([]byte, error) {
+	if len(proposals) == 0 {
+		return nil, fmt.Errorf("no proposals received")
+	}
+
+	localProposals := make(map[proposalKey]*proposal.Proposal)
+	for _, prop := range v.proposals {
+		localProposals[proposalKey{prop.Source, prop.DepositNonce}] = prop
+	}
+
+	for _, prop := range proposals {
+		localProp, ok := localProposals[proposalKey{prop.Source, prop.DepositNonce}]
+		if !ok {
+			return nil, fmt.Errorf("deposit %d from domain %d not observed", prop.DepositNonce, prop.Source)
+		}
+		if !equalProposals(prop, localProp) {
+			return nil, fmt.Errorf("proposal for deposit %d from domain %d doesn't match observed deposit", prop.DepositNonce, prop.Source)
+		}
+	}
+
+	return v.hasher.ProposalsHash(proposals)
+}
+
+func equalProposals(p1 *proposal.Proposal, p2 *proposal.Proposal) bool {
+	return p1.Destination == p2.Destination &&
+		p1.ResourceId == p2.ResourceId &&
+		p1.HandlerAddress == p2.HandlerAddress &&
+		p1.BridgeAddress == p2.BridgeAddress &&
+		bytes.Equal(p1.Data, p2.Data) &&
+		p1.Metadata.Priority == p2.Metadata.Priority &&
+		bytes.Equal(p1.Metadata.Blob, p2.Metadata.Blob)
+}
@@ -0,0 +1,85 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package executor_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
+	"github.com/ChainSafe/chainbridge-core/relayer/message"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/executor"
+	mock_executor "github.com/ChainSafe/sygma-relayer/chains/evm/executor/mock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProposalVerifierTestSuite struct {
+	suite.Suite
+	mockHasher *mock_executor.MockProposalHasher
+	proposals  []*proposal.Proposal
+	verifier   *executor.ProposalVerifier
+}
+
+func TestRunProposalVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(ProposalVerifierTestSuite))
+}
+
+func (s *ProposalVerifierTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockHasher = mock_executor.NewMockProposalHasher(ctrl)
+	s.proposals = []*proposal.Proposal{
+		proposal.NewProposal(
+			1, 2, 1, [32]byte{1}, []byte{1, 2},
+			common.HexToAddress("0x3cA3808176Ad060Ad80c4e08F30d85973Ef1d99e"),
+			common.HexToAddress("0xd606A00c1A39dA53EA7Bb3Ab570BBE40b156EB66"),
+			message.Metadata{},
+		),
+		proposal.NewProposal(
+			3, 2, 1, [32]byte{1}, []byte{3, 4},
+			common.HexToAddress("0x3cA3808176Ad060Ad80c4e08F30d85973Ef1d99e"),
+			common.HexToAddress("0xd606A00c1A39dA53EA7Bb3Ab570BBE40b156EB66"),
+			message.Metadata{},
+		),
+	}
+	s.verifier = executor.NewProposalVerifier(s.mockHasher, s.proposals, true)
+}
+
+func (s *ProposalVerifierTestSuite) Test_VerifyProposals_NoProposals() {
+	_, err := s.verifier.VerifyProposals([]*proposal.Proposal{})
+
+	s.NotNil(err)
+}
+
+func (s *ProposalVerifierTestSuite) Test_VerifyProposals_DepositNotObserved() {
+	proposals := []*proposal.Proposal{
+		proposal.NewProposal(1, 2, 5, [32]byte{1}, []byte{1, 2}, common.Address{}, common.Address{}, message.Metadata{}),
+	}
+
+	_, err := s.verifier.VerifyProposals(proposals)
+
+	s.NotNil(err)
+}
+
+func (s *ProposalVerifierTestSuite) Test_VerifyProposals_ProposalDataMismatch() {
+	invalidProposal := *s.proposals[1]
+	invalidProposal.Data = []byte{5, 6}
+
+	_, err := s.verifier.VerifyProposals([]*proposal.Proposal{s.proposals[0], &invalidProposal})
+
+	s.NotNil(err)
+}
+
+func (s *ProposalVerifierTestSuite) Test_VerifyProposals_ValidProposals() {
+	propBytes, _ := json.Marshal(s.proposals)
+	var receivedProposals []*proposal.Proposal
+	_ = json.Unmarshal(propBytes, &receivedProposals)
+	s.mockHasher.EXPECT().ProposalsHash(receivedProposals).Return([]byte("hash"), nil)
+
+	hash, err := s.verifier.VerifyProposals(receivedProposals)
+
+	s.Nil(err)
+	s.Equal([]byte("hash"), hash)
+}
@@ -5,14 +5,19 @@ package listener
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/rs/zerolog/log"
 
 	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/events"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
 	"github.com/ChainSafe/chainbridge-core/chains/evm/listener"
 	"github.com/ChainSafe/chainbridge-core/relayer/message"
 	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/consts"
@@ -22,11 +27,13 @@ import (
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/ChainSafe/sygma-relayer/topology"
 	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/journal"
 	"github.com/ChainSafe/sygma-relayer/tss/keygen"
 	"github.com/ChainSafe/sygma-relayer/tss/resharing"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
 )
 
 type EventListener interface {
@@ -36,6 +43,19 @@ type EventListener interface {
 	FetchDepositEvent(event hubEvents.RetryEvent, bridgeAddress common.Address, blockConfirmations *big.Int) ([]events.Deposit, error)
 }
 
+type SessionJournal interface {
+	RecordSession(sessionID string, process journal.ProcessType, proposals []*proposal.Proposal) error
+}
+
+type Presigner interface {
+	Invalidate() error
+	Fill(sessionID string)
+}
+
+type KeyshareChecker interface {
+	Trigger(sessionID string)
+}
+
 type RetryEventHandler struct {
 	eventListener      EventListener
 	depositHandler     listener.DepositHandler
@@ -113,6 +133,8 @@ type KeygenEventHandler struct {
 	host          host.Host
 	communication comm.Communication
 	storer        keygen.SaveDataStorer
+	journal       SessionJournal
+	presigner     Presigner
 	bridgeAddress common.Address
 	threshold     int
 }
@@ -123,6 +145,8 @@ func NewKeygenEventHandler(
 	host host.Host,
 	communication comm.Communication,
 	storer keygen.SaveDataStorer,
+	journal SessionJournal,
+	presigner Presigner,
 	bridgeAddress common.Address,
 	threshold int,
 ) *KeygenEventHandler {
@@ -132,6 +156,8 @@ func NewKeygenEventHandler(
 		host:          host,
 		communication: communication,
 		storer:        storer,
+		journal:       journal,
+		presigner:     presigner,
 		bridgeAddress: bridgeAddress,
 		threshold:     threshold,
 	}
@@ -152,8 +178,21 @@ func (eh *KeygenEventHandler) HandleEvent(startBlock *big.Int, endBlock *big.Int
 	}
 
 	keygenBlockNumber := big.NewInt(0).SetUint64(keygenEvents[0].BlockNumber)
+	err = eh.journal.RecordSession(eh.sessionID(keygenBlockNumber), journal.KeygenProcess, nil)
+	if err != nil {
+		return err
+	}
+
 	keygen := keygen.NewKeygen(eh.sessionID(keygenBlockNumber), eh.threshold, eh.host, eh.communication, eh.storer)
-	go eh.coordinator.Execute(context.Background(), keygen, make(chan interface{}, 1), make(chan error, 1))
+	go func() {
+		statusChn := make(chan error, 1)
+		eh.coordinator.Execute(context.Background(), keygen, make(chan interface{}, 1), statusChn)
+		if err := <-statusChn; err != nil || !sessionDone(eh.coordinator, keygen.SessionID()) {
+			return
+		}
+
+		eh.presigner.Fill(keygen.SessionID())
+	}()
 
 	return nil
 }
@@ -162,80 +201,347 @@ func (eh *KeygenEventHandler) sessionID(block *big.Int) string {
 	return fmt.Sprintf("keygen-%s", block.String())
 }
 
+type PeerHealthChecker interface {
+	CheckPeers(peers peer.IDSlice) []*comm.CommunicationError
+}
+
+type RefreshStatus string
+
+const (
+	// RefreshPostponed means not enough parties of the new topology were reachable
+	// and the refresh is retried on the next block range.
+	RefreshPostponed RefreshStatus = "postponed"
+	// RefreshStarted means the new topology was stored and resharing started.
+	RefreshStarted RefreshStatus = "started"
+)
+
+// RefreshReport contains the topology diff and reachability of parties of the latest refresh.
+type RefreshReport struct {
+	Time        time.Time             `json:"time"`
+	SessionID   string                `json:"sessionID"`
+	Hash        string                `json:"hash"`
+	Status      RefreshStatus         `json:"status"`
+	Diff        topology.TopologyDiff `json:"diff"`
+	Unreachable []peer.ID             `json:"unreachable"`
+	Reachable   int                   `json:"reachable"`
+	Required    int                   `json:"required"`
+}
+
+type pendingRefresh struct {
+	sessionID string
+	hash      string
+	current   topology.NetworkTopology
+	topology  topology.NetworkTopology
+	diff      topology.TopologyDiff
+}
+
+// storedRefresh is the postponed refresh persisted so it is retried after a restart.
+type storedRefresh struct {
+	SessionID string                   `json:"sessionID"`
+	Hash      string                   `json:"hash"`
+	Topology  topology.NetworkTopology `json:"topology"`
+}
+
+type KeyValueReaderWriter interface {
+	GetByKey(key []byte) ([]byte, error)
+	SetByKey(key []byte, value []byte) error
+}
+
 type RefreshEventHandler struct {
 	topologyProvider topology.NetworkTopologyProvider
 	topologyStore    *topology.TopologyStore
+	hashMode         string
 	eventListener    EventListener
 	bridgeAddress    common.Address
 	coordinator      *tss.Coordinator
 	host             host.Host
 	communication    comm.Communication
 	connectionGate   *p2p.ConnectionGate
+	healthChecker    PeerHealthChecker
 	storer           resharing.SaveDataStorer
+	journal          SessionJournal
+	presigner        Presigner
+	keyshareChecker  KeyshareChecker
+	db               KeyValueReaderWriter
+	domainID         uint8
+
+	pending    *pendingRefresh
+	reportLock sync.Mutex
+	report     *RefreshReport
 }
 
 func NewRefreshEventHandler(
 	topologyProvider topology.NetworkTopologyProvider,
 	topologyStore *topology.TopologyStore,
+	hashMode string,
 	eventListener EventListener,
 	coordinator *tss.Coordinator,
 	host host.Host,
 	communication comm.Communication,
 	connectionGate *p2p.ConnectionGate,
+	healthChecker PeerHealthChecker,
 	storer resharing.SaveDataStorer,
+	journal SessionJournal,
+	presigner Presigner,
+	keyshareChecker KeyshareChecker,
+	db KeyValueReaderWriter,
 	bridgeAddress common.Address,
+	domainID uint8,
 ) *RefreshEventHandler {
-	return &RefreshEventHandler{
+	eh := &RefreshEventHandler{
 		topologyProvider: topologyProvider,
 		topologyStore:    topologyStore,
+		hashMode:         hashMode,
 		eventListener:    eventListener,
 		coordinator:      coordinator,
 		host:             host,
 		communication:    communication,
 		storer:           storer,
+		journal:          journal,
+		presigner:        presigner,
+		keyshareChecker:  keyshareChecker,
 		connectionGate:   connectionGate,
+		healthChecker:    healthChecker,
+		db:               db,
 		bridgeAddress:    bridgeAddress,
+		domainID:         domainID,
 	}
+	eh.pending = eh.loadPending()
+	return eh
 }
 
-// HandleEvent fetches refresh events and in case of an event retrieves and stores the latest topology
-// and starts a resharing tss process
+// HandleEvent fetches refresh events and in case of an event retrieves the latest topology
+// and pre-dials its peers. Topology is stored and resharing started only if enough parties
+// of the new topology are reachable, otherwise the refresh is postponed and retried on the
+// next block range until it succeeds or a new refresh event replaces it.
 func (eh *RefreshEventHandler) HandleEvent(startBlock *big.Int, endBlock *big.Int, msgChan chan []*message.Message) error {
 	refreshEvents, err := eh.eventListener.FetchRefreshEvents(context.Background(), eh.bridgeAddress, startBlock, endBlock)
 	if err != nil {
 		return fmt.Errorf("unable to fetch keygen events because of: %+v", err)
 	}
 	if len(refreshEvents) == 0 {
-		return nil
+		if eh.pending == nil {
+			return nil
+		}
+
+		return eh.refresh(eh.pending)
 	}
 
-	topology, err := eh.topologyProvider.NetworkTopology()
+	newTopology, err := eh.topologyProvider.NetworkTopology()
 	if err != nil {
 		return err
 	}
-	hash, err := topology.Hash()
+
+	// if multiple refresh events inside block range use latest
+	expectedHash := refreshEvents[len(refreshEvents)-1].Hash
+	err = newTopology.VerifyHash(expectedHash, eh.hashMode)
+	if err != nil {
+		return fmt.Errorf("aborting refresh because %w", err)
+	}
+
+	currentTopology, err := eh.topologyStore.Topology()
+	if err != nil {
+		log.Warn().Err(err).Msgf("Unable to read current topology")
+	}
+	diff := topology.NewTopologyDiff(currentTopology, newTopology)
+	log.Info().
+		Str("SessionID", eh.sessionID(startBlock)).
+		Str("hash", expectedHash).
+		Interface("added", diff.Added).
+		Interface("removed", diff.Removed).
+		Interface("readdressed", diff.Readdressed).
+		Int("oldThreshold", diff.OldThreshold).
+		Int("newThreshold", diff.NewThreshold).
+		Msgf("Topology changed by refresh")
+
+	eh.pending = &pendingRefresh{
+		sessionID: eh.sessionID(startBlock),
+		hash:      expectedHash,
+		current:   currentTopology,
+		topology:  newTopology,
+		diff:      diff,
+	}
+	return eh.refresh(eh.pending)
+}
+
+// refresh pre-dials parties of the new topology while connections to the current peers
+// are still allowed and starts resharing if enough of them are reachable.
+func (eh *RefreshEventHandler) refresh(refresh *pendingRefresh) error {
+	eh.connectionGate.SetTopology(topology.Union(refresh.current, refresh.topology))
+	peers := peer.IDSlice{}
+	reachable := 0
+	for _, p := range refresh.topology.Peers {
+		if p.ID == eh.host.ID() {
+			reachable++
+			continue
+		}
+
+		p2p.AddPeer(eh.host, p)
+		peers = append(peers, p.ID)
+	}
+
+	unreachablePeers := make(map[peer.ID]bool)
+	for _, e := range eh.healthChecker.CheckPeers(peers) {
+		unreachablePeers[e.Peer] = true
+	}
+	unreachable := []peer.ID{}
+	for _, p := range peers {
+		if unreachablePeers[p] {
+			unreachable = append(unreachable, p)
+			continue
+		}
+		reachable++
+	}
+
+	report := &RefreshReport{
+		Time:        time.Now(),
+		SessionID:   refresh.sessionID,
+		Hash:        refresh.hash,
+		Status:      RefreshStarted,
+		Diff:        refresh.diff,
+		Unreachable: unreachable,
+		Reachable:   reachable,
+		Required:    refresh.topology.Threshold + 1,
+	}
+	if report.Reachable < report.Required {
+		report.Status = RefreshPostponed
+		eh.setReport(report)
+		err := eh.storePending(refresh)
+		if err != nil {
+			log.Warn().Err(err).Str("SessionID", refresh.sessionID).Msgf("Unable to store postponed refresh")
+		}
+		return fmt.Errorf(
+			"postponing refresh %s because only %d of %d required parties are reachable, unreachable peers: %v",
+			refresh.sessionID, report.Reachable, report.Required, unreachable)
+	}
+
+	err := eh.storePending(nil)
 	if err != nil {
 		return err
 	}
-	err = eh.topologyStore.StoreTopology(topology)
+	eh.pending = nil
+	eh.setReport(report)
+	err = eh.topologyStore.StoreTopology(refresh.topology)
 	if err != nil {
 		return err
 	}
+	eh.connectionGate.SetTopology(refresh.topology)
+	p2p.LoadPeers(eh.host, refresh.topology.Peers)
 
-	// if multiple refresh events inside block range use latest
-	expectedHash := refreshEvents[len(refreshEvents)-1].Hash
-	if hash != expectedHash {
-		return fmt.Errorf("aborting refresh because expected hash %s doesn't match %s", expectedHash, hash)
+	err = eh.journal.RecordSession(refresh.sessionID, journal.ResharingProcess, nil)
+	if err != nil {
+		return err
 	}
-	eh.connectionGate.SetTopology(topology)
-	p2p.LoadPeers(eh.host, topology.Peers)
 
-	resharing := resharing.NewResharing(eh.sessionID(startBlock), topology.Threshold, eh.host, eh.communication, eh.storer)
-	go eh.coordinator.Execute(context.Background(), resharing, make(chan interface{}, 1), make(chan error, 1))
+	resharing := resharing.NewResharing(refresh.sessionID, refresh.topology.Threshold, eh.host, eh.communication, eh.storer)
+	go func() {
+		statusChn := make(chan error, 1)
+		eh.coordinator.Execute(context.Background(), resharing, make(chan interface{}, 1), statusChn)
+		if err := <-statusChn; err != nil || !sessionDone(eh.coordinator, resharing.SessionID()) {
+			return
+		}
+
+		// verify all peers adopted the new keyshare
+		eh.keyshareChecker.Trigger(resharing.SessionID())
+
+		// presignatures generated with the previous keyshare can not be used anymore
+		err := eh.presigner.Invalidate()
+		if err != nil {
+			log.Err(err).Str("SessionID", resharing.SessionID()).Msgf("Unable to invalidate presignatures")
+		}
+		eh.presigner.Fill(resharing.SessionID())
+	}()
 
 	return nil
 }
 
+// storePending persists the postponed refresh or removes the persisted refresh if it is nil.
+func (eh *RefreshEventHandler) storePending(refresh *pendingRefresh) error {
+	if eh.db == nil {
+		return nil
+	}
+	if refresh == nil {
+		// key value store does not support deletion
+		return eh.db.SetByKey(eh.pendingKey(), []byte{})
+	}
+
+	b, err := json.Marshal(storedRefresh{
+		SessionID: refresh.sessionID,
+		Hash:      refresh.hash,
+		Topology:  refresh.topology,
+	})
+	if err != nil {
+		return err
+	}
+	return eh.db.SetByKey(eh.pendingKey(), b)
+}
+
+// loadPending returns the refresh postponed before the restart or nil if there is none.
+func (eh *RefreshEventHandler) loadPending() *pendingRefresh {
+	if eh.db == nil {
+		return nil
+	}
+	b, err := eh.db.GetByKey(eh.pendingKey())
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+
+	stored := storedRefresh{}
+	err = json.Unmarshal(b, &stored)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Unable to read postponed refresh")
+		return nil
+	}
+	current := topology.NetworkTopology{}
+	if eh.topologyStore != nil {
+		current, err = eh.topologyStore.Topology()
+		if err != nil {
+			log.Warn().Err(err).Msgf("Unable to read current topology")
+		}
+	}
+
+	log.Info().Str("SessionID", stored.SessionID).Str("hash", stored.Hash).Msgf("Loaded postponed refresh")
+	return &pendingRefresh{
+		sessionID: stored.SessionID,
+		hash:      stored.Hash,
+		current:   current,
+		topology:  stored.Topology,
+		diff:      topology.NewTopologyDiff(current, stored.Topology),
+	}
+}
+
+func (eh *RefreshEventHandler) pendingKey() []byte {
+	return []byte(fmt.Sprintf("tss:refresh:pending:%d", eh.domainID))
+}
+
+func (eh *RefreshEventHandler) setReport(report *RefreshReport) {
+	eh.reportLock.Lock()
+	defer eh.reportLock.Unlock()
+
+	eh.report = report
+}
+
+// Report returns report of the latest refresh or nil if there was no refresh.
+func (eh *RefreshEventHandler) Report() *RefreshReport {
+	eh.reportLock.Lock()
+	defer eh.reportLock.Unlock()
+
+	return eh.report
+}
+
+// ServeHTTP returns the latest refresh report as JSON.
+func (eh *RefreshEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(eh.Report())
+}
+
 func (eh *RefreshEventHandler) sessionID(block *big.Int) string {
 	return fmt.Sprintf("resharing-%s", block.String())
 }
+
+// sessionDone checks if the session was successfully finished by the coordinator
+// and not ended because the same session was already pending.
+func sessionDone(coordinator *tss.Coordinator, sessionID string) bool {
+	session, ok := coordinator.Session(sessionID)
+	return ok && session.State == tss.SessionDone
+}
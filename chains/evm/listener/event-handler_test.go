@@ -4,21 +4,32 @@
 package listener_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/suite"
 
 	coreEvents "github.com/ChainSafe/chainbridge-core/chains/evm/calls/events"
+	"github.com/ChainSafe/chainbridge-core/lvldb"
 	"github.com/ChainSafe/chainbridge-core/relayer/message"
 	"github.com/ChainSafe/chainbridge-core/types"
 
 	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
 	"github.com/ChainSafe/sygma-relayer/chains/evm/listener"
 	mock_listener "github.com/ChainSafe/sygma-relayer/chains/evm/listener/mock"
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/topology"
 )
 
 type RetryEventHandlerTestSuite struct {
@@ -216,3 +227,182 @@ func (s *RetryEventHandlerTestSuite) Test_MultipleDeposits() {
 	s.Nil(err)
 	s.Equal(msgs, []*message.Message{{DepositNonce: 1}, {DepositNonce: 2}})
 }
+
+type RefreshEventHandlerTestSuite struct {
+	suite.Suite
+	refreshEventHandler *listener.RefreshEventHandler
+	mockEventListener   *mock_listener.MockEventListener
+	mockHealthChecker   *mock_listener.MockPeerHealthChecker
+	topologyStore       *topology.TopologyStore
+	topologyPath        string
+	db                  *lvldb.LVLDB
+	connectionGate      *p2p.ConnectionGate
+	currentTopology     topology.NetworkTopology
+	host                host.Host
+	newPeers            []*peer.AddrInfo
+	hash                string
+}
+
+func TestRunRefreshEventHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshEventHandlerTestSuite))
+}
+
+func (s *RefreshEventHandlerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockEventListener = mock_listener.NewMockEventListener(ctrl)
+	s.mockHealthChecker = mock_listener.NewMockPeerHealthChecker(ctrl)
+
+	privKey, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 0)
+	hostID, _ := peer.IDFromPrivateKey(privKey)
+	removedPeer, _ := peer.AddrInfoFromString("/dns4/relayer1/tcp/9000/p2p/QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX")
+	newAddresses := []string{
+		"/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT",
+		"/dns4/relayer3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK",
+	}
+	s.newPeers = []*peer.AddrInfo{}
+	for _, address := range newAddresses {
+		p, _ := peer.AddrInfoFromString(address)
+		s.newPeers = append(s.newPeers, p)
+	}
+
+	hostAddress := fmt.Sprintf("/ip4/127.0.0.1/tcp/9000/p2p/%s", hostID.Pretty())
+	hostPeer, _ := peer.AddrInfoFromString(hostAddress)
+	s.currentTopology = topology.NetworkTopology{Peers: []*peer.AddrInfo{hostPeer, removedPeer}, Threshold: 1}
+	s.topologyStore = topology.NewTopologyStore(fmt.Sprintf("%s/topology.json", s.T().TempDir()))
+	_ = s.topologyStore.StoreTopology(s.currentTopology)
+
+	rawTopology := fmt.Sprintf(`{"peers": [{"peerAddress": "%s"}, {"peerAddress": "%s"}, {"peerAddress": "%s"}], "threshold": "2"}`,
+		hostAddress, newAddresses[0], newAddresses[1])
+	s.topologyPath = fmt.Sprintf("%s/new-topology.json", s.T().TempDir())
+	_ = ioutil.WriteFile(s.topologyPath, []byte(rawTopology), 0600)
+	newTopology, _ := topology.ParseTopology([]byte(rawTopology))
+	s.hash, _ = newTopology.CanonicalHash()
+
+	s.db, _ = lvldb.NewLvlDB(s.T().TempDir())
+	s.connectionGate = p2p.NewConnectionGate(s.currentTopology)
+	s.host, _ = p2p.NewHost(privKey, s.currentTopology, s.connectionGate, 0)
+	s.refreshEventHandler = s.newRefreshEventHandler()
+}
+
+func (s *RefreshEventHandlerTestSuite) TearDownTest() {
+	_ = s.host.Close()
+	_ = s.db.Close()
+}
+
+func (s *RefreshEventHandlerTestSuite) newRefreshEventHandler() *listener.RefreshEventHandler {
+	return listener.NewRefreshEventHandler(
+		topology.NewFileTopologyProvider(s.topologyPath, nil, nil),
+		s.topologyStore,
+		"",
+		s.mockEventListener,
+		nil,
+		s.host,
+		nil,
+		s.connectionGate,
+		s.mockHealthChecker,
+		nil,
+		nil,
+		nil,
+		nil,
+		s.db,
+		common.Address{},
+		1,
+	)
+}
+
+func (s *RefreshEventHandlerTestSuite) Test_HandleEvent_NoRefreshEvents() {
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{}, nil)
+
+	err := s.refreshEventHandler.HandleEvent(big.NewInt(0), big.NewInt(5), make(chan []*message.Message, 1))
+
+	s.Nil(err)
+	s.Nil(s.refreshEventHandler.Report())
+}
+
+func (s *RefreshEventHandlerTestSuite) Test_HandleEvent_InvalidHash() {
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{{Hash: "invalid"}}, nil)
+
+	err := s.refreshEventHandler.HandleEvent(big.NewInt(0), big.NewInt(5), make(chan []*message.Message, 1))
+
+	s.NotNil(err)
+	s.Nil(s.refreshEventHandler.Report())
+}
+
+func (s *RefreshEventHandlerTestSuite) Test_HandleEvent_NewPartiesUnreachable_RefreshPostponed() {
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{{Hash: s.hash}}, nil)
+	s.mockHealthChecker.EXPECT().CheckPeers(peer.IDSlice{s.newPeers[0].ID, s.newPeers[1].ID}).Return([]*comm.CommunicationError{
+		{Peer: s.newPeers[1].ID, Err: fmt.Errorf("error")},
+	})
+
+	err := s.refreshEventHandler.HandleEvent(big.NewInt(0), big.NewInt(5), make(chan []*message.Message, 1))
+
+	s.NotNil(err)
+	report := s.refreshEventHandler.Report()
+	s.Equal(listener.RefreshPostponed, report.Status)
+	s.Equal("resharing-0", report.SessionID)
+	s.Equal([]peer.ID{s.newPeers[1].ID}, report.Unreachable)
+	s.Equal(2, report.Reachable)
+	s.Equal(3, report.Required)
+	s.Equal([]peer.ID{s.newPeers[0].ID, s.newPeers[1].ID}, report.Diff.Added)
+	s.Equal([]peer.ID{s.currentTopology.Peers[1].ID}, report.Diff.Removed)
+	storedTopology, _ := s.topologyStore.Topology()
+	s.Equal(s.currentTopology.Threshold, storedTopology.Threshold)
+}
+
+func (s *RefreshEventHandlerTestSuite) Test_HandleEvent_PostponedRefreshRetried() {
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{{Hash: s.hash}}, nil)
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{}, nil)
+	s.mockHealthChecker.EXPECT().CheckPeers(gomock.Any()).Return([]*comm.CommunicationError{
+		{Peer: s.newPeers[0].ID, Err: fmt.Errorf("error")},
+		{Peer: s.newPeers[1].ID, Err: fmt.Errorf("error")},
+	}).Times(2)
+
+	err := s.refreshEventHandler.HandleEvent(big.NewInt(0), big.NewInt(5), make(chan []*message.Message, 1))
+	s.NotNil(err)
+	err = s.refreshEventHandler.HandleEvent(big.NewInt(5), big.NewInt(10), make(chan []*message.Message, 1))
+	s.NotNil(err)
+
+	report := s.refreshEventHandler.Report()
+	s.Equal(listener.RefreshPostponed, report.Status)
+	s.Equal("resharing-0", report.SessionID)
+	s.Equal(1, report.Reachable)
+}
+
+func (s *RefreshEventHandlerTestSuite) Test_NewRefreshEventHandler_PostponedRefreshRetriedAfterRestart() {
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{{Hash: s.hash}}, nil)
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{}, nil)
+	s.mockHealthChecker.EXPECT().CheckPeers(gomock.Any()).Return([]*comm.CommunicationError{
+		{Peer: s.newPeers[0].ID, Err: fmt.Errorf("error")},
+		{Peer: s.newPeers[1].ID, Err: fmt.Errorf("error")},
+	}).Times(2)
+
+	err := s.refreshEventHandler.HandleEvent(big.NewInt(0), big.NewInt(5), make(chan []*message.Message, 1))
+	s.NotNil(err)
+	restartedHandler := s.newRefreshEventHandler()
+	err = restartedHandler.HandleEvent(big.NewInt(5), big.NewInt(10), make(chan []*message.Message, 1))
+	s.NotNil(err)
+
+	report := restartedHandler.Report()
+	s.Equal(listener.RefreshPostponed, report.Status)
+	s.Equal("resharing-0", report.SessionID)
+	s.Equal(s.hash, report.Hash)
+	s.ElementsMatch([]peer.ID{s.newPeers[0].ID, s.newPeers[1].ID}, report.Diff.Added)
+}
+
+func (s *RefreshEventHandlerTestSuite) Test_ServeHTTP() {
+	s.mockEventListener.EXPECT().FetchRefreshEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Refresh{{Hash: s.hash}}, nil)
+	s.mockHealthChecker.EXPECT().CheckPeers(gomock.Any()).Return([]*comm.CommunicationError{
+		{Peer: s.newPeers[1].ID, Err: fmt.Errorf("error")},
+	})
+	_ = s.refreshEventHandler.HandleEvent(big.NewInt(0), big.NewInt(5), make(chan []*message.Message, 1))
+
+	recorder := httptest.NewRecorder()
+	s.refreshEventHandler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/refresh/1", nil))
+
+	report := &listener.RefreshReport{}
+	err := json.Unmarshal(recorder.Body.Bytes(), report)
+	s.Nil(err)
+	s.Equal(listener.RefreshPostponed, report.Status)
+	s.Equal(s.hash, report.Hash)
+	s.Equal([]peer.ID{s.newPeers[1].ID}, report.Unreachable)
+}
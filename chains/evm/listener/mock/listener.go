@@ -10,10 +10,14 @@ import (
 	reflect "reflect"
 
 	events "github.com/ChainSafe/chainbridge-core/chains/evm/calls/events"
+	proposal "github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
 	events0 "github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+	journal "github.com/ChainSafe/sygma-relayer/tss/journal"
 	common "github.com/ethereum/go-ethereum/common"
 	types "github.com/ethereum/go-ethereum/core/types"
 	gomock "github.com/golang/mock/gomock"
+	peer "github.com/libp2p/go-libp2p-core/peer"
 )
 
 // MockEventListener is a mock of EventListener interface.
@@ -98,3 +102,161 @@ func (mr *MockEventListenerMockRecorder) FetchRetryEvents(ctx, contractAddress,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchRetryEvents", reflect.TypeOf((*MockEventListener)(nil).FetchRetryEvents), ctx, contractAddress, startBlock, endBlock)
 }
+
+// MockSessionJournal is a mock of SessionJournal interface.
+type MockSessionJournal struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionJournalMockRecorder
+}
+
+// MockSessionJournalMockRecorder is the mock recorder for MockSessionJournal.
+type MockSessionJournalMockRecorder struct {
+	mock *MockSessionJournal
+}
+
+// NewMockSessionJournal creates a new mock instance.
+func NewMockSessionJournal(ctrl *gomock.Controller) *MockSessionJournal {
+	mock := &MockSessionJournal{ctrl: ctrl}
+	mock.recorder = &MockSessionJournalMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionJournal) EXPECT() *MockSessionJournalMockRecorder {
+	return m.recorder
+}
+
+// RecordSession mocks base method.
+func (m *MockSessionJournal) RecordSession(sessionID string, process journal.ProcessType, proposals []*proposal.Proposal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSession", sessionID, process, proposals)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSession indicates an expected call of RecordSession.
+func (mr *MockSessionJournalMockRecorder) RecordSession(sessionID, process, proposals interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSession", reflect.TypeOf((*MockSessionJournal)(nil).RecordSession), sessionID, process, proposals)
+}
+
+// MockPresigner is a mock of Presigner interface.
+type MockPresigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresignerMockRecorder
+}
+
+// MockPresignerMockRecorder is the mock recorder for MockPresigner.
+type MockPresignerMockRecorder struct {
+	mock *MockPresigner
+}
+
+// NewMockPresigner creates a new mock instance.
+func NewMockPresigner(ctrl *gomock.Controller) *MockPresigner {
+	mock := &MockPresigner{ctrl: ctrl}
+	mock.recorder = &MockPresignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresigner) EXPECT() *MockPresignerMockRecorder {
+	return m.recorder
+}
+
+// Fill mocks base method.
+func (m *MockPresigner) Fill(sessionID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Fill", sessionID)
+}
+
+// Fill indicates an expected call of Fill.
+func (mr *MockPresignerMockRecorder) Fill(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fill", reflect.TypeOf((*MockPresigner)(nil).Fill), sessionID)
+}
+
+// Invalidate mocks base method.
+func (m *MockPresigner) Invalidate() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invalidate")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockPresignerMockRecorder) Invalidate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockPresigner)(nil).Invalidate))
+}
+
+// MockKeyshareChecker is a mock of KeyshareChecker interface.
+type MockKeyshareChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyshareCheckerMockRecorder
+}
+
+// MockKeyshareCheckerMockRecorder is the mock recorder for MockKeyshareChecker.
+type MockKeyshareCheckerMockRecorder struct {
+	mock *MockKeyshareChecker
+}
+
+// NewMockKeyshareChecker creates a new mock instance.
+func NewMockKeyshareChecker(ctrl *gomock.Controller) *MockKeyshareChecker {
+	mock := &MockKeyshareChecker{ctrl: ctrl}
+	mock.recorder = &MockKeyshareCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyshareChecker) EXPECT() *MockKeyshareCheckerMockRecorder {
+	return m.recorder
+}
+
+// Trigger mocks base method.
+func (m *MockKeyshareChecker) Trigger(sessionID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Trigger", sessionID)
+}
+
+// Trigger indicates an expected call of Trigger.
+func (mr *MockKeyshareCheckerMockRecorder) Trigger(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trigger", reflect.TypeOf((*MockKeyshareChecker)(nil).Trigger), sessionID)
+}
+
+// MockPeerHealthChecker is a mock of PeerHealthChecker interface.
+type MockPeerHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeerHealthCheckerMockRecorder
+}
+
+// MockPeerHealthCheckerMockRecorder is the mock recorder for MockPeerHealthChecker.
+type MockPeerHealthCheckerMockRecorder struct {
+	mock *MockPeerHealthChecker
+}
+
+// NewMockPeerHealthChecker creates a new mock instance.
+func NewMockPeerHealthChecker(ctrl *gomock.Controller) *MockPeerHealthChecker {
+	mock := &MockPeerHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockPeerHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPeerHealthChecker) EXPECT() *MockPeerHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckPeers mocks base method.
+func (m *MockPeerHealthChecker) CheckPeers(peers peer.IDSlice) []*comm.CommunicationError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPeers", peers)
+	ret0, _ := ret[0].([]*comm.CommunicationError)
+	return ret0
+}
+
+// CheckPeers indicates an expected call of CheckPeers.
+func (mr *MockPeerHealthCheckerMockRecorder) CheckPeers(peers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPeers", reflect.TypeOf((*MockPeerHealthChecker)(nil).CheckPeers), peers)
+}
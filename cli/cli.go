@@ -24,7 +24,7 @@ func init() {
 }
 
 func Execute() {
-	rootCMD.AddCommand(runCMD, peerInfoCMD)
+	rootCMD.AddCommand(runCMD, peerInfoCMD, devnetCMD, keyshareCMD, topologyCMD)
 	if err := rootCMD.Execute(); err != nil {
 		log.Fatal().Err(err).Msg("failed to execute root cmd")
 	}
@@ -0,0 +1,95 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-core/logger"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/devnet"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	devnetCMD = &cobra.Command{
+		Use:   "devnet",
+		Short: "Run relayers on simulated chains in a single process",
+		Long:  "Run relayers on simulated chains in a single process with in-memory communication and send deposits between chains",
+		RunE:  runDevnet,
+	}
+)
+
+var (
+	devnetConfig = devnet.Config{
+		BullyConfig: relayer.BullyConfig{
+			PingWaitTime:     1 * time.Second,
+			PingBackOff:      1 * time.Second,
+			PingInterval:     1 * time.Second,
+			ElectionWaitTime: 2 * time.Second,
+			BullyWaitTime:    25 * time.Second,
+		},
+	}
+	devnetLogLevel string
+)
+
+func init() {
+	devnetCMD.Flags().IntVar(&devnetConfig.Relayers, "relayers", 3, "number of relayers")
+	devnetCMD.Flags().IntVar(&devnetConfig.Domains, "domains", 2, "number of simulated chains")
+	devnetCMD.Flags().IntVar(&devnetConfig.Deposits, "deposits", 1, "number of deposits sent from the first to the second domain")
+	devnetCMD.Flags().IntVar(&devnetConfig.Threshold, "threshold", 1, "MPC threshold")
+	devnetCMD.Flags().BoolVar(&devnetConfig.Keygen, "keygen", false, "run keygen instead of using test keyshares")
+	devnetCMD.Flags().StringVar(&devnetConfig.FixturesDir, "fixtures", "./tss/test", "directory with test libp2p keys and keyshares")
+	devnetCMD.Flags().DurationVar(&devnetConfig.BlockTime, "block-time", time.Second, "block time of simulated chains")
+	devnetCMD.Flags().IntVar(&devnetConfig.PresignaturePoolSize, "presignatures", 0, "presignature pool size")
+	devnetCMD.Flags().StringVar(&devnetLogLevel, "log-level", "info", "log level")
+}
+
+func runDevnet(cmd *cobra.Command, args []string) error {
+	level, err := zerolog.ParseLevel(devnetLogLevel)
+	if err != nil {
+		return err
+	}
+	logger.ConfigureLogger(level, os.Stdout)
+
+	d, err := devnet.NewDevnet(devnetConfig)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	errChn := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		err := d.Start(ctx, errChn)
+		if err != nil {
+			errChn <- err
+			return
+		}
+		log.Info().Msg("All deposits executed")
+	}()
+
+	sysErr := make(chan os.Signal, 1)
+	signal.Notify(sysErr,
+		syscall.SIGTERM,
+		syscall.SIGINT,
+		syscall.SIGHUP,
+		syscall.SIGQUIT)
+
+	select {
+	case err := <-errChn:
+		log.Error().Err(err).Msg("devnet failed")
+		return err
+	case sig := <-sysErr:
+		log.Info().Msgf("terminating got ` [%v] signal", sig)
+		return nil
+	}
+}
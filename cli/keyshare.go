@@ -0,0 +1,488 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-core/lvldb"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/ChainSafe/sygma-relayer/config"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+)
+
+const (
+	keysharePassphraseEnv     = config.EnvPrefix + "_KEYSHARE_PASSPHRASE"
+	keyshareNewPassphraseEnv  = config.EnvPrefix + "_KEYSHARE_NEW_PASSPHRASE"
+	keyshareFilePassphraseEnv = config.EnvPrefix + "_KEYSHARE_FILE_PASSPHRASE"
+)
+
+var (
+	keyshareCMD = &cobra.Command{
+		Use:   "keyshare",
+		Short: "Manage relayer keyshares",
+		Long:  "Manage relayer keyshares",
+	}
+	keyshareReencryptCMD = &cobra.Command{
+		Use:   "reencrypt",
+		Short: "Re-encrypt keyshare file with a new key",
+		Long: "Re-encrypt keyshare file with a new passphrase or key file. Plaintext keyshare files are encrypted " +
+			"if no current key is provided and encrypted keyshare files are decrypted if no new key is provided.",
+		RunE: reencryptKeyshare,
+	}
+	keyshareVersionsCMD = &cobra.Command{
+		Use:   "versions",
+		Short: "List stored keyshare versions",
+		Long:  "List stored keyshare versions",
+		RunE:  listKeyshareVersions,
+	}
+	keyshareActivateCMD = &cobra.Command{
+		Use:   "activate",
+		Short: "Activate stored keyshare version",
+		Long:  "Activate stored keyshare version. Relayer should be stopped while the keyshare is changed.",
+		RunE:  activateKeyshareVersion,
+	}
+	keysharePruneCMD = &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old keyshare versions",
+		Long:  "Remove all but the newest keyshare versions. Active keyshare version is never removed.",
+		RunE:  pruneKeyshareVersions,
+	}
+	keyshareInspectCMD = &cobra.Command{
+		Use:   "inspect",
+		Short: "Show public data of the active keyshare",
+		Long:  "Show MPC public key, address, threshold, peers and party index of the active keyshare",
+		RunE:  inspectKeyshare,
+	}
+	keyshareVerifyCMD = &cobra.Command{
+		Use:   "verify",
+		Short: "Verify active keyshare against the expected MPC address",
+		Long: "Verify that the active keyshare is valid and that it is a share of the MPC key with the expected address. " +
+			"EdDSA keyshares are verified against the hex encoded MPC public key.",
+		RunE: verifyKeyshare,
+	}
+	keyshareExportCMD = &cobra.Command{
+		Use:   "export",
+		Short: "Export active keyshare to a file",
+		Long:  "Export active keyshare to a file. Export is encrypted if export passphrase or key file is provided.",
+		RunE:  exportKeyshare,
+	}
+	keyshareImportCMD = &cobra.Command{
+		Use:   "import",
+		Short: "Import keyshare from an export file",
+		Long: "Import keyshare from an export file as the new active keyshare version. Export checksum and keyshare are " +
+			"verified before the keyshare is stored. Relayer should be stopped while the keyshare is changed.",
+		RunE: importKeyshare,
+	}
+)
+
+var (
+	keyshareBackend        string
+	keysharePath           string
+	keyshareDBPath         string
+	keyshareRemoteURL      string
+	keyshareRemoteToken    string
+	keysharePassphrase     string
+	keyshareKeyFile        string
+	keyshareNewPassphrase  string
+	keyshareNewKeyFile     string
+	keyshareCurve          string
+	keyshareGeneration     int
+	keyshareKeep           int
+	keyshareAddress        string
+	keyshareFile           string
+	keyshareFilePassphrase string
+	keyshareFileKeyFile    string
+
+	keysharePromptPassphrase     bool
+	keysharePromptNewPassphrase  bool
+	keysharePromptFilePassphrase bool
+)
+
+func init() {
+	keyshareCMD.PersistentFlags().StringVar(&keyshareBackend, "backend", keyshare.FileBackendType, "keyshare backend (file, lvldb or remote)")
+	keyshareCMD.PersistentFlags().StringVar(&keysharePath, "path", "", "path to the keyshare file")
+	keyshareCMD.PersistentFlags().StringVar(&keyshareDBPath, "db", "", "path to the relayer blockstore for the lvldb backend")
+	keyshareCMD.PersistentFlags().StringVar(&keyshareRemoteURL, "remote-url", "", "keyshare URL for the remote backend")
+	keyshareCMD.PersistentFlags().StringVar(&keyshareRemoteToken, "remote-token", "", "bearer token for the remote backend")
+	keyshareCMD.PersistentFlags().StringVar(&keysharePassphrase, "passphrase", "", fmt.Sprintf("current keyshare passphrase, visible to other users of the host, prefer %s or --prompt-passphrase", keysharePassphraseEnv))
+	keyshareCMD.PersistentFlags().BoolVar(&keysharePromptPassphrase, "prompt-passphrase", false, "read current keyshare passphrase from the terminal")
+	keyshareCMD.PersistentFlags().StringVar(&keyshareKeyFile, "key-file", "", "current keyshare key file")
+	keyshareCMD.PersistentFlags().StringVar(&keyshareCurve, "curve", "ecdsa", "keyshare curve (ecdsa or eddsa)")
+
+	keyshareReencryptCMD.Flags().StringVar(&keyshareNewPassphrase, "new-passphrase", "", fmt.Sprintf("new keyshare passphrase, visible to other users of the host, prefer %s or --prompt-new-passphrase", keyshareNewPassphraseEnv))
+	keyshareReencryptCMD.Flags().BoolVar(&keysharePromptNewPassphrase, "prompt-new-passphrase", false, "read new keyshare passphrase from the terminal")
+	keyshareReencryptCMD.Flags().StringVar(&keyshareNewKeyFile, "new-key-file", "", "new keyshare key file")
+
+	keyshareActivateCMD.Flags().IntVar(&keyshareGeneration, "generation", 0, "keyshare generation to activate")
+	_ = keyshareActivateCMD.MarkFlagRequired("generation")
+	keysharePruneCMD.Flags().IntVar(&keyshareKeep, "keep", 2, "number of newest keyshare versions to keep")
+
+	keyshareVerifyCMD.Flags().StringVar(&keyshareAddress, "address", "", "expected MPC address")
+	_ = keyshareVerifyCMD.MarkFlagRequired("address")
+	keyshareImportCMD.Flags().StringVar(&keyshareAddress, "address", "", "expected MPC address of the imported keyshare")
+	for _, cmd := range []*cobra.Command{keyshareExportCMD, keyshareImportCMD} {
+		cmd.Flags().StringVar(&keyshareFile, "file", "", "path to the keyshare export file")
+		cmd.Flags().StringVar(&keyshareFilePassphrase, "file-passphrase", "", fmt.Sprintf("export file passphrase, visible to other users of the host, prefer %s or --prompt-file-passphrase", keyshareFilePassphraseEnv))
+		cmd.Flags().BoolVar(&keysharePromptFilePassphrase, "prompt-file-passphrase", false, "read export file passphrase from the terminal")
+		cmd.Flags().StringVar(&keyshareFileKeyFile, "file-key-file", "", "export file key file")
+		_ = cmd.MarkFlagRequired("file")
+	}
+
+	keyshareCMD.AddCommand(
+		keyshareReencryptCMD, keyshareVersionsCMD, keyshareActivateCMD, keysharePruneCMD,
+		keyshareInspectCMD, keyshareVerifyCMD, keyshareExportCMD, keyshareImportCMD,
+	)
+}
+
+type curveKeyshareStore interface {
+	Versions() ([]keyshare.Version, error)
+	ActivateVersion(generation int) error
+	PruneVersions(keep int) ([]int, error)
+	Info() (keyshare.Info, error)
+	Verify(address string) error
+	Export(encryption *keyshare.Encryption) ([]byte, error)
+}
+
+// keyshareStore creates keyshare store for the configured backend. Returned function
+// closes resources opened by the backend.
+func keyshareStore(encryption *keyshare.Encryption) (*keyshare.KeyshareStore, func(), error) {
+	closer := func() {}
+	var db keyshare.KeyValueReaderWriter
+	switch keyshareBackend {
+	case keyshare.FileBackendType:
+		if keysharePath == "" {
+			return nil, closer, fmt.Errorf("keyshare path not provided")
+		}
+	case keyshare.LvlDBBackendType:
+		if keyshareDBPath == "" {
+			return nil, closer, fmt.Errorf("blockstore path not provided")
+		}
+		lvlDB, err := lvldb.NewLvlDB(keyshareDBPath)
+		if err != nil {
+			return nil, closer, err
+		}
+		db = lvlDB
+		closer = func() { _ = lvlDB.Close() }
+	}
+
+	backend, err := keyshare.NewBackend(keyshareBackend, keysharePath, db, keyshareRemoteURL, keyshareRemoteToken)
+	if err != nil {
+		closer()
+		return nil, func() {}, err
+	}
+	return keyshare.NewKeyshareStoreWithBackend(backend, encryption), closer, nil
+}
+
+// curveStore creates keyshare store for the curve selected with the curve flag.
+func curveStore() (curveKeyshareStore, func(), error) {
+	encryption, err := currentEncryption()
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	store, closer, err := keyshareStore(encryption)
+	if err != nil {
+		return nil, closer, err
+	}
+	switch keyshareCurve {
+	case "ecdsa":
+		return store, closer, nil
+	case "eddsa":
+		return store.EdDSAStore(), closer, nil
+	default:
+		closer()
+		return nil, func() {}, fmt.Errorf("unsupported keyshare curve %s", keyshareCurve)
+	}
+}
+
+func listKeyshareVersions(cmd *cobra.Command, args []string) error {
+	store, closer, err := curveStore()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	versions, err := store.Versions()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GENERATION\tACTIVE\tTHRESHOLD\tPEERS\tADDRESS\tCREATED\tSESSION")
+	for _, v := range versions {
+		created := "-"
+		if !v.CreatedAt.IsZero() {
+			created = v.CreatedAt.Format(time.RFC3339)
+		}
+		peers := make([]string, len(v.Peers))
+		for i, p := range v.Peers {
+			peers[i] = p.Pretty()
+		}
+		fmt.Fprintf(w, "%d\t%t\t%d\t%s\t%s\t%s\t%s\n", v.Generation, v.Active, v.Threshold, strings.Join(peers, ","), v.Address, created, v.SessionID)
+	}
+	return w.Flush()
+}
+
+func activateKeyshareVersion(cmd *cobra.Command, args []string) error {
+	store, closer, err := curveStore()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	err = store.ActivateVersion(keyshareGeneration)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Keyshare generation %d activated\n", keyshareGeneration)
+	return nil
+}
+
+func pruneKeyshareVersions(cmd *cobra.Command, args []string) error {
+	store, closer, err := curveStore()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	pruned, err := store.PruneVersions(keyshareKeep)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed keyshare generations %v\n", pruned)
+	return nil
+}
+
+func reencryptKeyshare(cmd *cobra.Command, args []string) error {
+	encryption, err := currentEncryption()
+	if err != nil {
+		return err
+	}
+
+	newPassphrase, err := passphrase(
+		"New keyshare passphrase", keyshareNewPassphrase, keyshareNewPassphraseEnv, keysharePromptNewPassphrase, true,
+	)
+	if err != nil {
+		return err
+	}
+	newEncryption, err := keyshare.NewEncryption(newPassphrase, keyshareNewKeyFile)
+	if err != nil {
+		return err
+	}
+
+	store, closer, err := keyshareStore(encryption)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	err = store.Reencrypt(newEncryption)
+	if err != nil {
+		return fmt.Errorf("unable to re-encrypt keyshare: %w", err)
+	}
+
+	if newEncryption == nil {
+		fmt.Println("Keyshare decrypted")
+	} else {
+		fmt.Println("Keyshare re-encrypted")
+	}
+	return nil
+}
+
+func inspectKeyshare(cmd *cobra.Command, args []string) error {
+	store, closer, err := curveStore()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	info, err := store.Info()
+	if err != nil {
+		return err
+	}
+
+	peers := make([]string, len(info.Peers))
+	for i, p := range info.Peers {
+		peers[i] = p.Pretty()
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Curve:\t%s\n", info.Curve)
+	fmt.Fprintf(w, "Public key:\t%s\n", info.PublicKey)
+	fmt.Fprintf(w, "Address:\t%s\n", info.Address)
+	fmt.Fprintf(w, "Threshold:\t%d\n", info.Threshold)
+	fmt.Fprintf(w, "Peers:\t%s\n", strings.Join(peers, ","))
+	fmt.Fprintf(w, "Party index:\t%d\n", info.PartyIndex)
+	fmt.Fprintf(w, "Generation:\t%d\n", info.Generation)
+	return w.Flush()
+}
+
+func verifyKeyshare(cmd *cobra.Command, args []string) error {
+	store, closer, err := curveStore()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	err = store.Verify(keyshareAddress)
+	if err != nil {
+		return fmt.Errorf("keyshare verification failed: %w", err)
+	}
+
+	fmt.Printf("Keyshare is valid for MPC address %s\n", keyshareAddress)
+	return nil
+}
+
+func exportKeyshare(cmd *cobra.Command, args []string) error {
+	store, closer, err := curveStore()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	encryption, err := exportFileEncryption(true)
+	if err != nil {
+		return err
+	}
+	data, err := store.Export(encryption)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(keyshareFile, data, 0600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Keyshare exported to %s\n", keyshareFile)
+	return nil
+}
+
+func importKeyshare(cmd *cobra.Command, args []string) error {
+	data, err := ioutil.ReadFile(keyshareFile)
+	if err != nil {
+		return err
+	}
+	fileEncryption, err := exportFileEncryption(false)
+	if err != nil {
+		return err
+	}
+	encryption, err := currentEncryption()
+	if err != nil {
+		return err
+	}
+
+	store, closer, err := keyshareStore(encryption)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	var info keyshare.Info
+	switch keyshareCurve {
+	case "ecdsa":
+		_, err = store.Import(data, fileEncryption, keyshareAddress)
+		if err != nil {
+			return fmt.Errorf("unable to import keyshare: %w", err)
+		}
+		info, err = store.Info()
+	case "eddsa":
+		_, err = store.EdDSAStore().Import(data, fileEncryption, keyshareAddress)
+		if err != nil {
+			return fmt.Errorf("unable to import keyshare: %w", err)
+		}
+		info, err = store.EdDSAStore().Info()
+	default:
+		return fmt.Errorf("unsupported keyshare curve %s", keyshareCurve)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Keyshare for MPC address %s imported as generation %d\n", info.Address, info.Generation)
+	return nil
+}
+
+// currentEncryption creates encryption of the stored keyshare from the current
+// passphrase or key file.
+func currentEncryption() (*keyshare.Encryption, error) {
+	currentPassphrase, err := passphrase(
+		"Keyshare passphrase", keysharePassphrase, keysharePassphraseEnv, keysharePromptPassphrase, false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyshare.NewEncryption(currentPassphrase, keyshareKeyFile)
+}
+
+// exportFileEncryption creates encryption of the keyshare export file from the export
+// file passphrase or key file.
+func exportFileEncryption(confirm bool) (*keyshare.Encryption, error) {
+	filePassphrase, err := passphrase(
+		"Export file passphrase", keyshareFilePassphrase, keyshareFilePassphraseEnv, keysharePromptFilePassphrase, confirm,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyshare.NewEncryption(filePassphrase, keyshareFileKeyFile)
+}
+
+// passphrase reads the passphrase from the terminal if prompt is set, otherwise
+// the passphrase flag value is used, falling back to the environment variable.
+// Passphrases read from the terminal are entered twice if confirm is set.
+func passphrase(name, flagValue, env string, prompt, confirm bool) (string, error) {
+	if !prompt {
+		if flagValue != "" {
+			return flagValue, nil
+		}
+		return os.Getenv(env), nil
+	}
+	if flagValue != "" {
+		return "", fmt.Errorf("%s provided with both a flag and a prompt", strings.ToLower(name))
+	}
+
+	value, err := readPassword(name + ": ")
+	if err != nil {
+		return "", err
+	}
+	if !confirm {
+		return value, nil
+	}
+
+	confirmation, err := readPassword("Repeat " + strings.ToLower(name) + ": ")
+	if err != nil {
+		return "", err
+	}
+	if value != confirmation {
+		return "", errors.New("passphrases do not match")
+	}
+	return value, nil
+}
+
+func readPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("passphrase prompt requires a terminal")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("unable to read passphrase: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,269 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/ChainSafe/sygma-relayer/topology"
+)
+
+var (
+	topologyCMD = &cobra.Command{
+		Use:   "topology",
+		Short: "Create and encrypt network topology documents",
+		Long:  "Create and encrypt network topology documents",
+	}
+	topologyCreateCMD = &cobra.Command{
+		Use:   "create",
+		Short: "Create plaintext topology document",
+		Long:  "Create plaintext topology document from peer addresses and threshold",
+		RunE:  createTopology,
+	}
+	topologyEncryptCMD = &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt topology document",
+		Long:  "Validate and encrypt plaintext topology document the way relayers decrypt it",
+		RunE:  encryptTopology,
+	}
+	topologyDecryptCMD = &cobra.Command{
+		Use:   "decrypt",
+		Short: "Decrypt topology document",
+		Long:  "Decrypt topology document",
+		RunE:  decryptTopology,
+	}
+	topologyHashCMD = &cobra.Command{
+		Use:   "hash",
+		Short: "Calculate topology hash",
+		Long: "Calculate canonical topology hash that has to be used in the refresh event. " +
+			"Topology is decrypted first if the encryption key is provided.",
+		RunE: hashTopology,
+	}
+	topologySignCMD = &cobra.Command{
+		Use:   "sign",
+		Short: "Sign topology document",
+		Long: "Sign plaintext topology document with the admin key and add the signature to the signatures document " +
+			"that has to be uploaded next to the topology document with the .sig suffix. " +
+			"Topology is decrypted first if the encryption key is provided.",
+		RunE: signTopology,
+	}
+)
+
+var (
+	topologyPeers         []string
+	topologyThreshold     int
+	topologyIn            string
+	topologyOut           string
+	topologyEncryptionKey string
+	topologyLegacyHash    bool
+	topologyAdminKeyFile  string
+)
+
+func init() {
+	topologyCreateCMD.Flags().StringSliceVar(&topologyPeers, "peer", []string{}, "peer address, can be repeated and addresses with the same peer ID are added as additional peer addresses")
+	topologyCreateCMD.Flags().IntVar(&topologyThreshold, "threshold", 0, "MPC threshold")
+	_ = topologyCreateCMD.MarkFlagRequired("peer")
+	_ = topologyCreateCMD.MarkFlagRequired("threshold")
+
+	for _, cmd := range []*cobra.Command{topologyEncryptCMD, topologyDecryptCMD, topologyHashCMD, topologySignCMD} {
+		cmd.Flags().StringVar(&topologyIn, "in", "", "path to the topology document")
+		_ = cmd.MarkFlagRequired("in")
+		cmd.Flags().StringVar(&topologyEncryptionKey, "encryption-key", "", "topology encryption key")
+	}
+	topologyHashCMD.Flags().BoolVar(&topologyLegacyHash, "legacy", false, "calculate legacy topology hash")
+	_ = topologyEncryptCMD.MarkFlagRequired("encryption-key")
+	_ = topologyDecryptCMD.MarkFlagRequired("encryption-key")
+	topologySignCMD.Flags().StringVar(&topologyAdminKeyFile, "key-file", "", "path to the file with hex encoded admin private key")
+	_ = topologySignCMD.MarkFlagRequired("key-file")
+
+	for _, cmd := range []*cobra.Command{topologyCreateCMD, topologyEncryptCMD, topologyDecryptCMD} {
+		cmd.Flags().StringVar(&topologyOut, "out", "", "output path, printed to stdout if not provided")
+	}
+	topologySignCMD.Flags().StringVar(&topologyOut, "out", "", "signatures document path, signature is added to existing signatures in the document")
+	_ = topologySignCMD.MarkFlagRequired("out")
+
+	topologyCMD.AddCommand(topologyCreateCMD, topologyEncryptCMD, topologyDecryptCMD, topologyHashCMD, topologySignCMD)
+}
+
+func createTopology(cmd *cobra.Command, args []string) error {
+	rawTopology := topology.RawTopology{
+		Threshold: strconv.Itoa(topologyThreshold),
+	}
+	// addresses of the same peer are grouped as additional peer addresses
+	peerIndexes := make(map[peer.ID]int)
+	for _, p := range topologyPeers {
+		addrInfo, err := peer.AddrInfoFromString(p)
+		if err != nil {
+			return fmt.Errorf("invalid peer address %s: %w", p, err)
+		}
+
+		i, ok := peerIndexes[addrInfo.ID]
+		if ok {
+			rawTopology.Peers[i].PeerAddresses = append(rawTopology.Peers[i].PeerAddresses, p)
+			continue
+		}
+		peerIndexes[addrInfo.ID] = len(rawTopology.Peers)
+		rawTopology.Peers = append(rawTopology.Peers, topology.RawPeer{PeerAddress: p})
+	}
+
+	networkTopology, err := topology.ProcessRawTopology(&rawTopology)
+	if err != nil {
+		return err
+	}
+	hash, err := networkTopology.CanonicalHash()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(rawTopology, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = writeTopology(data)
+	if err != nil {
+		return err
+	}
+
+	if topologyOut != "" {
+		fmt.Printf("Topology written to %s with hash %s\n", topologyOut, hash)
+	}
+	return nil
+}
+
+func encryptTopology(cmd *cobra.Command, args []string) error {
+	data, err := ioutil.ReadFile(topologyIn)
+	if err != nil {
+		return err
+	}
+	_, err = topology.ParseTopology(data)
+	if err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
+
+	encryption, err := topology.NewAESEncryption([]byte(topologyEncryptionKey))
+	if err != nil {
+		return err
+	}
+	encryptedData, err := encryption.Encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	return writeTopology([]byte(encryptedData))
+}
+
+func decryptTopology(cmd *cobra.Command, args []string) error {
+	data, err := readTopology()
+	if err != nil {
+		return err
+	}
+
+	return writeTopology(data)
+}
+
+func hashTopology(cmd *cobra.Command, args []string) error {
+	data, err := readTopology()
+	if err != nil {
+		return err
+	}
+
+	networkTopology, err := topology.ParseTopology(data)
+	if err != nil {
+		return err
+	}
+	hashFn := networkTopology.CanonicalHash
+	if topologyLegacyHash {
+		hashFn = networkTopology.Hash
+	}
+	hash, err := hashFn()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(hash)
+	return nil
+}
+
+func signTopology(cmd *cobra.Command, args []string) error {
+	data, err := readTopology()
+	if err != nil {
+		return err
+	}
+	_, err = topology.ParseTopology(data)
+	if err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
+
+	key, err := crypto.LoadECDSA(topologyAdminKeyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load admin key: %w", err)
+	}
+	signature, err := topology.Sign(data, key)
+	if err != nil {
+		return err
+	}
+
+	signatures := []string{}
+	existing, err := ioutil.ReadFile(topologyOut)
+	if err == nil {
+		err = json.Unmarshal(existing, &signatures)
+		if err != nil {
+			return fmt.Errorf("invalid signatures document %s: %w", topologyOut, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if !slices.Contains(signatures, signature) {
+		signatures = append(signatures, signature)
+	}
+
+	signaturesData, err := json.MarshalIndent(signatures, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(topologyOut, signaturesData, 0600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Topology signed by %s, %s contains %d signatures\n", crypto.PubkeyToAddress(key.PublicKey), topologyOut, len(signatures))
+	return nil
+}
+
+// readTopology reads the input topology document and decrypts it
+// if the encryption key is provided.
+func readTopology() ([]byte, error) {
+	data, err := ioutil.ReadFile(topologyIn)
+	if err != nil {
+		return nil, err
+	}
+	if topologyEncryptionKey == "" {
+		return data, nil
+	}
+
+	encryption, err := topology.NewAESEncryption([]byte(topologyEncryptionKey))
+	if err != nil {
+		return nil, err
+	}
+	return encryption.Decrypt(strings.TrimSpace(string(data)))
+}
+
+func writeTopology(data []byte) error {
+	if topologyOut == "" {
+		_, err := fmt.Fprintln(os.Stdout, string(data))
+		return err
+	}
+
+	return ioutil.WriteFile(topologyOut, data, 0600)
+}
@@ -0,0 +1,34 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package comm
+
+import "time"
+
+// Clock schedules message delivery of simulated networks and timeouts of coordinator
+// election and tss processes so tests can control time.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func())
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock schedules on wall clock time.
+type RealClock struct{}
+
+func (c RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c RealClock) AfterFunc(d time.Duration, f func()) {
+	if d <= 0 {
+		f()
+		return
+	}
+
+	time.AfterFunc(d, f)
+}
+
+func (c RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
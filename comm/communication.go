@@ -4,6 +4,8 @@
 package comm
 
 import (
+	"time"
+
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
@@ -12,7 +14,11 @@ type WrappedMessage struct {
 	MessageType MessageType `json:"message_type"`
 	SessionID   string      `json:"message_id"`
 	Payload     []byte      `json:"payload"`
-	From        peer.ID     `json:"-"`
+	// Timestamp is the time the sender created the message
+	Timestamp time.Time `json:"timestamp"`
+	// Sequence increases with each message the sender sends for the session
+	Sequence uint64  `json:"sequence"`
+	From     peer.ID `json:"-"`
 }
 
 // Communication defines methods for communicating between peers
@@ -15,6 +15,9 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
+// aliveWaitTime is how long alive message waits for the election in progress to confirm it
+const aliveWaitTime = 500 * time.Millisecond
+
 // bullyCoordinatorElector is used to execute bully coordinator discovery
 type bullyCoordinatorElector struct {
 	sessionID    string
@@ -28,10 +31,18 @@ type bullyCoordinatorElector struct {
 	mu           *sync.RWMutex
 	coordinator  peer.ID
 	sortedPeers  common.SortablePeerSlice
+	clock        comm.Clock
 }
 
 func NewBullyCoordinatorElector(
 	sessionID string, host host.Host, config relayer.BullyConfig, communication comm.Communication,
+) CoordinatorElector {
+	return NewBullyCoordinatorElectorWithClock(sessionID, host, config, communication, comm.RealClock{})
+}
+
+// NewBullyCoordinatorElectorWithClock creates bully elector that schedules election waits on the provided clock.
+func NewBullyCoordinatorElectorWithClock(
+	sessionID string, host host.Host, config relayer.BullyConfig, communication comm.Communication, clock comm.Clock,
 ) CoordinatorElector {
 	bully := &bullyCoordinatorElector{
 		sessionID:    sessionID,
@@ -44,6 +55,7 @@ func NewBullyCoordinatorElector(
 		hostID:       host.ID(),
 		mu:           &sync.RWMutex{},
 		coordinator:  host.ID(),
+		clock:        clock,
 	}
 
 	return bully
@@ -58,12 +70,13 @@ func (bc *bullyCoordinatorElector) Coordinator(ctx context.Context, peers peer.I
 
 	bc.sortedPeers = common.SortPeersForSession(peers, bc.sessionID)
 	errChan := make(chan error)
+	timeout := bc.clock.After(bc.conf.BullyWaitTime)
 	go bc.startBullyCoordination(errChan)
 
 	select {
 	case err := <-errChan:
 		return "", err
-	case <-time.After(bc.conf.BullyWaitTime):
+	case <-timeout:
 		break
 	}
 
@@ -92,7 +105,7 @@ func (bc *bullyCoordinatorElector) listen(ctx context.Context) {
 					// waits for confirmation that elector is alive
 					case bc.electionChan <- msg:
 						break
-					case <-time.After(500 * time.Millisecond):
+					case <-bc.clock.After(aliveWaitTime):
 						break
 					}
 				}
@@ -114,6 +127,7 @@ func (bc *bullyCoordinatorElector) listen(ctx context.Context) {
 }
 
 func (bc *bullyCoordinatorElector) elect(errChan chan error) {
+	timeout := bc.clock.After(bc.conf.ElectionWaitTime)
 	for _, p := range bc.sortedPeers {
 		if bc.isPeerIDHigher(p.ID, bc.hostID) {
 			bc.comm.Broadcast(peer.IDSlice{p.ID}, nil, comm.CoordinatorElectionMsg, bc.sessionID, errChan)
@@ -123,7 +137,7 @@ func (bc *bullyCoordinatorElector) elect(errChan chan error) {
 	select {
 	case <-bc.electionChan:
 		return
-	case <-time.After(bc.conf.ElectionWaitTime):
+	case <-timeout:
 		bc.setCoordinator(bc.hostID)
 		bc.comm.Broadcast(bc.sortedPeers.GetPeerIDs(), []byte{}, comm.CoordinatorSelectMsg, bc.sessionID, errChan)
 		return
@@ -9,12 +9,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
 
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/ChainSafe/sygma-relayer/config/relayer"
 	"github.com/ChainSafe/sygma-relayer/topology"
 	"github.com/ChainSafe/sygma-relayer/tss/common"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
 	"github.com/golang/mock/gomock"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/host"
@@ -335,3 +338,129 @@ func (s *BullyTestSuite) TestBully_GetCoordinator_OneDelay() {
 		})
 	}
 }
+
+type BullySimulatedNetworkTestSuite struct {
+	suite.Suite
+	sessionID string
+	hosts     []host.Host
+	faults    *memory.Faults
+	config    relayer.BullyConfig
+}
+
+func TestRunBullySimulatedNetworkTestSuite(t *testing.T) {
+	suite.Run(t, new(BullySimulatedNetworkTestSuite))
+}
+
+func (s *BullySimulatedNetworkTestSuite) SetupTest() {
+	s.sessionID = "1"
+	s.hosts = []host.Host{}
+	for i := 0; i < 3; i++ {
+		host, _ := tsstest.NewHost(i)
+		s.hosts = append(s.hosts, host)
+	}
+	s.faults = memory.NewFaults(1)
+	s.config = relayer.BullyConfig{
+		PingWaitTime:     100 * time.Millisecond,
+		PingBackOff:      100 * time.Millisecond,
+		PingInterval:     100 * time.Millisecond,
+		ElectionWaitTime: 200 * time.Millisecond,
+		BullyWaitTime:    time.Second,
+	}
+}
+
+func (s *BullySimulatedNetworkTestSuite) TearDownTest() {
+	for _, h := range s.hosts {
+		_ = h.Close()
+	}
+}
+
+func (s *BullySimulatedNetworkTestSuite) electCoordinators() []peer.ID {
+	return s.electCoordinatorsOnNetwork(func(network *memory.Network) {})
+}
+
+// electCoordinatorsOnNetwork calls beforeElection once all electors joined the network
+func (s *BullySimulatedNetworkTestSuite) electCoordinatorsOnNetwork(beforeElection func(network *memory.Network)) []peer.ID {
+	network := memory.NewSimulatedNetwork(s.faults, comm.RealClock{})
+	peers := peer.IDSlice{}
+	for _, h := range s.hosts {
+		peers = append(peers, h.ID())
+	}
+
+	electors := []elector.CoordinatorElector{}
+	for _, h := range s.hosts {
+		electors = append(electors, elector.NewBullyCoordinatorElector(s.sessionID, h, s.config, memory.NewCommunication(network, h.ID())))
+	}
+	beforeElection(network)
+
+	resultChn := make(chan peer.ID, len(s.hosts))
+	for _, b := range electors {
+		b := b
+		go func() {
+			coordinator, _ := b.Coordinator(context.Background(), peers)
+			resultChn <- coordinator
+		}()
+	}
+
+	coordinators := []peer.ID{}
+	for range s.hosts {
+		coordinators = append(coordinators, <-resultChn)
+	}
+	return coordinators
+}
+
+func (s *BullySimulatedNetworkTestSuite) sortedPeers() common.SortablePeerSlice {
+	peers := peer.IDSlice{}
+	for _, h := range s.hosts {
+		peers = append(peers, h.ID())
+	}
+	return common.SortPeersForSession(peers, s.sessionID)
+}
+
+func (s *BullySimulatedNetworkTestSuite) Test_DelayedAndDuplicatedMessages() {
+	s.faults.SetLatency(10*time.Millisecond, 50*time.Millisecond)
+	s.faults.SetDuplicateRate(0.3)
+
+	coordinators := s.electCoordinators()
+
+	expectedCoordinator := s.sortedPeers()[0].ID
+	s.Equal([]peer.ID{expectedCoordinator, expectedCoordinator, expectedCoordinator}, coordinators)
+}
+
+func (s *BullySimulatedNetworkTestSuite) Test_PartitionedLeader() {
+	sortedPeers := s.sortedPeers()
+	s.faults.Partition(peer.IDSlice{sortedPeers[0].ID}, peer.IDSlice{sortedPeers[1].ID, sortedPeers[2].ID})
+
+	coordinators := s.electCoordinators()
+
+	electedPeers := make(map[peer.ID]int)
+	for _, coordinator := range coordinators {
+		electedPeers[coordinator]++
+	}
+	s.Equal(map[peer.ID]int{sortedPeers[0].ID: 1, sortedPeers[1].ID: 2}, electedPeers)
+}
+
+func (s *BullySimulatedNetworkTestSuite) Test_StaleSelectMessageFromEarlierRunIgnored() {
+	sortedPeers := s.sortedPeers()
+	s.faults.Partition(peer.IDSlice{sortedPeers[0].ID}, peer.IDSlice{sortedPeers[1].ID, sortedPeers[2].ID})
+
+	coordinators := s.electCoordinatorsOnNetwork(func(network *memory.Network) {
+		// select message of the partitioned leader captured from an earlier run of the same session
+		for _, p := range sortedPeers[1:] {
+			err := network.Inject(p.ID, &comm.WrappedMessage{
+				MessageType: comm.CoordinatorSelectMsg,
+				SessionID:   s.sessionID,
+				Payload:     []byte{},
+				Timestamp:   time.Now().Add(-time.Hour),
+				Sequence:    1,
+				From:        sortedPeers[0].ID,
+			})
+			s.Nil(err)
+		}
+	})
+
+	electedPeers := make(map[peer.ID]int)
+	for _, coordinator := range coordinators {
+		electedPeers[coordinator]++
+	}
+	s.Equal(map[peer.ID]int{sortedPeers[0].ID: 1, sortedPeers[1].ID: 2}, electedPeers)
+}
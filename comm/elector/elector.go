@@ -33,16 +33,33 @@ type CoordinatorElectorFactory struct {
 	h      host.Host
 	comm   comm.Communication
 	config relayer.BullyConfig
+	clock  comm.Clock
 }
 
 // NewCoordinatorElectorFactory creates new CoordinatorElectorFactory
 func NewCoordinatorElectorFactory(h host.Host, config relayer.BullyConfig) *CoordinatorElectorFactory {
 	communication := p2p.NewCommunication(h, ProtocolID)
+	return NewCoordinatorElectorFactoryWithCommunication(h, communication, config)
+}
+
+// NewCoordinatorElectorFactoryWithCommunication creates new CoordinatorElectorFactory
+// that runs bully elections over the provided communication.
+func NewCoordinatorElectorFactoryWithCommunication(
+	h host.Host, communication comm.Communication, config relayer.BullyConfig,
+) *CoordinatorElectorFactory {
+	return NewCoordinatorElectorFactoryWithClock(h, communication, config, comm.RealClock{})
+}
 
+// NewCoordinatorElectorFactoryWithClock creates new CoordinatorElectorFactory
+// that schedules bully election waits on the provided clock.
+func NewCoordinatorElectorFactoryWithClock(
+	h host.Host, communication comm.Communication, config relayer.BullyConfig, clock comm.Clock,
+) *CoordinatorElectorFactory {
 	return &CoordinatorElectorFactory{
 		h:      h,
 		comm:   communication,
 		config: config,
+		clock:  clock,
 	}
 }
 
@@ -54,7 +71,7 @@ func (c *CoordinatorElectorFactory) CoordinatorElector(
 	case Static:
 		return NewCoordinatorElector(sessionID)
 	case Bully:
-		return NewBullyCoordinatorElector(sessionID, c.h, c.config, c.comm)
+		return NewBullyCoordinatorElectorWithClock(sessionID, c.h, c.config, c.comm, c.clock)
 	default:
 		return nil
 	}
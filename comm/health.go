@@ -39,3 +39,19 @@ func ExecuteCommHealthCheck(communication Communication, peers peer.IDSlice) []*
 		}
 	}
 }
+
+// HealthChecker dials peers through the health protocol to verify they are reachable.
+type HealthChecker struct {
+	communication Communication
+}
+
+func NewHealthChecker(communication Communication) *HealthChecker {
+	return &HealthChecker{
+		communication: communication,
+	}
+}
+
+// CheckPeers dials provided peers and returns errors for peers that are not reachable.
+func (hc *HealthChecker) CheckPeers(peers peer.IDSlice) []*CommunicationError {
+	return ExecuteCommHealthCheck(hc.communication, peers)
+}
@@ -0,0 +1,109 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package memory
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// VirtualClock is a manually advanced clock that runs scheduled functions
+// in deadline order so message delivery and timeouts are deterministic.
+type VirtualClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	seq    uint64
+	timers timerQueue
+}
+
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{
+		now: start,
+	}
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+// AfterFunc schedules f to run when the clock is advanced by at least d.
+func (c *VirtualClock) AfterFunc(d time.Duration, f func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.seq++
+	heap.Push(&c.timers, &timer{
+		deadline: c.now.Add(d),
+		seq:      c.seq,
+		f:        f,
+	})
+}
+
+// After returns a channel that receives the clock time when the clock is advanced by at least d.
+func (c *VirtualClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.AfterFunc(d, func() {
+		ch <- c.Now()
+	})
+	return ch
+}
+
+// Advance moves the clock forward by d and runs all functions scheduled until the new time.
+// Functions scheduled with the same deadline run in the order they were scheduled.
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	target := c.now.Add(d)
+	for c.timers.Len() > 0 && !c.timers[0].deadline.After(target) {
+		t := heap.Pop(&c.timers).(*timer)
+		c.now = t.deadline
+		c.lock.Unlock()
+		t.f()
+		c.lock.Lock()
+	}
+	c.now = target
+	c.lock.Unlock()
+}
+
+// Pending returns the number of scheduled functions that haven't run yet.
+func (c *VirtualClock) Pending() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.timers.Len()
+}
+
+type timer struct {
+	deadline time.Time
+	seq      uint64
+	f        func()
+}
+
+type timerQueue []*timer
+
+func (q timerQueue) Len() int { return len(q) }
+
+func (q timerQueue) Less(i, j int) bool {
+	if q[i].deadline.Equal(q[j].deadline) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].deadline.Before(q[j].deadline)
+}
+
+func (q timerQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *timerQueue) Push(x interface{}) {
+	*q = append(*q, x.(*timer))
+}
+
+func (q *timerQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	*q = old[:n-1]
+	return t
+}
@@ -0,0 +1,65 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
+	"github.com/stretchr/testify/suite"
+)
+
+type VirtualClockTestSuite struct {
+	suite.Suite
+	start time.Time
+	clock *memory.VirtualClock
+}
+
+func TestRunVirtualClockTestSuite(t *testing.T) {
+	suite.Run(t, new(VirtualClockTestSuite))
+}
+
+func (s *VirtualClockTestSuite) SetupTest() {
+	s.start = time.Unix(0, 0)
+	s.clock = memory.NewVirtualClock(s.start)
+}
+
+func (s *VirtualClockTestSuite) Test_Advance_RunsDueFunctionsInDeadlineOrder() {
+	executed := []int{}
+	s.clock.AfterFunc(time.Second*2, func() { executed = append(executed, 2) })
+	s.clock.AfterFunc(time.Second, func() { executed = append(executed, 1) })
+	s.clock.AfterFunc(time.Second, func() { executed = append(executed, 3) })
+	s.clock.AfterFunc(time.Second*5, func() { executed = append(executed, 5) })
+
+	s.clock.Advance(time.Second * 2)
+
+	s.Equal([]int{1, 3, 2}, executed)
+	s.Equal(1, s.clock.Pending())
+	s.Equal(s.start.Add(time.Second*2), s.clock.Now())
+}
+
+func (s *VirtualClockTestSuite) Test_Advance_RunsFunctionsScheduledDuringAdvance() {
+	executed := []int{}
+	s.clock.AfterFunc(time.Second, func() {
+		executed = append(executed, 1)
+		s.clock.AfterFunc(time.Second, func() { executed = append(executed, 2) })
+		s.clock.AfterFunc(time.Second*5, func() { executed = append(executed, 3) })
+	})
+
+	s.clock.Advance(time.Second * 3)
+
+	s.Equal([]int{1, 2}, executed)
+	s.Equal(1, s.clock.Pending())
+}
+
+func (s *VirtualClockTestSuite) Test_Advance_NothingDue() {
+	executed := false
+	s.clock.AfterFunc(time.Second, func() { executed = true })
+
+	s.clock.Advance(time.Millisecond)
+
+	s.False(executed)
+	s.Equal(1, s.clock.Pending())
+}
@@ -0,0 +1,190 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package memory
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Fault defines how a single message is delivered to the receiving peer.
+type Fault struct {
+	// Drop drops the message
+	Drop bool
+	// Duplicates is the number of additional copies of the message delivered
+	Duplicates int
+	// Latency delays message delivery
+	Latency time.Duration
+}
+
+// FaultModel decides the fault applied to a message sent to the peer.
+type FaultModel interface {
+	Fault(msg *comm.WrappedMessage, to peer.ID) Fault
+}
+
+// FaultRule applies the fault to messages matching all non empty filters.
+type FaultRule struct {
+	From         peer.ID
+	To           peer.ID
+	SessionID    string
+	MessageTypes []comm.MessageType
+	// Count limits the number of messages the rule is applied to, 0 applies it to all matching messages
+	Count int
+	Fault Fault
+}
+
+func (r *FaultRule) matches(msg *comm.WrappedMessage, to peer.ID) bool {
+	if r.From != "" && r.From != msg.From {
+		return false
+	}
+	if r.To != "" && r.To != to {
+		return false
+	}
+	if r.SessionID != "" && r.SessionID != msg.SessionID {
+		return false
+	}
+	if len(r.MessageTypes) == 0 {
+		return true
+	}
+	for _, msgType := range r.MessageTypes {
+		if msgType == msg.MessageType {
+			return true
+		}
+	}
+	return false
+}
+
+// Faults is a programmable fault model. Faults of messages are decided in the following order:
+// messages between partitioned peers are dropped, the first matching rule is applied and
+// random faults are applied to all other messages. Random faults are generated from the provided
+// seed so runs with the same seed and message order are reproducible.
+type Faults struct {
+	lock          sync.Mutex
+	rand          *rand.Rand
+	latency       time.Duration
+	jitter        time.Duration
+	dropRate      float64
+	duplicateRate float64
+	rules         []*FaultRule
+	partitions    map[peer.ID]int
+}
+
+func NewFaults(seed int64) *Faults {
+	return &Faults{
+		rand:       rand.New(rand.NewSource(seed)),
+		partitions: make(map[peer.ID]int),
+	}
+}
+
+// SetLatency sets the base latency of all messages. Each message is delayed by an additional
+// random duration up to jitter which reorders messages.
+func (f *Faults) SetLatency(latency time.Duration, jitter time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.latency = latency
+	f.jitter = jitter
+}
+
+// SetDropRate sets the probability of a message being dropped.
+func (f *Faults) SetDropRate(rate float64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.dropRate = rate
+}
+
+// SetDuplicateRate sets the probability of a message being delivered twice.
+func (f *Faults) SetDuplicateRate(rate float64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.duplicateRate = rate
+}
+
+// AddRule adds fault rule. Rules are matched in the order they were added.
+func (f *Faults) AddRule(rule FaultRule) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.rules = append(f.rules, &rule)
+}
+
+// Partition splits peers into groups that can't communicate with each other.
+// Peers not in any of the groups can communicate with all peers.
+func (f *Faults) Partition(groups ...peer.IDSlice) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.partitions = make(map[peer.ID]int)
+	for i, group := range groups {
+		for _, p := range group {
+			f.partitions[p] = i
+		}
+	}
+}
+
+// Heal removes network partitions.
+func (f *Faults) Heal() {
+	f.Partition()
+}
+
+// Reset removes all rules, partitions and random faults.
+func (f *Faults) Reset() {
+	f.Heal()
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.rules = nil
+	f.latency = 0
+	f.jitter = 0
+	f.dropRate = 0
+	f.duplicateRate = 0
+}
+
+func (f *Faults) Fault(msg *comm.WrappedMessage, to peer.ID) Fault {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.partitioned(msg.From, to) {
+		return Fault{Drop: true}
+	}
+
+	for i, rule := range f.rules {
+		if !rule.matches(msg, to) {
+			continue
+		}
+
+		if rule.Count > 0 {
+			rule.Count--
+			if rule.Count == 0 {
+				f.rules = append(f.rules[:i], f.rules[i+1:]...)
+			}
+		}
+		return rule.Fault
+	}
+
+	fault := Fault{Latency: f.latency}
+	if f.jitter > 0 {
+		fault.Latency += time.Duration(f.rand.Int63n(int64(f.jitter)))
+	}
+	if f.rand.Float64() < f.dropRate {
+		fault.Drop = true
+	}
+	if f.rand.Float64() < f.duplicateRate {
+		fault.Duplicates = 1
+	}
+	return fault
+}
+
+func (f *Faults) partitioned(p1 peer.ID, p2 peer.ID) bool {
+	g1, ok1 := f.partitions[p1]
+	g2, ok2 := f.partitions[p2]
+	return ok1 && ok2 && g1 != g2
+}
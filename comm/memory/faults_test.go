@@ -0,0 +1,152 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type SimulatedNetworkTestSuite struct {
+	suite.Suite
+	peer1   peer.ID
+	peer2   peer.ID
+	peer3   peer.ID
+	faults  *memory.Faults
+	clock   *memory.VirtualClock
+	network *memory.Network
+	comm1   *memory.Communication
+	comm2   *memory.Communication
+	comm3   *memory.Communication
+	msgChn  chan *comm.WrappedMessage
+}
+
+func TestRunSimulatedNetworkTestSuite(t *testing.T) {
+	suite.Run(t, new(SimulatedNetworkTestSuite))
+}
+
+func (s *SimulatedNetworkTestSuite) SetupTest() {
+	s.peer1, _ = peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	s.peer2, _ = peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	s.peer3, _ = peer.Decode("QmYayosTHxL2xa4jyrQ2PmbhGbrkSxsGM1kzXLTT8SsLVy")
+	s.faults = memory.NewFaults(1)
+	s.clock = memory.NewVirtualClock(time.Unix(0, 0))
+	s.network = memory.NewSimulatedNetwork(s.faults, s.clock)
+	s.comm1 = memory.NewCommunication(s.network, s.peer1)
+	s.comm2 = memory.NewCommunication(s.network, s.peer2)
+	s.comm3 = memory.NewCommunication(s.network, s.peer3)
+	s.msgChn = make(chan *comm.WrappedMessage, 10)
+	s.comm2.Subscribe("1", comm.TssKeySignMsg, s.msgChn)
+}
+
+func (s *SimulatedNetworkTestSuite) received() []string {
+	time.Sleep(time.Millisecond * 50)
+	payloads := []string{}
+	for {
+		select {
+		case msg := <-s.msgChn:
+			payloads = append(payloads, string(msg.Payload))
+		default:
+			return payloads
+		}
+	}
+}
+
+func (s *SimulatedNetworkTestSuite) Test_Latency_DeliveredAfterClockAdvanced() {
+	s.faults.SetLatency(time.Second, 0)
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg"), comm.TssKeySignMsg, "1", nil)
+
+	s.Equal([]string{}, s.received())
+	s.clock.Advance(time.Second)
+	s.Equal([]string{"msg"}, s.received())
+}
+
+func (s *SimulatedNetworkTestSuite) Test_Rule_ReordersMessages() {
+	s.faults.AddRule(memory.FaultRule{
+		From:  s.peer1,
+		Count: 1,
+		Fault: memory.Fault{Latency: time.Second * 2},
+	})
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg1"), comm.TssKeySignMsg, "1", nil)
+	s.clock.Advance(time.Millisecond)
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg2"), comm.TssKeySignMsg, "1", nil)
+
+	s.clock.Advance(time.Second)
+	s.Equal([]string{"msg2"}, s.received())
+	s.clock.Advance(time.Second)
+	s.Equal([]string{"msg1"}, s.received())
+}
+
+func (s *SimulatedNetworkTestSuite) Test_Rule_DropsMatchingMessages() {
+	s.faults.AddRule(memory.FaultRule{
+		To:           s.peer2,
+		MessageTypes: []comm.MessageType{comm.TssKeySignMsg},
+		Count:        1,
+		Fault:        memory.Fault{Drop: true},
+	})
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg1"), comm.TssKeySignMsg, "1", nil)
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg2"), comm.TssKeySignMsg, "1", nil)
+	s.clock.Advance(0)
+
+	s.Equal([]string{"msg2"}, s.received())
+	s.Equal(memory.Stats{Sent: 2, Delivered: 1, Dropped: 1}, s.network.Stats())
+}
+
+func (s *SimulatedNetworkTestSuite) Test_Rule_DuplicatesMessages() {
+	s.faults.AddRule(memory.FaultRule{
+		SessionID: "1",
+		Fault:     memory.Fault{Duplicates: 2},
+	})
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg"), comm.TssKeySignMsg, "1", nil)
+	s.clock.Advance(0)
+
+	// duplicates are delivered by the network and dropped by the receiver as replayed messages
+	s.Equal([]string{"msg"}, s.received())
+	s.Equal(memory.Stats{Sent: 1, Delivered: 3, Duplicated: 2}, s.network.Stats())
+}
+
+func (s *SimulatedNetworkTestSuite) Test_Partition_DropsMessagesBetweenGroups() {
+	msgChn3 := make(chan *comm.WrappedMessage, 10)
+	s.comm3.Subscribe("1", comm.TssKeySignMsg, msgChn3)
+	s.faults.Partition(peer.IDSlice{s.peer1, s.peer3}, peer.IDSlice{s.peer2})
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer2, s.peer3}, []byte("msg1"), comm.TssKeySignMsg, "1", nil)
+	s.clock.Advance(0)
+
+	s.Equal([]string{}, s.received())
+	s.Equal("msg1", string((<-msgChn3).Payload))
+
+	s.faults.Heal()
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg2"), comm.TssKeySignMsg, "1", nil)
+	s.clock.Advance(0)
+
+	s.Equal([]string{"msg2"}, s.received())
+}
+
+func (s *SimulatedNetworkTestSuite) Test_DropRate_SameSeedSameFaults() {
+	dropped := func() []bool {
+		faults := memory.NewFaults(42)
+		faults.SetDropRate(0.5)
+		results := []bool{}
+		for i := 0; i < 20; i++ {
+			results = append(results, faults.Fault(&comm.WrappedMessage{From: s.peer1}, s.peer2).Drop)
+		}
+		return results
+	}
+
+	first := dropped()
+
+	s.Equal(first, dropped())
+	s.Contains(first, true)
+	s.Contains(first, false)
+}
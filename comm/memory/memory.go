@@ -0,0 +1,204 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Stats counts messages sent through the network.
+type Stats struct {
+	Sent       int
+	Delivered  int
+	Dropped    int
+	Duplicated int
+}
+
+// Network connects in-memory communications of peers running inside the same process.
+type Network struct {
+	lock  sync.RWMutex
+	peers map[peer.ID]*Communication
+
+	faults    FaultModel
+	clock     comm.Clock
+	statsLock sync.Mutex
+	stats     Stats
+}
+
+// NewNetwork creates network that delivers messages immediately and without faults.
+func NewNetwork() *Network {
+	return NewSimulatedNetwork(nil, comm.RealClock{})
+}
+
+// NewSimulatedNetwork creates network that applies faults of the fault model to each sent
+// message and schedules delivery on the provided clock. Fault model is optional.
+func NewSimulatedNetwork(faults FaultModel, clock comm.Clock) *Network {
+	return &Network{
+		peers:  make(map[peer.ID]*Communication),
+		faults: faults,
+		clock:  clock,
+	}
+}
+
+// Stats returns counts of messages sent through the network.
+func (n *Network) Stats() Stats {
+	n.statsLock.Lock()
+	defer n.statsLock.Unlock()
+
+	return n.stats
+}
+
+func (n *Network) join(c *Communication) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.peers[c.peerID] = c
+}
+
+func (n *Network) peer(peerID peer.ID) (*Communication, bool) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	c, ok := n.peers[peerID]
+	return c, ok
+}
+
+func (n *Network) send(receiver *Communication, msg *comm.WrappedMessage) {
+	fault := Fault{}
+	if n.faults != nil {
+		fault = n.faults.Fault(msg, receiver.peerID)
+	}
+
+	n.statsLock.Lock()
+	n.stats.Sent++
+	if fault.Drop {
+		n.stats.Dropped++
+	} else {
+		n.stats.Delivered += 1 + fault.Duplicates
+		n.stats.Duplicated += fault.Duplicates
+	}
+	n.statsLock.Unlock()
+
+	if fault.Drop {
+		receiver.logger.Debug().Str("MsgType", msg.MessageType.String()).Str("SessionID", msg.SessionID).Msgf(
+			"dropped message from %s", msg.From.Pretty(),
+		)
+		return
+	}
+
+	for i := 0; i <= fault.Duplicates; i++ {
+		n.clock.AfterFunc(fault.Latency, func() {
+			receiver.receive(msg)
+		})
+	}
+}
+
+// Inject delivers the message to the peer as is, bypassing the fault model. It is used to
+// simulate messages captured from earlier sessions and sent again.
+func (n *Network) Inject(to peer.ID, msg *comm.WrappedMessage) error {
+	receiver, ok := n.peer(to)
+	if !ok {
+		return fmt.Errorf("peer %s not in network", to)
+	}
+
+	receiver.receive(msg)
+	return nil
+}
+
+// Communication is comm.Communication implementation that delivers messages
+// to peers joined to the same in-memory network. Stale and replayed messages
+// are dropped on receive.
+type Communication struct {
+	p2p.SessionSubscriptionManager
+	peerID      peer.ID
+	network     *Network
+	sequencer   *comm.Sequencer
+	replayGuard *comm.ReplayGuard
+	logger      zerolog.Logger
+}
+
+// NewCommunication creates communication for the provided peer and joins it to the network.
+func NewCommunication(network *Network, peerID peer.ID) *Communication {
+	c := &Communication{
+		SessionSubscriptionManager: p2p.NewSessionSubscriptionManager(),
+		peerID:                     peerID,
+		network:                    network,
+		sequencer:                  comm.NewSequencer(),
+		replayGuard:                comm.NewReplayGuard(comm.DefaultMaxMessageAge, false),
+		logger:                     log.With().Str("Module", "communication").Str("Peer", peerID.Pretty()).Logger(),
+	}
+	network.join(c)
+	return c
+}
+
+/** Communication interface methods **/
+
+func (c *Communication) Broadcast(
+	peers peer.IDSlice,
+	msg []byte,
+	msgType comm.MessageType,
+	sessionID string,
+	errChan chan error,
+) {
+	c.logger.Debug().Str("MsgType", msgType.String()).Str("SessionID", sessionID).Msg(
+		"broadcasting message",
+	)
+	timestamp := c.network.clock.Now()
+	sequence := c.sequencer.Next(sessionID)
+	for _, peerID := range peers {
+		if c.peerID == peerID {
+			continue // don't send message to itself
+		}
+
+		receiver, ok := c.network.peer(peerID)
+		if !ok {
+			p2p.SendError(errChan, fmt.Errorf("peer %s not in network", peerID), peerID)
+			continue
+		}
+
+		payload := make([]byte, len(msg))
+		copy(payload, msg)
+		c.network.send(receiver, &comm.WrappedMessage{
+			MessageType: msgType,
+			SessionID:   sessionID,
+			Payload:     payload,
+			Timestamp:   timestamp,
+			Sequence:    sequence,
+			From:        c.peerID,
+		})
+	}
+}
+
+func (c *Communication) Subscribe(
+	sessionID string,
+	msgType comm.MessageType,
+	channel chan *comm.WrappedMessage,
+) comm.SubscriptionID {
+	return c.SubscribeTo(sessionID, msgType, channel)
+}
+
+func (c *Communication) UnSubscribe(
+	subID comm.SubscriptionID,
+) {
+	c.UnSubscribeFrom(subID)
+}
+
+/** Helper methods **/
+
+func (c *Communication) receive(msg *comm.WrappedMessage) {
+	err := c.replayGuard.Check(msg, c.network.clock.Now())
+	if err != nil {
+		c.logger.Debug().Err(err).Str("From", msg.From.Pretty()).Msg("dropped message")
+		return
+	}
+
+	c.Deliver(msg)
+}
@@ -0,0 +1,121 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type MemoryCommunicationTestSuite struct {
+	suite.Suite
+	peer1   peer.ID
+	peer2   peer.ID
+	peer3   peer.ID
+	network *memory.Network
+	comm1   *memory.Communication
+	comm2   *memory.Communication
+}
+
+func TestRunMemoryCommunicationTestSuite(t *testing.T) {
+	suite.Run(t, new(MemoryCommunicationTestSuite))
+}
+
+func (s *MemoryCommunicationTestSuite) SetupTest() {
+	s.peer1, _ = peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	s.peer2, _ = peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	s.peer3, _ = peer.Decode("QmYayosTHxL2xa4jyrQ2PmbhGbrkSxsGM1kzXLTT8SsLVy")
+	s.network = memory.NewNetwork()
+	s.comm1 = memory.NewCommunication(s.network, s.peer1)
+	s.comm2 = memory.NewCommunication(s.network, s.peer2)
+}
+
+func (s *MemoryCommunicationTestSuite) Test_Broadcast_DeliversToSubscribers() {
+	msgChn := make(chan *comm.WrappedMessage)
+	s.comm2.Subscribe("1", comm.TssKeySignMsg, msgChn)
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer1, s.peer2}, []byte("msg"), comm.TssKeySignMsg, "1", nil)
+
+	select {
+	case msg := <-msgChn:
+		s.Equal(comm.TssKeySignMsg, msg.MessageType)
+		s.Equal("1", msg.SessionID)
+		s.Equal([]byte("msg"), msg.Payload)
+		s.Equal(s.peer1, msg.From)
+		s.False(msg.Timestamp.IsZero())
+		s.NotZero(msg.Sequence)
+	case <-time.After(time.Second):
+		s.Fail("message not delivered")
+	}
+}
+
+func (s *MemoryCommunicationTestSuite) Test_Inject_StaleMessageDropped() {
+	msgChn := make(chan *comm.WrappedMessage)
+	s.comm2.Subscribe("1", comm.TssStartMsg, msgChn)
+
+	err := s.network.Inject(s.peer2, &comm.WrappedMessage{
+		MessageType: comm.TssStartMsg,
+		SessionID:   "1",
+		Timestamp:   time.Now().Add(-time.Hour),
+		Sequence:    1,
+		From:        s.peer1,
+	})
+	s.Nil(err)
+
+	select {
+	case <-msgChn:
+		s.Fail("stale message delivered")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func (s *MemoryCommunicationTestSuite) Test_Inject_ReplayedMessageDropped() {
+	msgChn := make(chan *comm.WrappedMessage, 2)
+	s.comm2.Subscribe("1", comm.TssStartMsg, msgChn)
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssStartMsg,
+		SessionID:   "1",
+		Timestamp:   time.Now(),
+		Sequence:    1,
+		From:        s.peer1,
+	}
+
+	s.Nil(s.network.Inject(s.peer2, msg))
+	s.Nil(s.network.Inject(s.peer2, msg))
+
+	<-msgChn
+	select {
+	case <-msgChn:
+		s.Fail("replayed message delivered")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func (s *MemoryCommunicationTestSuite) Test_Broadcast_UnsubscribedMessageDropped() {
+	msgChn := make(chan *comm.WrappedMessage)
+	subID := s.comm2.Subscribe("1", comm.TssKeySignMsg, msgChn)
+	s.comm2.UnSubscribe(subID)
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer2}, []byte("msg"), comm.TssKeySignMsg, "1", nil)
+
+	select {
+	case <-msgChn:
+		s.Fail("message delivered after unsubscribe")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func (s *MemoryCommunicationTestSuite) Test_Broadcast_UnknownPeer() {
+	errChn := make(chan error, 1)
+
+	s.comm1.Broadcast(peer.IDSlice{s.peer3}, []byte("msg"), comm.TssKeySignMsg, "1", errChn)
+
+	err := <-errChn
+	s.Equal(s.peer3, err.(*comm.CommunicationError).Peer)
+}
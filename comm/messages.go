@@ -3,6 +3,11 @@
 
 package comm
 
+import (
+	"fmt"
+	"strings"
+)
+
 // MessageType represents message type identificator
 type MessageType uint8
 
@@ -33,6 +38,20 @@ const (
 	CoordinatorPingMsg
 	// CoordinatorPingResponseMsg message type used to respond on CoordinatorPingMsg message.
 	CoordinatorPingResponseMsg
+	// TssPresignMsg message type used for communicating offline presignature generation.
+	TssPresignMsg
+	// TssOneRoundSignMsg message type used for exchanging signature shares calculated from a presignature.
+	TssOneRoundSignMsg
+	// TssEdDSAKeyGenMsg message type used for communicating EdDSA key generation.
+	TssEdDSAKeyGenMsg
+	// TssEdDSAKeySignMsg message type used for communicating EdDSA signature for specific message.
+	TssEdDSAKeySignMsg
+	// TssEdDSAReshareMsg message type used for EdDSA resharing tss messages.
+	TssEdDSAReshareMsg
+	// KeyshareCheckMsg message type used to share public keyshare data and request the same data from peers.
+	KeyshareCheckMsg
+	// KeyshareCheckResponseMsg message type used to respond on KeyshareCheckMsg message with public keyshare data.
+	KeyshareCheckResponseMsg
 	// Unknown message type
 	Unknown
 )
@@ -66,7 +85,32 @@ func (msgType MessageType) String() string {
 		return "CoordinatorPingMsg"
 	case CoordinatorPingResponseMsg:
 		return "CoordinatorPingResponseMsg"
+	case TssPresignMsg:
+		return "TssPresignMsg"
+	case TssOneRoundSignMsg:
+		return "TssOneRoundSignMsg"
+	case TssEdDSAKeyGenMsg:
+		return "TssEdDSAKeyGenMsg"
+	case TssEdDSAKeySignMsg:
+		return "TssEdDSAKeySignMsg"
+	case TssEdDSAReshareMsg:
+		return "TssEdDSAReshareMsg"
+	case KeyshareCheckMsg:
+		return "KeyshareCheckMsg"
+	case KeyshareCheckResponseMsg:
+		return "KeyshareCheckResponseMsg"
 	default:
 		return "UnknownMsg"
 	}
 }
+
+// ParseMessageType returns message type with the provided name. Names are case insensitive
+// as configuration keys are lowercased when loaded.
+func ParseMessageType(name string) (MessageType, error) {
+	for msgType := TssKeyGenMsg; msgType < Unknown; msgType++ {
+		if strings.EqualFold(msgType.String(), name) {
+			return msgType, nil
+		}
+	}
+	return Unknown, fmt.Errorf("unknown message type %s", name)
+}
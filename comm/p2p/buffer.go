@@ -0,0 +1,161 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p
+
+import (
+	"time"
+
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+)
+
+const (
+	// MessageBufferTTL is the duration messages without subscribers are kept for.
+	MessageBufferTTL = time.Minute
+	// MessageBufferSize is the maximum number of buffered messages per session.
+	MessageBufferSize = 256
+	// MessageBufferMaxBytes is the maximum payload size of all buffered messages.
+	MessageBufferMaxBytes = 64 * 1024 * 1024
+)
+
+// BufferStats counts messages received before the local party subscribed to them.
+type BufferStats struct {
+	// Buffered is the number of messages buffered because there were no subscribers.
+	Buffered uint64 `json:"buffered"`
+	// Replayed is the number of buffered messages delivered to new subscribers.
+	Replayed uint64 `json:"replayed"`
+	// Expired is the number of buffered messages removed after the buffer TTL.
+	Expired uint64 `json:"expired"`
+	// Dropped is the number of messages not buffered because the session buffer was full.
+	Dropped uint64 `json:"dropped"`
+	// Evicted is the number of oldest buffered messages removed to keep the buffer under its size limit.
+	Evicted uint64 `json:"evicted"`
+	// Pending is the number of currently buffered messages.
+	Pending int `json:"pending"`
+	// PendingBytes is the payload size of currently buffered messages.
+	PendingBytes int `json:"pendingBytes"`
+}
+
+type bufferedMessage struct {
+	msg     *comm.WrappedMessage
+	expires time.Time
+}
+
+// messageBuffer keeps messages per session until a subscriber for the
+// message type subscribes or the message expires.
+//
+// Besides the per session limit, payload size of all buffered messages is limited
+// so peers opening many sessions can't exhaust memory. Oldest messages of any session
+// are evicted first when the limit is reached.
+type messageBuffer struct {
+	ttl      time.Duration
+	size     int
+	maxBytes int
+	sessions map[string][]bufferedMessage
+	stats    BufferStats
+}
+
+func newMessageBuffer(ttl time.Duration, size int, maxBytes int) *messageBuffer {
+	return &messageBuffer{
+		ttl:      ttl,
+		size:     size,
+		maxBytes: maxBytes,
+		sessions: make(map[string][]bufferedMessage),
+	}
+}
+
+func (b *messageBuffer) add(msg *comm.WrappedMessage) {
+	b.expire()
+
+	if len(b.sessions[msg.SessionID]) >= b.size || len(msg.Payload) > b.maxBytes {
+		b.stats.Dropped++
+		return
+	}
+	for b.stats.PendingBytes+len(msg.Payload) > b.maxBytes {
+		b.evictOldest()
+	}
+
+	b.sessions[msg.SessionID] = append(b.sessions[msg.SessionID], bufferedMessage{
+		msg:     msg,
+		expires: time.Now().Add(b.ttl),
+	})
+	b.stats.Buffered++
+	b.stats.Pending++
+	b.stats.PendingBytes += len(msg.Payload)
+}
+
+// take removes and returns buffered messages of the session with the provided type.
+func (b *messageBuffer) take(sessionID string, msgType comm.MessageType) []*comm.WrappedMessage {
+	b.expire()
+
+	msgs := []*comm.WrappedMessage{}
+	remaining := []bufferedMessage{}
+	for _, bm := range b.sessions[sessionID] {
+		if bm.msg.MessageType == msgType {
+			msgs = append(msgs, bm.msg)
+		} else {
+			remaining = append(remaining, bm)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(b.sessions, sessionID)
+	} else {
+		b.sessions[sessionID] = remaining
+	}
+
+	b.stats.Replayed += uint64(len(msgs))
+	b.stats.Pending -= len(msgs)
+	for _, msg := range msgs {
+		b.stats.PendingBytes -= len(msg.Payload)
+	}
+	return msgs
+}
+
+// evictOldest removes the oldest buffered message. Messages of a session are
+// ordered by arrival and all have the same ttl, so the oldest message is the first
+// message of the session with the earliest expiry.
+func (b *messageBuffer) evictOldest() {
+	oldestSession := ""
+	var oldest *bufferedMessage
+	for sessionID, msgs := range b.sessions {
+		if oldest == nil || msgs[0].expires.Before(oldest.expires) {
+			oldestSession = sessionID
+			oldest = &msgs[0]
+		}
+	}
+	if oldest == nil {
+		return
+	}
+
+	b.stats.Evicted++
+	b.stats.Pending--
+	b.stats.PendingBytes -= len(oldest.msg.Payload)
+	if len(b.sessions[oldestSession]) == 1 {
+		delete(b.sessions, oldestSession)
+	} else {
+		b.sessions[oldestSession] = b.sessions[oldestSession][1:]
+	}
+}
+
+func (b *messageBuffer) expire() {
+	now := time.Now()
+	for sessionID, msgs := range b.sessions {
+		remaining := []bufferedMessage{}
+		for _, bm := range msgs {
+			if now.Before(bm.expires) {
+				remaining = append(remaining, bm)
+			} else {
+				b.stats.PendingBytes -= len(bm.msg.Payload)
+			}
+		}
+
+		expired := len(msgs) - len(remaining)
+		b.stats.Expired += uint64(expired)
+		b.stats.Pending -= expired
+		if len(remaining) == 0 {
+			delete(b.sessions, sessionID)
+		} else {
+			b.sessions[sessionID] = remaining
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ProtobufProtocolVersion is appended to the base protocol ID to get the ID of
+// the protocol that uses the protobuf wire format.
+const ProtobufProtocolVersion = "2.0.0"
+
+// Codec encodes messages sent over a single libp2p protocol.
+type Codec interface {
+	Marshal(msg *comm.WrappedMessage) ([]byte, error)
+	Unmarshal(data []byte) (*comm.WrappedMessage, error)
+}
+
+// WireProtocol is a libp2p protocol together with the codec of its messages.
+// Streams of pooled protocols are kept open and reused for multiple messages.
+// Handlers of the legacy protocol read a single message per stream, so each
+// message sent over it is sent over a new stream.
+type WireProtocol struct {
+	ID     protocol.ID
+	Codec  Codec
+	Pooled bool
+}
+
+// WireProtocols returns protocols supported for the base protocol ID in order of
+// preference. Peers negotiate the first protocol both of them support, so relayers
+// keep communicating with relayers that only support the legacy JSON protocol.
+func WireProtocols(protocolID protocol.ID) []WireProtocol {
+	return []WireProtocol{
+		{
+			ID:     protocol.ID(fmt.Sprintf("%s/%s", protocolID, ProtobufProtocolVersion)),
+			Codec:  ProtobufCodec{},
+			Pooled: true,
+		},
+		{
+			ID:     protocolID,
+			Codec:  JSONCodec{},
+			Pooled: false,
+		},
+	}
+}
+
+// JSONCodec encodes messages as JSON objects for the legacy protocol. Payloads
+// are converted to legacy JSON payloads before sending.
+type JSONCodec struct{}
+
+func (c JSONCodec) Marshal(msg *comm.WrappedMessage) ([]byte, error) {
+	payload, err := legacyPayload(msg.MessageType, msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	legacyMsg := *msg
+	legacyMsg.Payload = payload
+	return json.Marshal(legacyMsg)
+}
+
+func (c JSONCodec) Unmarshal(data []byte) (*comm.WrappedMessage, error) {
+	msg := &comm.WrappedMessage{}
+	err := json.Unmarshal(data, msg)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// legacyPayload converts tss and start payloads to JSON payloads understood by
+// relayers that only support the legacy protocol. Payloads of other message types
+// are the same in both protocols and are returned unchanged.
+func legacyPayload(msgType comm.MessageType, payload []byte) ([]byte, error) {
+	switch msgType {
+	case comm.TssKeyGenMsg, comm.TssKeySignMsg, comm.TssReshareMsg, comm.TssPresignMsg,
+		comm.TssEdDSAKeyGenMsg, comm.TssEdDSAKeySignMsg, comm.TssEdDSAReshareMsg:
+		{
+			msgBytes, isBroadcast, err := comm.UnmarshalTssPayload(payload)
+			if err != nil {
+				return nil, err
+			}
+			return comm.MarshalLegacyTssPayload(msgBytes, isBroadcast)
+		}
+	case comm.TssStartMsg:
+		{
+			params, err := comm.UnmarshalStartPayload(payload)
+			if err != nil {
+				return nil, err
+			}
+			return comm.MarshalLegacyStartPayload(params)
+		}
+	default:
+		return payload, nil
+	}
+}
+
+// ProtobufCodec encodes messages as protobuf messages:
+//
+//	message WrappedMessage {
+//	  uint32 message_type = 1;
+//	  string session_id = 2;
+//	  bytes payload = 3;
+//	  int64 timestamp = 4; // unix time in nanoseconds
+//	  uint64 sequence = 5;
+//	}
+type ProtobufCodec struct{}
+
+func (c ProtobufCodec) Marshal(msg *comm.WrappedMessage) ([]byte, error) {
+	b := make([]byte, 0, len(msg.Payload)+len(msg.SessionID)+40)
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(msg.MessageType))
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, msg.SessionID)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendBytes(b, msg.Payload)
+	if !msg.Timestamp.IsZero() {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(msg.Timestamp.UnixNano()))
+	}
+	if msg.Sequence != 0 {
+		b = protowire.AppendTag(b, 5, protowire.VarintType)
+		b = protowire.AppendVarint(b, msg.Sequence)
+	}
+	return b, nil
+}
+
+func (c ProtobufCodec) Unmarshal(data []byte) (*comm.WrappedMessage, error) {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.Unknown,
+		Payload:     []byte{},
+	}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid message: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid message type: %w", protowire.ParseError(n))
+			}
+			msg.MessageType = comm.MessageType(v)
+			data = data[n:]
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid session ID: %w", protowire.ParseError(n))
+			}
+			msg.SessionID = v
+			data = data[n:]
+		case num == 3 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid payload: %w", protowire.ParseError(n))
+			}
+			msg.Payload = v
+			data = data[n:]
+		case num == 4 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid timestamp: %w", protowire.ParseError(n))
+			}
+			msg.Timestamp = time.Unix(0, int64(v))
+			data = data[n:]
+		case num == 5 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid sequence: %w", protowire.ParseError(n))
+			}
+			msg.Sequence = v
+			data = data[n:]
+		default:
+			// skip fields added by newer versions
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid message: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+	}
+	return msg, nil
+}
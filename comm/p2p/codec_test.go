@@ -0,0 +1,144 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+)
+
+type CodecTestSuite struct {
+	suite.Suite
+}
+
+func TestRunCodecTestSuite(t *testing.T) {
+	suite.Run(t, new(CodecTestSuite))
+}
+
+func (s *CodecTestSuite) Test_WireProtocols() {
+	protocols := p2p.WireProtocols("p2p/sygma")
+
+	s.Equal(2, len(protocols))
+	s.Equal("p2p/sygma/2.0.0", string(protocols[0].ID))
+	s.IsType(p2p.ProtobufCodec{}, protocols[0].Codec)
+	s.Equal("p2p/sygma", string(protocols[1].ID))
+	s.IsType(p2p.JSONCodec{}, protocols[1].Codec)
+}
+
+func (s *CodecTestSuite) Test_ProtobufCodec_RoundTrip() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssReshareMsg,
+		SessionID:   "session",
+		Payload:     comm.MarshalTssPayload([]byte{1, 2, 3}, true),
+		Timestamp:   time.Unix(0, 1666000000000000000),
+		Sequence:    1666000000000000001,
+	}
+
+	data, err := p2p.ProtobufCodec{}.Marshal(msg)
+	s.Nil(err)
+	unmarshaledMsg, err := p2p.ProtobufCodec{}.Unmarshal(data)
+	s.Nil(err)
+
+	s.Equal(msg, unmarshaledMsg)
+}
+
+func (s *CodecTestSuite) Test_ProtobufCodec_SkipsUnknownFields() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssKeyGenMsg,
+		SessionID:   "session",
+		Payload:     []byte{1},
+	}
+	data, _ := p2p.ProtobufCodec{}.Marshal(msg)
+	data = protowire.AppendTag(data, 10, protowire.BytesType)
+	data = protowire.AppendBytes(data, []byte("new field"))
+
+	unmarshaledMsg, err := p2p.ProtobufCodec{}.Unmarshal(data)
+
+	s.Nil(err)
+	s.Equal(msg, unmarshaledMsg)
+}
+
+func (s *CodecTestSuite) Test_ProtobufCodec_InvalidMessage() {
+	_, err := p2p.ProtobufCodec{}.Unmarshal([]byte{0x1a, 0x10, 0x01})
+
+	s.NotNil(err)
+}
+
+func (s *CodecTestSuite) Test_JSONCodec_SendsLegacyPayload() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "session",
+		Payload:     comm.MarshalTssPayload([]byte{1}, true),
+	}
+
+	data, err := p2p.JSONCodec{}.Marshal(msg)
+	s.Nil(err)
+
+	legacyMsg := comm.WrappedMessage{}
+	err = json.Unmarshal(data, &legacyMsg)
+	s.Nil(err)
+	s.Equal(comm.TssKeySignMsg, legacyMsg.MessageType)
+	s.Equal("session", legacyMsg.SessionID)
+	s.JSONEq(`{"msgBytes":"AQ==","isBroadcast":true}`, string(legacyMsg.Payload))
+	// original message is not modified
+	s.Equal(comm.MarshalTssPayload([]byte{1}, true), msg.Payload)
+}
+
+func (s *CodecTestSuite) Test_JSONCodec_SendsLegacyStartPayload() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssStartMsg,
+		Payload:     comm.MarshalStartPayload([]byte("params")),
+	}
+
+	data, err := p2p.JSONCodec{}.Marshal(msg)
+	s.Nil(err)
+
+	legacyMsg := comm.WrappedMessage{}
+	err = json.Unmarshal(data, &legacyMsg)
+	s.Nil(err)
+	s.JSONEq(`{"params":"cGFyYW1z"}`, string(legacyMsg.Payload))
+}
+
+func (s *CodecTestSuite) Test_JSONCodec_OtherMessageTypesUnchanged() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssOneRoundSignMsg,
+		Payload:     []byte(`{"signature":"AQ=="}`),
+	}
+
+	data, err := p2p.JSONCodec{}.Marshal(msg)
+	s.Nil(err)
+
+	legacyMsg := comm.WrappedMessage{}
+	err = json.Unmarshal(data, &legacyMsg)
+	s.Nil(err)
+	s.Equal(msg.Payload, legacyMsg.Payload)
+}
+
+func (s *CodecTestSuite) Test_WireProtocols_OnlyVersionedProtocolPooled() {
+	protocols := p2p.WireProtocols("p2p/sygma")
+
+	s.True(protocols[0].Pooled)
+	s.False(protocols[1].Pooled)
+}
+
+func (s *CodecTestSuite) Test_ProtobufCodec_IsSmallerThanJSONCodec() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssReshareMsg,
+		SessionID:   "session",
+		Payload:     comm.MarshalTssPayload(make([]byte, 100000), false),
+	}
+
+	protobufData, _ := p2p.ProtobufCodec{}.Marshal(msg)
+	jsonData, _ := p2p.JSONCodec{}.Marshal(msg)
+
+	s.Less(len(protobufData), 100100)
+	s.Greater(len(jsonData), 170000)
+}
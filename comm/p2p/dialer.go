@@ -0,0 +1,295 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	madns "github.com/multiformats/go-multiaddr-dns"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// ResolveCacheTTL is the duration resolved peer addresses are reused before they are resolved again.
+	ResolveCacheTTL = 5 * time.Minute
+	// DialTimeout is the maximum duration of dialing a single peer address.
+	DialTimeout = 10 * time.Second
+	// DialBackoffBase is the duration an address is skipped for after the first failed dial.
+	// It doubles with every following failure up to DialBackoffMax.
+	DialBackoffBase = 5 * time.Second
+	DialBackoffMax  = 2 * time.Minute
+)
+
+type addrStats struct {
+	latency      time.Duration
+	lastSuccess  time.Time
+	lastFailure  time.Time
+	failures     int
+	backoffUntil time.Time
+	resolved     []ma.Multiaddr
+	resolvedAt   time.Time
+}
+
+// succeeded returns true if the last dial of the address was successful.
+func (s *addrStats) succeeded() bool {
+	return !s.lastSuccess.IsZero() && s.lastSuccess.After(s.lastFailure)
+}
+
+// Dialer connects to peers by dialing their topology addresses one by one, starting
+// with addresses that were recently dialed successfully with the lowest latency.
+// Addresses that failed are skipped for an exponentially increasing backoff period.
+type Dialer struct {
+	h        host.Host
+	resolver *madns.Resolver
+
+	lock      sync.Mutex
+	peerLocks map[peer.ID]*sync.Mutex
+	stats     map[peer.ID]map[string]*addrStats
+}
+
+var (
+	hostDialersLock sync.Mutex
+	hostDialers     = make(map[host.Host]*Dialer)
+)
+
+// HostDialer returns the dialer shared by all communications and pingers of the host,
+// so dial backoff and serialization of dials to the same peer apply to all of them.
+func HostDialer(h host.Host) *Dialer {
+	hostDialersLock.Lock()
+	defer hostDialersLock.Unlock()
+
+	dialer, ok := hostDialers[h]
+	if !ok {
+		dialer = NewDialer(h)
+		hostDialers[h] = dialer
+	}
+	return dialer
+}
+
+func NewDialer(h host.Host) *Dialer {
+	return &Dialer{
+		h:         h,
+		resolver:  madns.DefaultResolver,
+		peerLocks: make(map[peer.ID]*sync.Mutex),
+		stats:     make(map[peer.ID]map[string]*addrStats),
+	}
+}
+
+// Dial connects to the peer if it is not already connected. Addresses are dialed
+// in order of their rank until one of them succeeds.
+func (d *Dialer) Dial(ctx context.Context, to peer.ID) error {
+	peerLock := d.peerLock(to)
+	peerLock.Lock()
+	defer peerLock.Unlock()
+
+	if d.h.Network().Connectedness(to) == network.Connected {
+		return nil
+	}
+
+	addrs := TopologyAddrs(d.h, to)
+	if len(addrs) == 0 {
+		return fmt.Errorf("peer %s has no defined addresses", to)
+	}
+	ranked := d.rank(to, addrs)
+	if len(ranked) == 0 {
+		return fmt.Errorf("all addresses of peer %s are backed off", to)
+	}
+
+	errs := []string{}
+	for _, addr := range ranked {
+		err := d.dialAddr(ctx, to, addr)
+		if err == nil {
+			return nil
+		}
+
+		log.Debug().Err(err).Str("peer", to.Pretty()).Msgf("unable to dial address %s", addr)
+		errs = append(errs, fmt.Sprintf("%s: %s", addr, err))
+	}
+	return fmt.Errorf("unable to dial peer %s: %s", to, strings.Join(errs, "; "))
+}
+
+// dialAddr adds the resolved address to the peerstore and dials the peer. Other peerstore
+// addresses of the peer are left untouched, so the host can dial them as well and the
+// address is only credited with the success if the connection was established over it.
+func (d *Dialer) dialAddr(ctx context.Context, to peer.ID, addr ma.Multiaddr) error {
+	resolved, err := d.resolve(ctx, to, addr)
+	if err != nil {
+		d.recordFailure(to, addr)
+		return err
+	}
+
+	d.h.Peerstore().AddAddrs(to, resolved, peerstore.TempAddrTTL)
+	dialCtx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := d.h.Network().DialPeer(dialCtx, to)
+	if err != nil {
+		d.recordFailure(to, addr)
+		return err
+	}
+
+	for _, resolvedAddr := range resolved {
+		if resolvedAddr.Equal(conn.RemoteMultiaddr()) {
+			d.recordSuccess(to, addr, time.Since(start))
+			break
+		}
+	}
+	return nil
+}
+
+// rank orders addresses that aren't backed off. Addresses whose last dial succeeded are
+// dialed first ordered by latency, followed by not yet dialed addresses and addresses
+// with the least consecutive failures.
+func (d *Dialer) rank(to peer.ID, addrs []ma.Multiaddr) []ma.Multiaddr {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := time.Now()
+	type rankedAddr struct {
+		addr  ma.Multiaddr
+		stats *addrStats
+	}
+	candidates := []rankedAddr{}
+	for _, addr := range addrs {
+		stats, ok := d.stats[to][addr.String()]
+		if !ok {
+			stats = &addrStats{}
+		}
+		if now.Before(stats.backoffUntil) {
+			continue
+		}
+		candidates = append(candidates, rankedAddr{addr: addr, stats: stats})
+	}
+
+	tier := func(s *addrStats) int {
+		switch {
+		case s.succeeded():
+			return 0
+		case s.failures == 0:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].stats, candidates[j].stats
+		if tier(a) != tier(b) {
+			return tier(a) < tier(b)
+		}
+		if a.succeeded() {
+			return a.latency < b.latency
+		}
+		return a.failures < b.failures
+	})
+
+	ranked := []ma.Multiaddr{}
+	for _, c := range candidates {
+		ranked = append(ranked, c.addr)
+	}
+	return ranked
+}
+
+// resolve returns resolved address, using the cached resolution if it is not older than
+// ResolveCacheTTL. Cached resolution is used if resolving fails.
+func (d *Dialer) resolve(ctx context.Context, to peer.ID, addr ma.Multiaddr) ([]ma.Multiaddr, error) {
+	if !madns.Matches(addr) {
+		return []ma.Multiaddr{addr}, nil
+	}
+
+	stats := d.addrStats(to, addr)
+	d.lock.Lock()
+	cached, resolvedAt := stats.resolved, stats.resolvedAt
+	d.lock.Unlock()
+	if len(cached) != 0 && time.Since(resolvedAt) < ResolveCacheTTL {
+		return cached, nil
+	}
+
+	resolved, err := d.resolver.Resolve(ctx, addr)
+	if err != nil || len(resolved) == 0 {
+		if len(cached) != 0 {
+			return cached, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("address %s resolved to no addresses", addr)
+		}
+		return nil, err
+	}
+
+	d.lock.Lock()
+	stats.resolved = resolved
+	stats.resolvedAt = time.Now()
+	d.lock.Unlock()
+	return resolved, nil
+}
+
+func (d *Dialer) recordSuccess(to peer.ID, addr ma.Multiaddr, latency time.Duration) {
+	stats := d.addrStats(to, addr)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if stats.latency == 0 {
+		stats.latency = latency
+	} else {
+		stats.latency = (stats.latency + latency) / 2
+	}
+	stats.lastSuccess = time.Now()
+	stats.failures = 0
+	stats.backoffUntil = time.Time{}
+}
+
+func (d *Dialer) recordFailure(to peer.ID, addr ma.Multiaddr) {
+	stats := d.addrStats(to, addr)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	stats.lastFailure = time.Now()
+	stats.failures++
+	backoff := DialBackoffBase
+	for i := 1; i < stats.failures && backoff < DialBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > DialBackoffMax {
+		backoff = DialBackoffMax
+	}
+	stats.backoffUntil = stats.lastFailure.Add(backoff)
+	// resolve the address again on the next dial in case it changed
+	stats.resolved = nil
+}
+
+func (d *Dialer) addrStats(to peer.ID, addr ma.Multiaddr) *addrStats {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if _, ok := d.stats[to]; !ok {
+		d.stats[to] = make(map[string]*addrStats)
+	}
+	stats, ok := d.stats[to][addr.String()]
+	if !ok {
+		stats = &addrStats{}
+		d.stats[to][addr.String()] = stats
+	}
+	return stats
+}
+
+func (d *Dialer) peerLock(to peer.ID) *sync.Mutex {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	peerLock, ok := d.peerLocks[to]
+	if !ok {
+		peerLock = &sync.Mutex{}
+		d.peerLocks[to] = peerLock
+	}
+	return peerLock
+}
@@ -0,0 +1,121 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+)
+
+type DialerTestSuite struct {
+	suite.Suite
+	sender      host.Host
+	receiver    host.Host
+	dialer      *p2p.Dialer
+	unreachable ma.Multiaddr
+}
+
+func TestRunDialerTestSuite(t *testing.T) {
+	suite.Run(t, new(DialerTestSuite))
+}
+
+func (s *DialerTestSuite) SetupTest() {
+	sender, receiver, err := newConnectedHosts()
+	s.Nil(err)
+	s.sender = sender
+	s.receiver = receiver
+	s.dialer = p2p.NewDialer(s.sender)
+	s.unreachable, _ = ma.NewMultiaddr("/ip4/127.0.0.1/tcp/1")
+}
+
+func (s *DialerTestSuite) TearDownTest() {
+	_ = s.sender.Close()
+	_ = s.receiver.Close()
+}
+
+func (s *DialerTestSuite) receiverAddr() ma.Multiaddr {
+	return s.sender.Peerstore().Addrs(s.receiver.ID())[0]
+}
+
+func (s *DialerTestSuite) Test_Dial_FallsBackToNextAddress() {
+	p2p.AddPeer(s.sender, &peer.AddrInfo{
+		ID:    s.receiver.ID(),
+		Addrs: []ma.Multiaddr{s.unreachable, s.receiverAddr()},
+	})
+
+	err := s.dialer.Dial(context.Background(), s.receiver.ID())
+
+	s.Nil(err)
+	s.Equal(network.Connected, s.sender.Network().Connectedness(s.receiver.ID()))
+	s.Equal(2, len(s.sender.Peerstore().Addrs(s.receiver.ID())))
+}
+
+func (s *DialerTestSuite) Test_Dial_SkipsBackedOffAddress() {
+	s.sender.Peerstore().ClearAddrs(s.receiver.ID())
+	p2p.AddPeer(s.sender, &peer.AddrInfo{
+		ID:    s.receiver.ID(),
+		Addrs: []ma.Multiaddr{s.unreachable},
+	})
+
+	err := s.dialer.Dial(context.Background(), s.receiver.ID())
+	s.NotNil(err)
+	s.Contains(err.Error(), "unable to dial peer")
+
+	err = s.dialer.Dial(context.Background(), s.receiver.ID())
+	s.NotNil(err)
+	s.Contains(err.Error(), "backed off")
+}
+
+func (s *DialerTestSuite) Test_Dial_AlreadyConnected() {
+	err := s.dialer.Dial(context.Background(), s.receiver.ID())
+	s.Nil(err)
+
+	p2p.AddPeer(s.sender, &peer.AddrInfo{
+		ID:    s.receiver.ID(),
+		Addrs: []ma.Multiaddr{s.unreachable},
+	})
+	err = s.dialer.Dial(context.Background(), s.receiver.ID())
+
+	s.Nil(err)
+}
+
+func (s *DialerTestSuite) Test_Dial_PeerWithoutAddresses() {
+	privKey, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 0)
+	unknownPeer, _ := peer.IDFromPrivateKey(privKey)
+
+	err := s.dialer.Dial(context.Background(), unknownPeer)
+
+	s.NotNil(err)
+}
+
+func (s *DialerTestSuite) Test_Dial_KeepsPeerstoreAddresses() {
+	s.sender.Peerstore().ClearAddrs(s.receiver.ID())
+	p2p.AddPeer(s.sender, &peer.AddrInfo{
+		ID:    s.receiver.ID(),
+		Addrs: []ma.Multiaddr{s.unreachable},
+	})
+	expiringAddr, _ := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/2")
+	s.sender.Peerstore().AddAddr(s.receiver.ID(), expiringAddr, time.Millisecond*100)
+
+	err := s.dialer.Dial(context.Background(), s.receiver.ID())
+	s.NotNil(err)
+	time.Sleep(time.Millisecond * 200)
+
+	s.Equal([]ma.Multiaddr{s.unreachable}, s.sender.Peerstore().Addrs(s.receiver.ID()))
+}
+
+func (s *DialerTestSuite) Test_HostDialer_SharedPerHost() {
+	s.Same(p2p.HostDialer(s.sender), p2p.HostDialer(s.sender))
+	s.NotSame(p2p.HostDialer(s.sender), p2p.HostDialer(s.receiver))
+}
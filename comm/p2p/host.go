@@ -15,6 +15,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/peerstore"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/rs/zerolog/log"
 )
 
@@ -45,6 +46,10 @@ func NewHost(privKey crypto.PrivKey, networkTopology topology.NetworkTopology, c
 	return h, nil
 }
 
+// TopologyAddrsKey is the peerstore metadata key under which topology
+// addresses of the peer are stored.
+const TopologyAddrsKey = "sygma/topology-addrs"
+
 // LoadPeers clears out peerstore and loads new peers into it
 func LoadPeers(h host.Host, peers []*peer.AddrInfo) {
 	for _, p := range h.Peerstore().Peers() {
@@ -56,6 +61,26 @@ func LoadPeers(h host.Host, peers []*peer.AddrInfo) {
 	}
 
 	for _, p := range peers {
-		h.Peerstore().AddAddr(p.ID, p.Addrs[0], peerstore.PermanentAddrTTL)
+		AddPeer(h, p)
+	}
+}
+
+// AddPeer adds all peer addresses to the peerstore and stores them as
+// topology addresses used for dialing the peer.
+func AddPeer(h host.Host, p *peer.AddrInfo) {
+	h.Peerstore().AddAddrs(p.ID, p.Addrs, peerstore.PermanentAddrTTL)
+	_ = h.Peerstore().Put(p.ID, TopologyAddrsKey, p.Addrs)
+}
+
+// TopologyAddrs returns topology addresses of the peer. If the peer was not
+// added from topology all addresses known to the peerstore are returned.
+func TopologyAddrs(h host.Host, p peer.ID) []ma.Multiaddr {
+	addrs, err := h.Peerstore().Get(p, TopologyAddrsKey)
+	if err == nil {
+		if topologyAddrs, ok := addrs.([]ma.Multiaddr); ok {
+			return topologyAddrs
+		}
 	}
+
+	return h.Peerstore().Addrs(p)
 }
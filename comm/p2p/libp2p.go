@@ -4,40 +4,62 @@
 package p2p
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"time"
 
 	comm "github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
-	madns "github.com/multiformats/go-multiaddr-dns"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 type Libp2pCommunication struct {
 	SessionSubscriptionManager
-	h             host.Host
-	protocolID    protocol.ID
-	streamManager *StreamManager
-	logger        zerolog.Logger
+	h          host.Host
+	protocolID protocol.ID
+	protocols  []WireProtocol
+	streamPool *StreamPool
+	limiter    *InboundLimiter
+	sequencer  *comm.Sequencer
+	logger     zerolog.Logger
 }
 
+// ErrMessageDropped is returned for received messages that exceed inbound limits of the sender.
+var ErrMessageDropped = errors.New("message dropped")
+
+// NewCommunication creates communication that handles the base protocol with the
+// legacy JSON wire format and its versioned protocol with the protobuf wire format.
+// Received messages are limited with DefaultMessageLimits.
 func NewCommunication(h host.Host, protocolID protocol.ID) Libp2pCommunication {
+	return NewCommunicationWithLimiter(h, protocolID, NewInboundLimiter(DefaultMessageLimits()))
+}
+
+// NewCommunicationWithLimiter creates communication that drops received messages
+// exceeding limits of the provided limiter.
+func NewCommunicationWithLimiter(h host.Host, protocolID protocol.ID, limiter *InboundLimiter) Libp2pCommunication {
 	logger := log.With().Str("Module", "communication").Str("Peer", h.ID().Pretty()).Logger()
+	protocols := WireProtocols(protocolID)
 	c := Libp2pCommunication{
 		SessionSubscriptionManager: NewSessionSubscriptionManager(),
 		h:                          h,
 		protocolID:                 protocolID,
-		streamManager:              NewStreamManager(),
+		protocols:                  protocols,
+		streamPool:                 NewStreamPool(h, protocols),
+		limiter:                    limiter,
+		sequencer:                  comm.NewSequencer(),
 		logger:                     logger,
 	}
 
 	// start processing incoming messages
-	c.h.SetStreamHandler(c.protocolID, c.StreamHandlerFunc)
+	for _, wp := range protocols {
+		c.h.SetStreamHandler(wp.ID, c.StreamHandlerFunc)
+	}
 	return c
 }
 
@@ -51,17 +73,14 @@ func (c Libp2pCommunication) Broadcast(
 	errChan chan error,
 ) {
 	hostID := c.h.ID()
-	wMsg := comm.WrappedMessage{
+	wMsg := &comm.WrappedMessage{
 		MessageType: msgType,
 		SessionID:   sessionID,
 		Payload:     msg,
+		Timestamp:   time.Now(),
+		Sequence:    c.sequencer.Next(sessionID),
 		From:        hostID,
 	}
-	marshaledMsg, err := json.Marshal(wMsg)
-	if err != nil {
-		c.logger.Error().Err(err).Str("SessionID", sessionID).Msg("unable to marshal message")
-		return
-	}
 	c.logger.Debug().Str("MsgType", msgType.String()).Str("SessionID", sessionID).Msg(
 		"broadcasting message",
 	)
@@ -70,7 +89,7 @@ func (c Libp2pCommunication) Broadcast(
 			continue // don't send message to itself
 		}
 		go func(peerID peer.ID) {
-			err := c.sendMessage(peerID, marshaledMsg, msgType, sessionID)
+			err := c.sendMessage(peerID, wMsg)
 			if err != nil {
 				SendError(errChan, err, peerID)
 				return
@@ -107,67 +126,53 @@ func (c Libp2pCommunication) UnSubscribe(
 
 func (c Libp2pCommunication) sendMessage(
 	to peer.ID,
-	msg []byte,
-	msgType comm.MessageType,
-	sessionID string,
+	msg *comm.WrappedMessage,
 ) error {
-	pi := c.h.Peerstore().PeerInfo(to)
-	resolver, err := madns.NewResolver()
-	if err != nil {
-		return err
-	}
-	if len(pi.Addrs) == 0 {
-		return fmt.Errorf("peer %s has no defined addresses", to)
-	}
-
-	addr, err := resolver.Resolve(context.Background(), pi.Addrs[0])
-	if err != nil {
-		return err
-	}
-	err = c.h.Connect(context.TODO(), peer.AddrInfo{
-		ID:    to,
-		Addrs: addr,
-	})
-	if err != nil {
-		return err
-	}
-
-	stream, err := c.h.NewStream(context.TODO(), to, c.protocolID)
+	err := c.streamPool.Send(to, msg)
 	if err != nil {
-		c.logger.Error().Err(err).Str("MsgType", msgType.String()).Str("SessionID", sessionID).Msgf(
-			"unable to open stream toward %s", to.Pretty(),
+		c.logger.Error().Err(err).Str("To", to.Pretty()).Str("MsgType", msg.MessageType.String()).Str("SessionID", msg.SessionID).Msg(
+			"unable to send message",
 		)
 		return err
 	}
-
-	err = WriteStream(msg, stream)
-	if err != nil {
-		c.logger.Error().Str("To", string(to)).Err(err).Msg("unable to send message")
-		return err
-	}
 	c.logger.Trace().Str(
 		"To", to.Pretty()).Str(
-		"MsgType", msgType.String()).Str(
-		"SessionID", sessionID).Msg(
+		"MsgType", msg.MessageType.String()).Str(
+		"SessionID", msg.SessionID).Msg(
 		"message sent",
 	)
-	c.streamManager.AddStream(sessionID, stream)
 	return nil
 }
 
+// StreamHandlerFunc reads messages from the stream until the stream is closed by the
+// remote peer or no message is received for StreamReadTimeout. Messages exceeding
+// inbound limits are dropped without closing the stream.
 func (c Libp2pCommunication) StreamHandlerFunc(s network.Stream) {
-	msg, err := c.ProcessMessageFromStream(s)
-	if err != nil {
-		c.logger.Error().Err(err).Str("StreamID", s.ID()).Msg("unable to process message")
-		return
-	}
+	defer s.Close()
+
+	for {
+		_ = s.SetReadDeadline(time.Now().Add(StreamReadTimeout))
+		msg, err := c.ProcessMessageFromStream(s)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			c.logger.Debug().Str("StreamID", s.ID()).Msg("closing idle stream")
+			_ = s.Reset()
+			return
+		}
+		if errors.Is(err, ErrMessageDropped) {
+			c.logger.Debug().Err(err).Str("From", s.Conn().RemotePeer().Pretty()).Msg("dropped message")
+			continue
+		}
+		if err != nil {
+			c.logger.Error().Err(err).Str("StreamID", s.ID()).Msg("unable to process message")
+			_ = s.Reset()
+			return
+		}
 
-	subscribers := c.GetSubscribers(msg.SessionID, msg.MessageType)
-	for _, sub := range subscribers {
-		sub := sub
-		go func() {
-			sub <- msg
-		}()
+		c.Deliver(msg)
 	}
 }
 
@@ -175,19 +180,25 @@ func (c Libp2pCommunication) ProcessMessageFromStream(s network.Stream) (*comm.W
 	remotePeerID := s.Conn().RemotePeer()
 	msgBytes, err := ReadStream(s)
 	if err != nil {
-		c.streamManager.AddStream("UNKNOWN", s)
 		return nil, err
 	}
 
-	var wrappedMsg comm.WrappedMessage
-	if err := json.Unmarshal(msgBytes, &wrappedMsg); nil != err {
-		c.streamManager.AddStream("UNKNOWN", s)
+	err = c.limiter.AllowRate(remotePeerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMessageDropped, err)
+	}
+
+	wrappedMsg, err := c.codec(s.Protocol()).Unmarshal(msgBytes)
+	if err != nil {
 		return nil, err
 	}
 
 	wrappedMsg.From = remotePeerID
 
-	c.streamManager.AddStream(wrappedMsg.SessionID, s)
+	err = c.limiter.AllowMessage(remotePeerID, wrappedMsg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMessageDropped, err)
+	}
 
 	c.logger.Trace().Str(
 		"From", wrappedMsg.From.Pretty()).Str(
@@ -196,5 +207,15 @@ func (c Libp2pCommunication) ProcessMessageFromStream(s network.Stream) (*comm.W
 		"processed message",
 	)
 
-	return &wrappedMsg, nil
+	return wrappedMsg, nil
+}
+
+// codec returns codec of the protocol, defaulting to the legacy JSON codec.
+func (c Libp2pCommunication) codec(protocolID protocol.ID) Codec {
+	for _, wp := range c.protocols {
+		if wp.ID == protocolID {
+			return wp.Codec
+		}
+	}
+	return JSONCodec{}
 }
@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/ChainSafe/sygma-relayer/comm"
@@ -47,6 +48,7 @@ func (s *Libp2pCommunicationTestSuite) TearDownTest() {}
 func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing_ValidMessage() {
 	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
 	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID+"/"+p2p.ProtobufProtocolVersion, gomock.Any()).Return()
 	c := p2p.NewCommunication(s.mockHost, s.testProtocolID)
 
 	testWrappedMsg := comm.WrappedMessage{
@@ -63,6 +65,7 @@ func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing
 	mockConn := mock_network.NewMockConn(s.mockController)
 	mockConn.EXPECT().RemotePeer().Return(s.allowedPeers[0])
 	mockStream.EXPECT().Conn().Return(mockConn)
+	mockStream.EXPECT().Protocol().Return(s.testProtocolID)
 
 	// mock stream reading
 	// on first call return header representing length of the message
@@ -92,9 +95,82 @@ func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing
 	s.Nil(messageFromStream.Payload)
 }
 
+func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing_ProtobufMessage() {
+	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID+"/"+p2p.ProtobufProtocolVersion, gomock.Any()).Return()
+	c := p2p.NewCommunication(s.mockHost, s.testProtocolID)
+
+	testWrappedMsg := &comm.WrappedMessage{
+		MessageType: comm.CoordinatorPingMsg,
+		SessionID:   "1",
+		Payload:     []byte("payload"),
+	}
+	bytes, _ := p2p.ProtobufCodec{}.Marshal(testWrappedMsg)
+
+	mockStream := mock_network.NewMockStream(s.mockController)
+	mockConn := mock_network.NewMockConn(s.mockController)
+	mockConn.EXPECT().RemotePeer().Return(s.allowedPeers[0])
+	mockStream.EXPECT().Conn().Return(mockConn)
+	mockStream.EXPECT().Protocol().Return(s.testProtocolID + "/" + p2p.ProtobufProtocolVersion)
+	firstCall := mockStream.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (n int, err error) {
+		binary.LittleEndian.PutUint32(p, uint32(len(bytes)))
+		return 4, nil
+	})
+	secondCall := mockStream.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (n int, err error) {
+		copy(p[:], bytes)
+		return len(bytes), nil
+	})
+	gomock.InOrder(firstCall, secondCall)
+
+	messageFromStream, err := c.ProcessMessageFromStream(mockStream)
+
+	s.Nil(err)
+	s.Equal(s.allowedPeers[0], messageFromStream.From)
+	s.Equal(testWrappedMsg.MessageType, messageFromStream.MessageType)
+	s.Equal(testWrappedMsg.SessionID, messageFromStream.SessionID)
+	s.Equal(testWrappedMsg.Payload, messageFromStream.Payload)
+}
+
+func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing_MessageExceedsLimits() {
+	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID+"/"+p2p.ProtobufProtocolVersion, gomock.Any()).Return()
+	limits := p2p.DefaultMessageLimits()
+	limits.MaxMessageSizes[comm.CoordinatorPingMsg] = 1
+	c := p2p.NewCommunicationWithLimiter(s.mockHost, s.testProtocolID, p2p.NewInboundLimiter(limits))
+
+	bytes, _ := p2p.ProtobufCodec{}.Marshal(&comm.WrappedMessage{
+		MessageType: comm.CoordinatorPingMsg,
+		SessionID:   "1",
+		Payload:     []byte("payload"),
+	})
+
+	mockStream := mock_network.NewMockStream(s.mockController)
+	mockConn := mock_network.NewMockConn(s.mockController)
+	mockConn.EXPECT().RemotePeer().Return(s.allowedPeers[0])
+	mockStream.EXPECT().Conn().Return(mockConn)
+	mockStream.EXPECT().Protocol().Return(s.testProtocolID + "/" + p2p.ProtobufProtocolVersion)
+	firstCall := mockStream.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (n int, err error) {
+		binary.LittleEndian.PutUint32(p, uint32(len(bytes)))
+		return 4, nil
+	})
+	secondCall := mockStream.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (n int, err error) {
+		copy(p[:], bytes)
+		return len(bytes), nil
+	})
+	gomock.InOrder(firstCall, secondCall)
+
+	messageFromStream, err := c.ProcessMessageFromStream(mockStream)
+
+	s.Nil(messageFromStream)
+	s.True(errors.Is(err, p2p.ErrMessageDropped))
+}
+
 func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing_FailOnReadingFromStream() {
 	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
 	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID+"/"+p2p.ProtobufProtocolVersion, gomock.Any()).Return()
 	c := p2p.NewCommunication(s.mockHost, s.testProtocolID)
 
 	mockStream := mock_network.NewMockStream(s.mockController)
@@ -114,6 +190,7 @@ func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing
 func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_StreamHandlerFunction_ValidMessageWithSubscribers() {
 	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
 	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID+"/"+p2p.ProtobufProtocolVersion, gomock.Any()).Return()
 	c := p2p.NewCommunication(s.mockHost, s.testProtocolID)
 
 	testWrappedMsg := comm.WrappedMessage{
@@ -146,7 +223,12 @@ func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_StreamHandlerFunc
 		copy(p[:], bytes)
 		return len(bytes), nil
 	})
-	gomock.InOrder(firstCall, secondCall)
+	// on third call return EOF as the remote peer closed the stream
+	thirdCall := mockStream.EXPECT().Read(gomock.Any()).AnyTimes().Return(0, io.EOF)
+	gomock.InOrder(firstCall, secondCall, thirdCall)
+	mockStream.EXPECT().SetReadDeadline(gomock.Any()).AnyTimes().Return(nil)
+	mockStream.EXPECT().Protocol().AnyTimes().Return(s.testProtocolID)
+	mockStream.EXPECT().Close().AnyTimes().Return(nil)
 
 	testSubChannelFirst := make(chan *comm.WrappedMessage)
 	c.Subscribe("1", comm.CoordinatorPingMsg, testSubChannelFirst)
@@ -0,0 +1,206 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+)
+
+const (
+	// ControlMessageSize is the default maximum payload size of messages without tss data.
+	ControlMessageSize = 64 * 1024
+	// dropLogInterval is the number of dropped messages of a peer between warnings.
+	dropLogInterval = 100
+)
+
+// DefaultMessageLimits returns limits used by communications created without limits.
+func DefaultMessageLimits() relayer.MessageLimitsConfig {
+	return relayer.MessageLimitsConfig{
+		PeerRate:           50,
+		PeerBurst:          500,
+		MaxSessionsPerPeer: 200,
+		SessionWindow:      time.Minute,
+		MaxMessageAge:      comm.DefaultMaxMessageAge,
+		MaxMessageSizes:    map[comm.MessageType]int{},
+	}
+}
+
+// defaultMaxMessageSize returns maximum payload size of the message type. Tss process
+// messages can contain large proofs, especially during resharing, and are only limited
+// by the stream payload limit.
+func defaultMaxMessageSize(msgType comm.MessageType) int {
+	switch msgType {
+	case comm.TssKeyGenMsg, comm.TssKeySignMsg, comm.TssReshareMsg, comm.TssPresignMsg,
+		comm.TssEdDSAKeyGenMsg, comm.TssEdDSAKeySignMsg, comm.TssEdDSAReshareMsg, comm.TssStartMsg:
+		return MaxPayload
+	default:
+		return ControlMessageSize
+	}
+}
+
+// InboundStats contains counts of accepted and dropped messages of a single peer.
+type InboundStats struct {
+	Accepted       uint64 `json:"accepted"`
+	RateLimited    uint64 `json:"rateLimited"`
+	Oversized      uint64 `json:"oversized"`
+	SessionLimited uint64 `json:"sessionLimited"`
+	Stale          uint64 `json:"stale"`
+	Replayed       uint64 `json:"replayed"`
+	Unstamped      uint64 `json:"unstamped"`
+}
+
+func (s InboundStats) dropped() uint64 {
+	return s.RateLimited + s.Oversized + s.SessionLimited + s.Stale + s.Replayed + s.Unstamped
+}
+
+type peerLimits struct {
+	tokens     float64
+	lastRefill time.Time
+	// sessionID -> time of the last message of the session
+	sessions map[string]time.Time
+	stats    InboundStats
+}
+
+// InboundLimiter limits messages received from each peer with a token bucket, maximum
+// payload size of each message type and maximum number of sessions a peer can send
+// messages for at the same time. Messages exceeding the limits are dropped, together
+// with stale and replayed messages.
+type InboundLimiter struct {
+	limits      relayer.MessageLimitsConfig
+	replayGuard *comm.ReplayGuard
+
+	lock  sync.Mutex
+	peers map[peer.ID]*peerLimits
+}
+
+func NewInboundLimiter(limits relayer.MessageLimitsConfig) *InboundLimiter {
+	return &InboundLimiter{
+		limits:      limits,
+		replayGuard: comm.NewReplayGuard(limits.MaxMessageAge, limits.RequireMessageTimestamps),
+		peers:       make(map[peer.ID]*peerLimits),
+	}
+}
+
+// AllowRate takes a token from the bucket of the peer. It is checked before the
+// message is decoded so flooding peers don't use resources for decoding.
+func (l *InboundLimiter) AllowRate(from peer.ID) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	p := l.peer(from)
+	now := time.Now()
+	p.tokens += now.Sub(p.lastRefill).Seconds() * l.limits.PeerRate
+	if p.tokens > float64(l.limits.PeerBurst) {
+		p.tokens = float64(l.limits.PeerBurst)
+	}
+	p.lastRefill = now
+
+	if p.tokens < 1 {
+		p.stats.RateLimited++
+		return l.dropped(from, p, fmt.Errorf("peer exceeded rate of %v messages per second", l.limits.PeerRate))
+	}
+	p.tokens--
+	return nil
+}
+
+// AllowMessage checks payload size, freshness and session limits of the decoded message.
+func (l *InboundLimiter) AllowMessage(from peer.ID, msg *comm.WrappedMessage) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	p := l.peer(from)
+	maxSize, ok := l.limits.MaxMessageSizes[msg.MessageType]
+	if !ok {
+		maxSize = defaultMaxMessageSize(msg.MessageType)
+	}
+	if len(msg.Payload) > maxSize {
+		p.stats.Oversized++
+		return l.dropped(from, p, fmt.Errorf(
+			"%s payload of %d bytes exceeds limit of %d bytes", msg.MessageType, len(msg.Payload), maxSize,
+		))
+	}
+
+	now := time.Now()
+	err := l.replayGuard.Check(msg, now)
+	if errors.Is(err, comm.ErrStaleMessage) {
+		p.stats.Stale++
+		return l.dropped(from, p, err)
+	}
+	if errors.Is(err, comm.ErrUnstampedMessage) {
+		p.stats.Unstamped++
+		return l.dropped(from, p, err)
+	}
+	if err != nil {
+		p.stats.Replayed++
+		return l.dropped(from, p, err)
+	}
+
+	for sessionID, lastSeen := range p.sessions {
+		if now.Sub(lastSeen) > l.limits.SessionWindow {
+			delete(p.sessions, sessionID)
+		}
+	}
+	_, known := p.sessions[msg.SessionID]
+	if !known && len(p.sessions) >= l.limits.MaxSessionsPerPeer {
+		p.stats.SessionLimited++
+		return l.dropped(from, p, fmt.Errorf(
+			"peer exceeded limit of %d sessions, dropped message of session %s", l.limits.MaxSessionsPerPeer, msg.SessionID,
+		))
+	}
+	p.sessions[msg.SessionID] = now
+
+	p.stats.Accepted++
+	return nil
+}
+
+// Stats returns inbound message counts of all peers that sent messages.
+func (l *InboundLimiter) Stats() map[string]InboundStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	stats := make(map[string]InboundStats)
+	for p, limits := range l.peers {
+		stats[p.Pretty()] = limits.stats
+	}
+	return stats
+}
+
+// ServeHTTP returns inbound message counts of all peers as JSON.
+func (l *InboundLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(l.Stats())
+}
+
+func (l *InboundLimiter) peer(from peer.ID) *peerLimits {
+	p, ok := l.peers[from]
+	if !ok {
+		p = &peerLimits{
+			tokens:     float64(l.limits.PeerBurst),
+			lastRefill: time.Now(),
+			sessions:   make(map[string]time.Time),
+		}
+		l.peers[from] = p
+	}
+	return p
+}
+
+// dropped logs the first and then every dropLogInterval dropped message of the peer.
+func (l *InboundLimiter) dropped(from peer.ID, p *peerLimits, err error) error {
+	dropped := p.stats.dropped()
+	if dropped == 1 || dropped%dropLogInterval == 0 {
+		log.Warn().Err(err).Str("Peer", from.Pretty()).Msgf("Dropped %d messages from peer", dropped)
+	}
+	return err
+}
@@ -0,0 +1,172 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+)
+
+type InboundLimiterTestSuite struct {
+	suite.Suite
+	peer1  peer.ID
+	peer2  peer.ID
+	limits relayer.MessageLimitsConfig
+}
+
+func TestRunInboundLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(InboundLimiterTestSuite))
+}
+
+func (s *InboundLimiterTestSuite) SetupTest() {
+	s.peer1, _ = peer.Decode("QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX")
+	s.peer2, _ = peer.Decode("QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT")
+	s.limits = p2p.DefaultMessageLimits()
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowRate_BurstExceeded() {
+	s.limits.PeerRate = 0.001
+	s.limits.PeerBurst = 2
+	limiter := p2p.NewInboundLimiter(s.limits)
+
+	s.Nil(limiter.AllowRate(s.peer1))
+	s.Nil(limiter.AllowRate(s.peer1))
+	s.NotNil(limiter.AllowRate(s.peer1))
+	s.Nil(limiter.AllowRate(s.peer2))
+
+	s.Equal(uint64(1), limiter.Stats()[s.peer1.Pretty()].RateLimited)
+	s.Equal(uint64(0), limiter.Stats()[s.peer2.Pretty()].RateLimited)
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowRate_TokensRefilled() {
+	s.limits.PeerRate = 1000
+	s.limits.PeerBurst = 1
+	limiter := p2p.NewInboundLimiter(s.limits)
+
+	s.Nil(limiter.AllowRate(s.peer1))
+	time.Sleep(time.Millisecond * 10)
+	s.Nil(limiter.AllowRate(s.peer1))
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowMessage_Oversized() {
+	limiter := p2p.NewInboundLimiter(s.limits)
+
+	err := limiter.AllowMessage(s.peer1, &comm.WrappedMessage{
+		MessageType: comm.CoordinatorPingMsg,
+		SessionID:   "1",
+		Payload:     make([]byte, p2p.ControlMessageSize+1),
+	})
+	s.NotNil(err)
+
+	err = limiter.AllowMessage(s.peer1, &comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "1",
+		Payload:     make([]byte, p2p.ControlMessageSize+1),
+	})
+	s.Nil(err)
+
+	stats := limiter.Stats()[s.peer1.Pretty()]
+	s.Equal(uint64(1), stats.Oversized)
+	s.Equal(uint64(1), stats.Accepted)
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowMessage_SizeOverride() {
+	s.limits.MaxMessageSizes = map[comm.MessageType]int{comm.TssKeySignMsg: 10}
+	limiter := p2p.NewInboundLimiter(s.limits)
+
+	err := limiter.AllowMessage(s.peer1, &comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "1",
+		Payload:     make([]byte, 11),
+	})
+
+	s.NotNil(err)
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowMessage_SessionLimitExceeded() {
+	s.limits.MaxSessionsPerPeer = 2
+	limiter := p2p.NewInboundLimiter(s.limits)
+
+	s.Nil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "1"}))
+	s.Nil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "2"}))
+	s.NotNil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "3"}))
+	// messages of known sessions and from other peers are still accepted
+	s.Nil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "1"}))
+	s.Nil(limiter.AllowMessage(s.peer2, &comm.WrappedMessage{SessionID: "3"}))
+
+	s.Equal(uint64(1), limiter.Stats()[s.peer1.Pretty()].SessionLimited)
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowMessage_SessionsExpire() {
+	s.limits.MaxSessionsPerPeer = 1
+	s.limits.SessionWindow = time.Millisecond * 10
+	limiter := p2p.NewInboundLimiter(s.limits)
+
+	s.Nil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "1"}))
+	s.NotNil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "2"}))
+	time.Sleep(time.Millisecond * 20)
+	s.Nil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "2"}))
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowMessage_StaleAndReplayedMessages() {
+	limiter := p2p.NewInboundLimiter(s.limits)
+	msg := &comm.WrappedMessage{
+		MessageType: comm.CoordinatorSelectMsg,
+		SessionID:   "1",
+		Timestamp:   time.Now(),
+		Sequence:    1,
+	}
+
+	s.Nil(limiter.AllowMessage(s.peer1, msg))
+	s.NotNil(limiter.AllowMessage(s.peer1, msg))
+	s.NotNil(limiter.AllowMessage(s.peer1, &comm.WrappedMessage{
+		MessageType: comm.CoordinatorSelectMsg,
+		SessionID:   "1",
+		Timestamp:   time.Now().Add(-time.Hour),
+		Sequence:    2,
+	}))
+
+	stats := limiter.Stats()[s.peer1.Pretty()]
+	s.Equal(uint64(1), stats.Replayed)
+	s.Equal(uint64(1), stats.Stale)
+	s.Equal(uint64(1), stats.Accepted)
+}
+
+func (s *InboundLimiterTestSuite) Test_AllowMessage_UnstampedMessages() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.CoordinatorSelectMsg,
+		SessionID:   "1",
+	}
+
+	s.Nil(p2p.NewInboundLimiter(s.limits).AllowMessage(s.peer1, msg))
+
+	s.limits.RequireMessageTimestamps = true
+	limiter := p2p.NewInboundLimiter(s.limits)
+	s.NotNil(limiter.AllowMessage(s.peer1, msg))
+	s.Equal(uint64(1), limiter.Stats()[s.peer1.Pretty()].Unstamped)
+}
+
+func (s *InboundLimiterTestSuite) Test_ServeHTTP_ReturnsStats() {
+	limiter := p2p.NewInboundLimiter(s.limits)
+	_ = limiter.AllowMessage(s.peer1, &comm.WrappedMessage{SessionID: "1"})
+
+	recorder := httptest.NewRecorder()
+	limiter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/inbound", nil))
+
+	s.Equal(http.StatusOK, recorder.Code)
+	stats := make(map[string]p2p.InboundStats)
+	err := json.Unmarshal(recorder.Body.Bytes(), &stats)
+	s.Nil(err)
+	s.Equal(p2p.InboundStats{Accepted: 1}, stats[s.peer1.Pretty()])
+}
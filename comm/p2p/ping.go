@@ -0,0 +1,44 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+)
+
+// Pinger measures round trip time to peers with the libp2p ping protocol.
+type Pinger struct {
+	h      host.Host
+	dialer *Dialer
+}
+
+func NewPinger(h host.Host) *Pinger {
+	return &Pinger{
+		h:      h,
+		dialer: HostDialer(h),
+	}
+}
+
+// Ping dials the peer if it is not connected and returns the round trip time
+// of a single ping.
+func (p *Pinger) Ping(ctx context.Context, to peer.ID) (time.Duration, error) {
+	err := p.dialer.Dial(ctx, to)
+	if err != nil {
+		return 0, err
+	}
+
+	pingCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	result, ok := <-ping.Ping(pingCtx, p.h, to)
+	if !ok {
+		return 0, fmt.Errorf("ping to peer %s canceled", to)
+	}
+	return result.RTT, result.Error
+}
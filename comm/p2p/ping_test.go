@@ -0,0 +1,56 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+)
+
+type PingerTestSuite struct {
+	suite.Suite
+	sender   host.Host
+	receiver host.Host
+	pinger   *p2p.Pinger
+}
+
+func TestRunPingerTestSuite(t *testing.T) {
+	suite.Run(t, new(PingerTestSuite))
+}
+
+func (s *PingerTestSuite) SetupTest() {
+	sender, receiver, err := newConnectedHosts()
+	s.Nil(err)
+	s.sender = sender
+	s.receiver = receiver
+	s.pinger = p2p.NewPinger(s.sender)
+}
+
+func (s *PingerTestSuite) TearDownTest() {
+	_ = s.sender.Close()
+	_ = s.receiver.Close()
+}
+
+func (s *PingerTestSuite) Test_Ping_ReachablePeer() {
+	rtt, err := s.pinger.Ping(context.Background(), s.receiver.ID())
+
+	s.Nil(err)
+	s.NotZero(rtt)
+}
+
+func (s *PingerTestSuite) Test_Ping_UnknownPeer() {
+	privKey, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 0)
+	unknownPeer, _ := peer.IDFromPrivateKey(privKey)
+
+	_, err := s.pinger.Ping(context.Background(), unknownPeer)
+
+	s.NotNil(err)
+}
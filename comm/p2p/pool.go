@@ -0,0 +1,163 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+)
+
+const (
+	// StreamIdleTimeout is the duration after which an unused outbound stream is replaced
+	// with a new one. It is shorter than StreamReadTimeout so streams are replaced before
+	// the receiving peer closes them.
+	StreamIdleTimeout = 2 * time.Minute
+	// StreamReadTimeout is the duration after which an inbound stream without messages is closed.
+	StreamReadTimeout = 5 * time.Minute
+)
+
+type peerStream struct {
+	lock     sync.Mutex
+	stream   network.Stream
+	codec    Codec
+	lastUsed time.Time
+}
+
+// StreamPool keeps one outbound stream per peer and reuses it for all messages sent
+// to the peer. Broken streams are replaced with a new stream on the next message.
+// Messages are encoded with the codec of the protocol negotiated for the stream.
+// Streams of protocols that are not pooled are closed after sending a single message.
+type StreamPool struct {
+	h         host.Host
+	protocols []WireProtocol
+	dialer    *Dialer
+
+	lock    sync.Mutex
+	streams map[peer.ID]*peerStream
+}
+
+// NewStreamPool creates stream pool that opens streams with the first of the
+// provided protocols supported by the peer.
+func NewStreamPool(h host.Host, protocols []WireProtocol) *StreamPool {
+	return &StreamPool{
+		h:         h,
+		protocols: protocols,
+		dialer:    HostDialer(h),
+		streams:   make(map[peer.ID]*peerStream),
+	}
+}
+
+// Send writes the message to the pooled stream of the peer. If writing to the pooled
+// stream fails the stream is reset and the message is sent over a new stream.
+// If the peer only supports a protocol that is not pooled the message is sent
+// over a new stream that is closed afterwards.
+func (p *StreamPool) Send(to peer.ID, msg *comm.WrappedMessage) error {
+	ps := p.peerStream(to)
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if ps.stream != nil && time.Since(ps.lastUsed) > StreamIdleTimeout {
+		_ = ps.stream.Close()
+		ps.stream = nil
+	}
+	if ps.stream != nil {
+		data, err := ps.codec.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		err = WriteStream(data, ps.stream)
+		if err == nil {
+			ps.lastUsed = time.Now()
+			return nil
+		}
+
+		_ = ps.stream.Reset()
+		ps.stream = nil
+	}
+
+	stream, wp, err := p.newStream(to)
+	if err != nil {
+		return err
+	}
+	data, err := wp.Codec.Marshal(msg)
+	if err != nil {
+		_ = stream.Reset()
+		return err
+	}
+	err = WriteStream(data, stream)
+	if err != nil {
+		_ = stream.Reset()
+		return err
+	}
+	if !wp.Pooled {
+		return stream.Close()
+	}
+
+	ps.stream = stream
+	ps.codec = wp.Codec
+	ps.lastUsed = time.Now()
+	return nil
+}
+
+// Close closes all pooled streams.
+func (p *StreamPool) Close() {
+	p.lock.Lock()
+	streams := p.streams
+	p.streams = make(map[peer.ID]*peerStream)
+	p.lock.Unlock()
+
+	for _, ps := range streams {
+		ps.lock.Lock()
+		if ps.stream != nil {
+			_ = ps.stream.Close()
+			ps.stream = nil
+		}
+		ps.lock.Unlock()
+	}
+}
+
+func (p *StreamPool) peerStream(to peer.ID) *peerStream {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	ps, ok := p.streams[to]
+	if !ok {
+		ps = &peerStream{}
+		p.streams[to] = ps
+	}
+	return ps
+}
+
+func (p *StreamPool) newStream(to peer.ID) (network.Stream, WireProtocol, error) {
+	err := p.dialer.Dial(context.TODO(), to)
+	if err != nil {
+		return nil, WireProtocol{}, err
+	}
+
+	protocolIDs := []protocol.ID{}
+	for _, wp := range p.protocols {
+		protocolIDs = append(protocolIDs, wp.ID)
+	}
+	stream, err := p.h.NewStream(context.TODO(), to, protocolIDs...)
+	if err != nil {
+		return nil, WireProtocol{}, err
+	}
+	for _, wp := range p.protocols {
+		if wp.ID == stream.Protocol() {
+			return stream, wp, nil
+		}
+	}
+
+	_ = stream.Reset()
+	return nil, WireProtocol{}, fmt.Errorf("unsupported protocol %s negotiated with peer %s", stream.Protocol(), to)
+}
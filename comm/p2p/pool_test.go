@@ -0,0 +1,364 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package p2p_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ma "github.com/multiformats/go-multiaddr"
+	madns "github.com/multiformats/go-multiaddr-dns"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/topology"
+)
+
+const poolProtocolID protocol.ID = "test/pool"
+
+// newConnectedHosts creates two hosts that know each others local address.
+func newConnectedHosts() (host.Host, host.Host, error) {
+	hosts := []host.Host{}
+	gates := []*p2p.ConnectionGate{}
+	for i := 0; i < 2; i++ {
+		privKey, _, err := crypto.GenerateKeyPair(crypto.ECDSA, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		gate := p2p.NewConnectionGate(topology.NetworkTopology{})
+		h, err := p2p.NewHost(privKey, topology.NetworkTopology{}, gate, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		hosts = append(hosts, h)
+		gates = append(gates, gate)
+	}
+
+	networkTopology := topology.NetworkTopology{}
+	for _, h := range hosts {
+		port, _ := h.Addrs()[0].ValueForProtocol(ma.P_TCP)
+		addr, _ := ma.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/%s", port))
+		networkTopology.Peers = append(networkTopology.Peers, &peer.AddrInfo{ID: h.ID(), Addrs: []ma.Multiaddr{addr}})
+	}
+	for i, h := range hosts {
+		gates[i].SetTopology(networkTopology)
+		other := networkTopology.Peers[1-i]
+		h.Peerstore().AddAddr(other.ID, other.Addrs[0], peerstore.PermanentAddrTTL)
+	}
+	return hosts[0], hosts[1], nil
+}
+
+type streamRecorder struct {
+	lock      sync.Mutex
+	streams   int
+	protocols []protocol.ID
+	messages  [][]byte
+}
+
+// register handles all wire protocols of the base protocol on the host.
+func (r *streamRecorder) register(h host.Host, protocols []p2p.WireProtocol) {
+	for _, wp := range protocols {
+		codec := wp.Codec
+		h.SetStreamHandler(wp.ID, func(s network.Stream) {
+			r.handle(s, codec)
+		})
+	}
+}
+
+func (r *streamRecorder) handle(s network.Stream, codec p2p.Codec) {
+	r.lock.Lock()
+	r.streams++
+	r.protocols = append(r.protocols, s.Protocol())
+	r.lock.Unlock()
+
+	for {
+		data, err := p2p.ReadStream(s)
+		if err != nil {
+			_ = s.Close()
+			return
+		}
+		msg, err := codec.Unmarshal(data)
+		if err != nil {
+			_ = s.Reset()
+			return
+		}
+
+		r.lock.Lock()
+		r.messages = append(r.messages, msg.Payload)
+		r.lock.Unlock()
+	}
+}
+
+// handleLegacy handles the stream the way legacy relayers did, reading a single JSON
+// encoded message from the stream and leaving the rest of the stream unread.
+func (r *streamRecorder) handleLegacy(s network.Stream) {
+	r.lock.Lock()
+	r.streams++
+	r.protocols = append(r.protocols, s.Protocol())
+	r.lock.Unlock()
+
+	data, err := p2p.ReadStream(s)
+	if err != nil {
+		return
+	}
+	msg := comm.WrappedMessage{}
+	err = json.Unmarshal(data, &msg)
+	if err != nil {
+		return
+	}
+
+	r.lock.Lock()
+	r.messages = append(r.messages, msg.Payload)
+	r.lock.Unlock()
+}
+
+func (r *streamRecorder) counts() (int, int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.streams, len(r.messages)
+}
+
+type StreamPoolTestSuite struct {
+	suite.Suite
+	sender   host.Host
+	receiver host.Host
+	recorder *streamRecorder
+	pool     *p2p.StreamPool
+}
+
+func TestRunStreamPoolTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamPoolTestSuite))
+}
+
+func (s *StreamPoolTestSuite) SetupTest() {
+	sender, receiver, err := newConnectedHosts()
+	s.Nil(err)
+	s.sender = sender
+	s.receiver = receiver
+	s.recorder = &streamRecorder{}
+	s.recorder.register(s.receiver, p2p.WireProtocols(poolProtocolID))
+	s.pool = p2p.NewStreamPool(s.sender, p2p.WireProtocols(poolProtocolID))
+}
+
+func (s *StreamPoolTestSuite) message(payload string) *comm.WrappedMessage {
+	return &comm.WrappedMessage{
+		MessageType: comm.CoordinatorPingMsg,
+		SessionID:   "session",
+		Payload:     []byte(payload),
+	}
+}
+
+func (s *StreamPoolTestSuite) TearDownTest() {
+	s.pool.Close()
+	_ = s.sender.Close()
+	_ = s.receiver.Close()
+}
+
+func (s *StreamPoolTestSuite) Test_Send_ReusesStream() {
+	for i := 0; i < 3; i++ {
+		err := s.pool.Send(s.receiver.ID(), s.message(fmt.Sprintf("message %d", i)))
+		s.Nil(err)
+	}
+
+	s.Eventually(func() bool {
+		_, messages := s.recorder.counts()
+		return messages == 3
+	}, time.Second*5, time.Millisecond*10)
+	streams, _ := s.recorder.counts()
+	s.Equal(1, streams)
+	s.Equal([]byte("message 0"), s.recorder.messages[0])
+	s.Equal([]byte("message 2"), s.recorder.messages[2])
+}
+
+func (s *StreamPoolTestSuite) Test_Send_RebuildsBrokenStream() {
+	err := s.pool.Send(s.receiver.ID(), s.message("message 0"))
+	s.Nil(err)
+	s.Eventually(func() bool {
+		_, messages := s.recorder.counts()
+		return messages == 1
+	}, time.Second*5, time.Millisecond*10)
+
+	_ = s.receiver.Network().ClosePeer(s.sender.ID())
+	s.Eventually(func() bool {
+		return s.sender.Network().Connectedness(s.receiver.ID()) != network.Connected
+	}, time.Second*5, time.Millisecond*10)
+
+	err = s.pool.Send(s.receiver.ID(), s.message("message 1"))
+	s.Nil(err)
+	s.Eventually(func() bool {
+		_, messages := s.recorder.counts()
+		return messages == 2
+	}, time.Second*5, time.Millisecond*10)
+	streams, _ := s.recorder.counts()
+	s.Equal(2, streams)
+}
+
+func (s *StreamPoolTestSuite) Test_Send_PeerWithoutAddresses() {
+	privKey, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 0)
+	unknownPeer, _ := peer.IDFromPrivateKey(privKey)
+
+	err := s.pool.Send(unknownPeer, s.message("message"))
+
+	s.NotNil(err)
+}
+
+func (s *StreamPoolTestSuite) Test_Send_NegotiatesProtobufProtocol() {
+	err := s.pool.Send(s.receiver.ID(), s.message("message"))
+	s.Nil(err)
+
+	s.Eventually(func() bool {
+		_, messages := s.recorder.counts()
+		return messages == 1
+	}, time.Second*5, time.Millisecond*10)
+	s.Equal(p2p.WireProtocols(poolProtocolID)[0].ID, s.recorder.protocols[0])
+	s.Equal([]byte("message"), s.recorder.messages[0])
+}
+
+func (s *StreamPoolTestSuite) Test_Send_FallsBackToLegacyProtocol() {
+	legacyProtocols := p2p.WireProtocols(poolProtocolID)[1:]
+	for _, wp := range p2p.WireProtocols(poolProtocolID) {
+		s.receiver.RemoveStreamHandler(wp.ID)
+	}
+	s.recorder.register(s.receiver, legacyProtocols)
+
+	err := s.pool.Send(s.receiver.ID(), s.message("message"))
+	s.Nil(err)
+
+	s.Eventually(func() bool {
+		_, messages := s.recorder.counts()
+		return messages == 1
+	}, time.Second*5, time.Millisecond*10)
+	s.Equal(poolProtocolID, s.recorder.protocols[0])
+	s.Equal([]byte("message"), s.recorder.messages[0])
+}
+
+func (s *StreamPoolTestSuite) Test_Send_LegacyProtocolOpensStreamPerMessage() {
+	for _, wp := range p2p.WireProtocols(poolProtocolID) {
+		s.receiver.RemoveStreamHandler(wp.ID)
+	}
+	s.recorder.register(s.receiver, p2p.WireProtocols(poolProtocolID)[1:])
+
+	for i := 0; i < 3; i++ {
+		err := s.pool.Send(s.receiver.ID(), s.message(fmt.Sprintf("message %d", i)))
+		s.Nil(err)
+	}
+
+	s.Eventually(func() bool {
+		_, messages := s.recorder.counts()
+		return messages == 3
+	}, time.Second*5, time.Millisecond*10)
+	streams, _ := s.recorder.counts()
+	s.Equal(3, streams)
+}
+
+func (s *StreamPoolTestSuite) Test_Send_LegacyRelayerReceivesAllMessages() {
+	for _, wp := range p2p.WireProtocols(poolProtocolID) {
+		s.receiver.RemoveStreamHandler(wp.ID)
+	}
+	s.receiver.SetStreamHandler(poolProtocolID, s.recorder.handleLegacy)
+
+	for i := 0; i < 3; i++ {
+		err := s.pool.Send(s.receiver.ID(), &comm.WrappedMessage{
+			MessageType: comm.TssKeySignMsg,
+			SessionID:   "session",
+			Payload:     comm.MarshalTssPayload([]byte{byte(i)}, true),
+		})
+		s.Nil(err)
+	}
+
+	s.Eventually(func() bool {
+		_, messages := s.recorder.counts()
+		return messages == 3
+	}, time.Second*5, time.Millisecond*10)
+	s.Equal(poolProtocolID, s.recorder.protocols[0])
+	for i := 0; i < 3; i++ {
+		msgBytes, isBroadcast, err := comm.UnmarshalTssPayload(s.recorder.messages[i])
+		s.Nil(err)
+		s.True(isBroadcast)
+		s.Contains([][]byte{{0}, {1}, {2}}, msgBytes)
+	}
+}
+
+func benchmarkHosts(b *testing.B) (host.Host, host.Host) {
+	sender, receiver, err := newConnectedHosts()
+	if err != nil {
+		b.Fatal(err)
+	}
+	recorder := &streamRecorder{}
+	recorder.register(receiver, p2p.WireProtocols(poolProtocolID))
+	b.Cleanup(func() {
+		_ = sender.Close()
+		_ = receiver.Close()
+	})
+	return sender, receiver
+}
+
+// BenchmarkSend_NewStreamPerMessage measures sending messages the way it was done before
+// the stream pool, resolving the peer address and opening a new stream for every message.
+func BenchmarkSend_NewStreamPerMessage(b *testing.B) {
+	sender, receiver := benchmarkHosts(b)
+	msg := &comm.WrappedMessage{Payload: make([]byte, 1024)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pi := sender.Peerstore().PeerInfo(receiver.ID())
+		resolver, err := madns.NewResolver()
+		if err != nil {
+			b.Fatal(err)
+		}
+		addrs, err := resolver.Resolve(context.Background(), pi.Addrs[0])
+		if err != nil {
+			b.Fatal(err)
+		}
+		err = sender.Connect(context.TODO(), peer.AddrInfo{ID: receiver.ID(), Addrs: addrs})
+		if err != nil {
+			b.Fatal(err)
+		}
+		stream, err := sender.NewStream(context.TODO(), receiver.ID(), poolProtocolID)
+		if err != nil {
+			b.Fatal(err)
+		}
+		data, err := json.Marshal(msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		err = p2p.WriteStream(data, stream)
+		if err != nil {
+			b.Fatal(err)
+		}
+		// wait until the receiver is done with the stream so the number of
+		// pending streams stays bounded
+		_ = stream.CloseWrite()
+		_, _ = io.ReadAll(stream)
+		_ = stream.Close()
+	}
+}
+
+func BenchmarkSend_StreamPool(b *testing.B) {
+	sender, receiver := benchmarkHosts(b)
+	pool := p2p.NewStreamPool(sender, p2p.WireProtocols(poolProtocolID))
+	defer pool.Close()
+	msg := &comm.WrappedMessage{Payload: make([]byte, 1024)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := pool.Send(receiver.ID(), msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
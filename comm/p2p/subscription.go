@@ -4,27 +4,79 @@
 package p2p
 
 import (
+	"encoding/json"
+	"net/http"
 	"sync"
+	"time"
 
 	comm "github.com/ChainSafe/sygma-relayer/comm"
 )
 
 // SessionSubscriptionManager manages channel subscriptions by comm.SessionID
+//
+// Messages delivered before anyone subscribed to their session and type are buffered
+// and replayed to the first subscriber. Messages of a session and type whose subscribers
+// already unsubscribed are dropped, as retried tss processes reuse the session ID and
+// late messages of the failed attempt must not be replayed into the retry.
 type SessionSubscriptionManager struct {
 	lock *sync.Mutex
 	// sessionID -> messageType -> subscriptionID
 	subscribersMap map[string]map[comm.MessageType]map[string]chan *comm.WrappedMessage
+	buffer         *messageBuffer
 }
 
 func NewSessionSubscriptionManager() SessionSubscriptionManager {
+	return NewSessionSubscriptionManagerWithBuffer(MessageBufferTTL, MessageBufferSize, MessageBufferMaxBytes)
+}
+
+// NewSessionSubscriptionManagerWithBuffer creates subscription manager that buffers up to
+// size messages per session without subscribers for the ttl duration. Oldest messages
+// are evicted when payloads of all buffered messages exceed maxBytes.
+func NewSessionSubscriptionManagerWithBuffer(ttl time.Duration, size int, maxBytes int) SessionSubscriptionManager {
 	return SessionSubscriptionManager{
 		lock: &sync.Mutex{},
 		subscribersMap: make(
 			map[string]map[comm.MessageType]map[string]chan *comm.WrappedMessage,
 		),
+		buffer: newMessageBuffer(ttl, size, maxBytes),
+	}
+}
+
+// Deliver sends the message to all subscribers of its session and type. If no one
+// subscribed to the session and type yet the message is buffered until someone subscribes.
+func (ms *SessionSubscriptionManager) Deliver(msg *comm.WrappedMessage) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	subs, subscribed := ms.subscribersMap[msg.SessionID][msg.MessageType]
+	if !subscribed {
+		ms.buffer.add(msg)
+		return
+	}
+
+	for _, sub := range subs {
+		sub := sub
+		go func() {
+			sub <- msg
+		}()
 	}
 }
 
+// BufferStats returns counts of buffered, replayed and expired messages.
+func (ms *SessionSubscriptionManager) BufferStats() BufferStats {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	ms.buffer.expire()
+	return ms.buffer.stats
+}
+
+// ServeHTTP returns message buffer stats as JSON.
+func (ms SessionSubscriptionManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(ms.BufferStats())
+}
+
 func (ms *SessionSubscriptionManager) GetSubscribers(
 	sessionID string,
 	msgType comm.MessageType,
@@ -62,6 +114,16 @@ func (ms *SessionSubscriptionManager) SubscribeTo(
 
 	subID := comm.NewSubscriptionID(sessionID, msgType)
 	ms.subscribersMap[sessionID][msgType][subID.SubscriptionIdentifier()] = channel
+
+	// replay messages received before the subscription in the order they were received
+	pending := ms.buffer.take(sessionID, msgType)
+	if len(pending) > 0 {
+		go func() {
+			for _, msg := range pending {
+				channel <- msg
+			}
+		}()
+	}
 	return subID
 }
 
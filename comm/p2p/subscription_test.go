@@ -4,8 +4,13 @@
 package p2p_test
 
 import (
-	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 
 	comm "github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/stretchr/testify/suite"
@@ -66,3 +71,133 @@ func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Man
 	subscribers = subscriptionManager.GetSubscribers("2", comm.CoordinatorPingMsg)
 	s.Len(subscribers, 0)
 }
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Deliver_Subscribed() {
+	subscriptionManager := p2p.NewSessionSubscriptionManager()
+	sChannel := make(chan *comm.WrappedMessage)
+	subscriptionManager.SubscribeTo("1", comm.CoordinatorPingMsg, sChannel)
+
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.CoordinatorPingMsg})
+
+	msg := <-sChannel
+	s.Equal("1", msg.SessionID)
+	s.Equal(uint64(0), subscriptionManager.BufferStats().Buffered)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Deliver_ReplaysEarlyMessages() {
+	subscriptionManager := p2p.NewSessionSubscriptionManager()
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeyGenMsg, Payload: []byte("1")})
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeyGenMsg, Payload: []byte("2")})
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.CoordinatorPingMsg})
+
+	sChannel := make(chan *comm.WrappedMessage)
+	subscriptionManager.SubscribeTo("1", comm.TssKeyGenMsg, sChannel)
+
+	s.Equal([]byte("1"), (<-sChannel).Payload)
+	s.Equal([]byte("2"), (<-sChannel).Payload)
+	stats := subscriptionManager.BufferStats()
+	s.Equal(uint64(3), stats.Buffered)
+	s.Equal(uint64(2), stats.Replayed)
+	s.Equal(1, stats.Pending)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Deliver_FailedAttemptMessagesNotReplayedToRetry() {
+	subscriptionManager := p2p.NewSessionSubscriptionManager()
+	// first attempt of the session fails and its subscriptions are removed
+	readyChannel := make(chan *comm.WrappedMessage, 1)
+	readySubID := subscriptionManager.SubscribeTo("1", comm.TssReadyMsg, readyChannel)
+	signChannel := make(chan *comm.WrappedMessage, 1)
+	signSubID := subscriptionManager.SubscribeTo("1", comm.TssKeySignMsg, signChannel)
+	subscriptionManager.UnSubscribeFrom(readySubID)
+	subscriptionManager.UnSubscribeFrom(signSubID)
+	// late messages of the failed attempt
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssReadyMsg})
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeySignMsg})
+
+	// retry subscribes with the same session ID
+	retryReadyChannel := make(chan *comm.WrappedMessage, 1)
+	subscriptionManager.SubscribeTo("1", comm.TssReadyMsg, retryReadyChannel)
+	retrySignChannel := make(chan *comm.WrappedMessage, 1)
+	subscriptionManager.SubscribeTo("1", comm.TssKeySignMsg, retrySignChannel)
+
+	time.Sleep(time.Millisecond * 10)
+	s.Len(retryReadyChannel, 0)
+	s.Len(retrySignChannel, 0)
+	s.Len(readyChannel, 0)
+	s.Len(signChannel, 0)
+	stats := subscriptionManager.BufferStats()
+	s.Equal(uint64(0), stats.Buffered)
+	s.Equal(0, stats.Pending)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Deliver_ExpiredMessagesNotReplayed() {
+	subscriptionManager := p2p.NewSessionSubscriptionManagerWithBuffer(time.Millisecond*10, 10, p2p.MessageBufferMaxBytes)
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeyGenMsg})
+
+	time.Sleep(time.Millisecond * 20)
+	sChannel := make(chan *comm.WrappedMessage, 1)
+	subscriptionManager.SubscribeTo("1", comm.TssKeyGenMsg, sChannel)
+
+	time.Sleep(time.Millisecond * 10)
+	s.Len(sChannel, 0)
+	stats := subscriptionManager.BufferStats()
+	s.Equal(uint64(1), stats.Expired)
+	s.Equal(uint64(0), stats.Replayed)
+	s.Equal(0, stats.Pending)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Deliver_FullBufferDropsMessages() {
+	subscriptionManager := p2p.NewSessionSubscriptionManagerWithBuffer(time.Minute, 2, p2p.MessageBufferMaxBytes)
+	for i := 0; i < 3; i++ {
+		subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeyGenMsg})
+	}
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "2", MessageType: comm.TssKeyGenMsg})
+
+	stats := subscriptionManager.BufferStats()
+	s.Equal(uint64(3), stats.Buffered)
+	s.Equal(uint64(1), stats.Dropped)
+	s.Equal(3, stats.Pending)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Deliver_FullBufferEvictsOldestMessages() {
+	subscriptionManager := p2p.NewSessionSubscriptionManagerWithBuffer(time.Minute, 10, 4)
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeyGenMsg, Payload: []byte("1")})
+	time.Sleep(time.Millisecond)
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "2", MessageType: comm.TssKeyGenMsg, Payload: []byte("2")})
+	time.Sleep(time.Millisecond)
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeyGenMsg, Payload: []byte("3")})
+	time.Sleep(time.Millisecond)
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "3", MessageType: comm.TssKeyGenMsg, Payload: []byte("456")})
+	// messages larger than the whole buffer are dropped without evicting others
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "4", MessageType: comm.TssKeyGenMsg, Payload: []byte("67890")})
+
+	stats := subscriptionManager.BufferStats()
+	s.Equal(uint64(4), stats.Buffered)
+	s.Equal(uint64(2), stats.Evicted)
+	s.Equal(uint64(1), stats.Dropped)
+	s.Equal(2, stats.Pending)
+	s.Equal(4, stats.PendingBytes)
+
+	session1 := make(chan *comm.WrappedMessage, 2)
+	subscriptionManager.SubscribeTo("1", comm.TssKeyGenMsg, session1)
+	s.Equal([]byte("3"), (<-session1).Payload)
+	session2 := make(chan *comm.WrappedMessage, 1)
+	subscriptionManager.SubscribeTo("2", comm.TssKeyGenMsg, session2)
+	time.Sleep(time.Millisecond * 10)
+	s.Len(session2, 0)
+	s.Len(session1, 0)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_ServeHTTP() {
+	subscriptionManager := p2p.NewSessionSubscriptionManager()
+	subscriptionManager.Deliver(&comm.WrappedMessage{SessionID: "1", MessageType: comm.TssKeyGenMsg})
+
+	recorder := httptest.NewRecorder()
+	subscriptionManager.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/messages", nil))
+
+	stats := p2p.BufferStats{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &stats)
+	s.Nil(err)
+	s.Equal(uint64(1), stats.Buffered)
+	s.Equal(1, stats.Pending)
+}
@@ -19,11 +19,11 @@ const (
 	MaxPayload   = 20000000 // 20M
 )
 
-// ReadStream reads data from the given stream
+// ReadStream reads a single length prefixed message from the given stream. Stream is
+// read without buffering so following messages can be read from the same stream.
 func ReadStream(stream network.Stream) ([]byte, error) {
-	streamReader := bufio.NewReader(stream)
 	lengthBytes := make([]byte, LengthHeader)
-	n, err := io.ReadFull(streamReader, lengthBytes)
+	n, err := io.ReadFull(stream, lengthBytes)
 	if n != LengthHeader || err != nil {
 		return nil, fmt.Errorf("error in read the message head %w", err)
 	}
@@ -32,7 +32,7 @@ func ReadStream(stream network.Stream) ([]byte, error) {
 		return nil, fmt.Errorf("payload length:%d exceed max payload length:%d", length, MaxPayload)
 	}
 	dataBuf := make([]byte, length)
-	n, err = io.ReadFull(streamReader, dataBuf)
+	n, err = io.ReadFull(stream, dataBuf)
 	if uint32(n) != length || err != nil {
 		return nil, fmt.Errorf("short read err(%w), we would like to read: %d, however we only read: %d", err, length, n)
 	}
@@ -0,0 +1,146 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package comm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Payloads of tss process messages and start messages are encoded with protobuf:
+//
+//	message TssPayload {
+//	  bytes msg_bytes = 1;
+//	  bool is_broadcast = 2;
+//	}
+//
+//	message StartPayload {
+//	  bytes params = 1;
+//	}
+//
+// Previous versions encoded them as JSON objects, which are still accepted when
+// decoding and are sent to peers that only support the legacy wire protocol.
+
+type legacyTssPayload struct {
+	MsgBytes    []byte `json:"msgBytes"`
+	IsBroadcast bool   `json:"isBroadcast"`
+}
+
+type legacyStartPayload struct {
+	Params []byte `json:"params"`
+}
+
+// MarshalTssPayload encodes tss-lib wire message bytes as a protobuf TssPayload.
+func MarshalTssPayload(msgBytes []byte, isBroadcast bool) []byte {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, msgBytes)
+	if isBroadcast {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	return b
+}
+
+// UnmarshalTssPayload decodes protobuf or legacy JSON encoded tss payload.
+func UnmarshalTssPayload(data []byte) ([]byte, bool, error) {
+	if isLegacyPayload(data) {
+		payload := legacyTssPayload{}
+		err := json.Unmarshal(data, &payload)
+		return payload.MsgBytes, payload.IsBroadcast, err
+	}
+
+	msgBytes := []byte{}
+	isBroadcast := false
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			msgBytes = append([]byte{}, value...)
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(value)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			isBroadcast = v != 0
+		}
+		return nil
+	})
+	return msgBytes, isBroadcast, err
+}
+
+// MarshalStartPayload encodes tss process start params as a protobuf StartPayload.
+func MarshalStartPayload(params []byte) []byte {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, params)
+}
+
+// UnmarshalStartPayload decodes protobuf or legacy JSON encoded start payload.
+func UnmarshalStartPayload(data []byte) ([]byte, error) {
+	if isLegacyPayload(data) {
+		payload := legacyStartPayload{}
+		err := json.Unmarshal(data, &payload)
+		return payload.Params, err
+	}
+
+	params := []byte{}
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num == 1 && typ == protowire.BytesType {
+			params = append([]byte{}, value...)
+		}
+		return nil
+	})
+	return params, err
+}
+
+// MarshalLegacyTssPayload encodes tss-lib wire message bytes as a legacy JSON tss payload.
+func MarshalLegacyTssPayload(msgBytes []byte, isBroadcast bool) ([]byte, error) {
+	return json.Marshal(legacyTssPayload{MsgBytes: msgBytes, IsBroadcast: isBroadcast})
+}
+
+// MarshalLegacyStartPayload encodes tss process start params as a legacy JSON start payload.
+func MarshalLegacyStartPayload(params []byte) ([]byte, error) {
+	return json.Marshal(legacyStartPayload{Params: params})
+}
+
+// isLegacyPayload returns true for JSON objects. Protobuf messages never start with '{'
+// as it would encode a group field that the payloads don't use.
+func isLegacyPayload(data []byte) bool {
+	return len(data) > 0 && data[0] == '{'
+}
+
+// consumeFields calls fn with the number, type and raw value of every protobuf field in data.
+func consumeFields(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("invalid protobuf payload: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var value []byte
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return fmt.Errorf("invalid protobuf payload: %w", protowire.ParseError(n))
+			}
+			value = v
+			data = data[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return fmt.Errorf("invalid protobuf payload: %w", protowire.ParseError(n))
+			}
+			value = data[:n]
+			data = data[n:]
+		}
+
+		err := fn(num, typ, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package comm_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/stretchr/testify/suite"
+)
+
+type PayloadTestSuite struct {
+	suite.Suite
+}
+
+func TestRunPayloadTestSuite(t *testing.T) {
+	suite.Run(t, new(PayloadTestSuite))
+}
+
+func (s *PayloadTestSuite) Test_TssPayload_RoundTrip() {
+	payload := comm.MarshalTssPayload([]byte{1, 2, 3}, true)
+
+	msgBytes, isBroadcast, err := comm.UnmarshalTssPayload(payload)
+
+	s.Nil(err)
+	s.Equal([]byte{1, 2, 3}, msgBytes)
+	s.True(isBroadcast)
+}
+
+func (s *PayloadTestSuite) Test_TssPayload_LegacyJSON() {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"msgBytes":    []byte{1, 2, 3},
+		"isBroadcast": true,
+	})
+
+	msgBytes, isBroadcast, err := comm.UnmarshalTssPayload(payload)
+
+	s.Nil(err)
+	s.Equal([]byte{1, 2, 3}, msgBytes)
+	s.True(isBroadcast)
+}
+
+func (s *PayloadTestSuite) Test_TssPayload_Invalid() {
+	_, _, err := comm.UnmarshalTssPayload([]byte{0x0a, 0x05, 0x01})
+
+	s.NotNil(err)
+}
+
+func (s *PayloadTestSuite) Test_TssPayload_IsSmallerThanLegacyJSON() {
+	msgBytes := make([]byte, 1024)
+	legacyPayload, _ := comm.MarshalLegacyTssPayload(msgBytes, false)
+
+	s.Less(len(comm.MarshalTssPayload(msgBytes, false)), len(msgBytes)+8)
+	s.Greater(len(legacyPayload), len(msgBytes)*4/3)
+}
+
+func (s *PayloadTestSuite) Test_StartPayload_RoundTrip() {
+	params, err := comm.UnmarshalStartPayload(comm.MarshalStartPayload([]byte("params")))
+
+	s.Nil(err)
+	s.Equal([]byte("params"), params)
+}
+
+func (s *PayloadTestSuite) Test_LegacyTssPayload_DecodedAsTssPayload() {
+	payload, err := comm.MarshalLegacyTssPayload([]byte{1}, true)
+	s.Nil(err)
+
+	s.JSONEq(`{"msgBytes":"AQ==","isBroadcast":true}`, string(payload))
+	msgBytes, isBroadcast, err := comm.UnmarshalTssPayload(payload)
+	s.Nil(err)
+	s.Equal([]byte{1}, msgBytes)
+	s.True(isBroadcast)
+}
+
+func (s *PayloadTestSuite) Test_LegacyStartPayload_DecodedAsStartPayload() {
+	payload, err := comm.MarshalLegacyStartPayload([]byte("params"))
+	s.Nil(err)
+
+	s.JSONEq(`{"params":"cGFyYW1z"}`, string(payload))
+	params, err := comm.UnmarshalStartPayload(payload)
+	s.Nil(err)
+	s.Equal([]byte("params"), params)
+}
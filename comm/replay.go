@@ -0,0 +1,155 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package comm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// DefaultMaxMessageAge is the maximum difference between the sender timestamp of
+	// a message and the time it is received.
+	DefaultMaxMessageAge = 2 * time.Minute
+	// sequenceTTL is the duration sequences of idle sessions are kept for.
+	sequenceTTL = 10 * time.Minute
+)
+
+var (
+	ErrStaleMessage     = errors.New("stale message")
+	ErrReplayedMessage  = errors.New("replayed message")
+	ErrUnstampedMessage = errors.New("unstamped message")
+)
+
+type sessionSequence struct {
+	next     uint64
+	lastUsed time.Time
+}
+
+// Sequencer assigns increasing sequence numbers to messages sent for each session.
+// Sequences start at the current unix time in nanoseconds, so sequence numbers are
+// not reused when a session with the same ID is started again after a restart.
+type Sequencer struct {
+	lock     sync.Mutex
+	sessions map[string]*sessionSequence
+}
+
+func NewSequencer() *Sequencer {
+	return &Sequencer{
+		sessions: make(map[string]*sessionSequence),
+	}
+}
+
+// Next returns the next sequence number of the session.
+func (s *Sequencer) Next(sessionID string) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	for id, session := range s.sessions {
+		if now.Sub(session.lastUsed) > sequenceTTL {
+			delete(s.sessions, id)
+		}
+	}
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		session = &sessionSequence{next: uint64(now.UnixNano())}
+		s.sessions[sessionID] = session
+	}
+	session.lastUsed = now
+	sequence := session.next
+	session.next++
+	return sequence
+}
+
+// ReplayGuard rejects messages with sender timestamps outside of the freshness window
+// and messages with sequence numbers already received from the sender for the session.
+// Sequence numbers are only remembered for the freshness window, as older messages
+// are rejected by their timestamp.
+type ReplayGuard struct {
+	maxAge            time.Duration
+	requireTimestamps bool
+
+	lock sync.Mutex
+	// sender -> sessionID -> sequence -> message timestamp
+	seen      map[peer.ID]map[string]map[uint64]time.Time
+	lastPrune time.Time
+}
+
+// NewReplayGuard creates a replay guard that accepts messages without timestamps
+// unless requireTimestamps is set.
+func NewReplayGuard(maxAge time.Duration, requireTimestamps bool) *ReplayGuard {
+	return &ReplayGuard{
+		maxAge:            maxAge,
+		requireTimestamps: requireTimestamps,
+		seen:              make(map[peer.ID]map[string]map[uint64]time.Time),
+	}
+}
+
+// Check returns an error if the message is stale or was already received at the provided time.
+// Messages without a timestamp are sent by relayers that don't support replay protection and
+// are only accepted if timestamps are not required.
+func (g *ReplayGuard) Check(msg *WrappedMessage, now time.Time) error {
+	if msg.Timestamp.IsZero() {
+		if g.requireTimestamps {
+			return fmt.Errorf("%w: %s of session %s", ErrUnstampedMessage, msg.MessageType, msg.SessionID)
+		}
+		return nil
+	}
+
+	age := now.Sub(msg.Timestamp)
+	if age > g.maxAge || -age > g.maxAge {
+		return fmt.Errorf("%w: %s sent at %s is outside of the %s freshness window",
+			ErrStaleMessage, msg.MessageType, msg.Timestamp.Format(time.RFC3339), g.maxAge)
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.prune(now)
+	sessions, ok := g.seen[msg.From]
+	if !ok {
+		sessions = make(map[string]map[uint64]time.Time)
+		g.seen[msg.From] = sessions
+	}
+	sequences, ok := sessions[msg.SessionID]
+	if !ok {
+		sequences = make(map[uint64]time.Time)
+		sessions[msg.SessionID] = sequences
+	}
+	if _, ok := sequences[msg.Sequence]; ok {
+		return fmt.Errorf("%w: %s with sequence %d of session %s", ErrReplayedMessage, msg.MessageType, msg.Sequence, msg.SessionID)
+	}
+	sequences[msg.Sequence] = msg.Timestamp
+	return nil
+}
+
+// prune removes sequences of messages that would be rejected as stale.
+func (g *ReplayGuard) prune(now time.Time) {
+	if now.Sub(g.lastPrune) < g.maxAge {
+		return
+	}
+	g.lastPrune = now
+
+	for sender, sessions := range g.seen {
+		for sessionID, sequences := range sessions {
+			for sequence, timestamp := range sequences {
+				if now.Sub(timestamp) > g.maxAge {
+					delete(sequences, sequence)
+				}
+			}
+			if len(sequences) == 0 {
+				delete(sessions, sessionID)
+			}
+		}
+		if len(sessions) == 0 {
+			delete(g.seen, sender)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package comm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+)
+
+type SequencerTestSuite struct {
+	suite.Suite
+}
+
+func TestRunSequencerTestSuite(t *testing.T) {
+	suite.Run(t, new(SequencerTestSuite))
+}
+
+func (s *SequencerTestSuite) Test_Next_IncreasesPerSession() {
+	sequencer := comm.NewSequencer()
+
+	first := sequencer.Next("1")
+	second := sequencer.Next("1")
+	other := sequencer.Next("2")
+
+	s.Equal(first+1, second)
+	s.NotEqual(first, other)
+}
+
+func (s *SequencerTestSuite) Test_Next_RestartedSessionContinuesAfterPreviousSequences() {
+	previous := comm.NewSequencer().Next("1")
+	time.Sleep(time.Millisecond)
+
+	s.Greater(comm.NewSequencer().Next("1"), previous)
+}
+
+type ReplayGuardTestSuite struct {
+	suite.Suite
+	guard *comm.ReplayGuard
+	peer1 peer.ID
+	peer2 peer.ID
+	now   time.Time
+}
+
+func TestRunReplayGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplayGuardTestSuite))
+}
+
+func (s *ReplayGuardTestSuite) SetupTest() {
+	s.guard = comm.NewReplayGuard(time.Minute, false)
+	s.peer1, _ = peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	s.peer2, _ = peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	s.now = time.Now()
+}
+
+func (s *ReplayGuardTestSuite) message(from peer.ID, sessionID string, timestamp time.Time, sequence uint64) *comm.WrappedMessage {
+	return &comm.WrappedMessage{
+		MessageType: comm.TssFailMsg,
+		SessionID:   sessionID,
+		Timestamp:   timestamp,
+		Sequence:    sequence,
+		From:        from,
+	}
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_FreshMessage() {
+	err := s.guard.Check(s.message(s.peer1, "1", s.now.Add(-time.Second*30), 1), s.now)
+
+	s.Nil(err)
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_StaleMessage() {
+	err := s.guard.Check(s.message(s.peer1, "1", s.now.Add(-time.Minute*2), 1), s.now)
+
+	s.True(errors.Is(err, comm.ErrStaleMessage))
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_MessageFromFuture() {
+	err := s.guard.Check(s.message(s.peer1, "1", s.now.Add(time.Minute*2), 1), s.now)
+
+	s.True(errors.Is(err, comm.ErrStaleMessage))
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_ReplayedMessage() {
+	s.Nil(s.guard.Check(s.message(s.peer1, "1", s.now, 1), s.now))
+
+	err := s.guard.Check(s.message(s.peer1, "1", s.now, 1), s.now.Add(time.Second))
+
+	s.True(errors.Is(err, comm.ErrReplayedMessage))
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_SequencesTrackedPerSenderAndSession() {
+	s.Nil(s.guard.Check(s.message(s.peer1, "1", s.now, 1), s.now))
+	s.Nil(s.guard.Check(s.message(s.peer2, "1", s.now, 1), s.now))
+	s.Nil(s.guard.Check(s.message(s.peer1, "2", s.now, 1), s.now))
+	// messages sent concurrently can be received out of order
+	s.Nil(s.guard.Check(s.message(s.peer1, "1", s.now, 3), s.now))
+	s.Nil(s.guard.Check(s.message(s.peer1, "1", s.now, 2), s.now))
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_ReplayRejectedAfterPrune() {
+	s.Nil(s.guard.Check(s.message(s.peer1, "1", s.now, 1), s.now))
+
+	err := s.guard.Check(s.message(s.peer1, "1", s.now, 1), s.now.Add(time.Minute*2))
+
+	s.True(errors.Is(err, comm.ErrStaleMessage))
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_LegacyMessageAccepted() {
+	s.Nil(s.guard.Check(s.message(s.peer1, "1", time.Time{}, 0), s.now))
+	s.Nil(s.guard.Check(s.message(s.peer1, "1", time.Time{}, 0), s.now))
+}
+
+func (s *ReplayGuardTestSuite) Test_Check_UnstampedMessageRejectedWhenTimestampsRequired() {
+	guard := comm.NewReplayGuard(time.Minute, true)
+
+	err := guard.Check(s.message(s.peer1, "1", time.Time{}, 0), s.now)
+
+	s.True(errors.Is(err, comm.ErrUnstampedMessage))
+	s.Nil(guard.Check(s.message(s.peer1, "1", s.now, 1), s.now))
+}
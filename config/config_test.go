@@ -12,6 +12,7 @@ import (
 
 	coreRelayer "github.com/ChainSafe/chainbridge-core/config/relayer"
 
+	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/config"
 	"github.com/ChainSafe/sygma-relayer/config/relayer"
 	"github.com/stretchr/testify/suite"
@@ -73,9 +74,14 @@ func (s *GetConfigTestSuite) Test_GetConfigFromENV() {
 					ServiceAddress: "buckets.chainsafe.io",
 					EncryptionKey:  "test-enc-key",
 				},
-				Port:         9000,
-				KeysharePath: "/cfg/keyshares/0.keyshare",
-				Key:          "test-pk",
+				Port:                  9000,
+				KeyshareBackend:       "file",
+				KeysharePath:          "/cfg/keyshares/0.keyshare",
+				Key:                   "test-pk",
+				PresignaturePoolSize:  10,
+				ReputationWindow:      time.Hour,
+				KeyshareCheckInterval: 10 * time.Minute,
+				PeerCheckInterval:     30 * time.Second,
 			},
 			BullyConfig: relayer.BullyConfig{
 				PingWaitTime:     1 * time.Second,
@@ -84,6 +90,14 @@ func (s *GetConfigTestSuite) Test_GetConfigFromENV() {
 				ElectionWaitTime: 2 * time.Second,
 				BullyWaitTime:    25 * time.Second,
 			},
+			MessageLimits: relayer.MessageLimitsConfig{
+				PeerRate:           50,
+				PeerBurst:          500,
+				MaxSessionsPerPeer: 200,
+				SessionWindow:      time.Minute,
+				MaxMessageAge:      2 * time.Minute,
+				MaxMessageSizes:    map[comm.MessageType]int{},
+			},
 		},
 		ChainConfigs: []map[string]interface{}{
 			{
@@ -184,6 +198,82 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 			errorMsg:   "unknown log level: invalid",
 			outConfig:  config.Config{},
 		},
+		{
+			name: "unknown keyshare backend",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					RawRelayerConfig: coreRelayer.RawRelayerConfig{
+						LogLevel: "info",
+					},
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						KeyshareBackend: "vault",
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							AccessKey:     "access-key",
+							SecKey:        "sec-key",
+							EncryptionKey: "enc-key",
+						},
+					},
+				},
+				ChainConfigs: []map[string]interface{}{{
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "unknown keyshare backend vault",
+			outConfig:  config.Config{},
+		},
+		{
+			name: "remote keyshare backend without URL",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					RawRelayerConfig: coreRelayer.RawRelayerConfig{
+						LogLevel: "info",
+					},
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						KeyshareBackend: "remote",
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							AccessKey:     "access-key",
+							SecKey:        "sec-key",
+							EncryptionKey: "enc-key",
+						},
+					},
+				},
+				ChainConfigs: []map[string]interface{}{{
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "keyshare remote URL not provided",
+			outConfig:  config.Config{},
+		},
+		{
+			name: "keyshare passphrase and key file provided",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					RawRelayerConfig: coreRelayer.RawRelayerConfig{
+						LogLevel: "info",
+					},
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						KeysharePassphrase: "passphrase",
+						KeyshareKeyFile:    "./keyshare.key",
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							AccessKey:     "access-key",
+							SecKey:        "sec-key",
+							EncryptionKey: "enc-key",
+						},
+					},
+				},
+				ChainConfigs: []map[string]interface{}{{
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "only one of keyshare passphrase or keyshare key file can be provided",
+			outConfig:  config.Config{},
+		},
 		{
 			name: "invalid bully config",
 			inConfig: config.RawConfig{
@@ -215,6 +305,34 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 			errorMsg:   "unable to parse bully ping wait time: time: unknown unit \"z\" in duration \"2z\"",
 			outConfig:  config.Config{},
 		},
+		{
+			name: "unknown message type in message limits",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					RawRelayerConfig: coreRelayer.RawRelayerConfig{
+						LogLevel: "info",
+					},
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							AccessKey:     "access-key",
+							SecKey:        "sec-key",
+							EncryptionKey: "enc-key",
+						},
+						Port: "2020",
+					},
+					MessageLimits: relayer.RawMessageLimitsConfig{
+						MaxMessageSizes: map[string]int{"InvalidMsg": 1024},
+					},
+				},
+				ChainConfigs: []map[string]interface{}{{
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "invalid max message size: unknown message type invalidmsg",
+			outConfig:  config.Config{},
+		},
 		{
 			name: "invalid topology config",
 			inConfig: config.RawConfig{
@@ -240,7 +358,7 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 			outConfig:  config.Config{},
 		},
 		{
-			name: "missing encryption key",
+			name: "unknown topology provider type",
 			inConfig: config.RawConfig{
 				RelayerConfig: relayer.RawRelayerConfig{
 					RawRelayerConfig: coreRelayer.RawRelayerConfig{
@@ -248,8 +366,56 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 					},
 					MpcConfig: relayer.RawMpcRelayerConfig{
 						TopologyConfiguration: relayer.TopologyConfiguration{
-							AccessKey: "access-key",
-							SecKey:    "secret-key",
+							Type: "ipfs",
+						},
+						Port: "2020",
+					},
+				},
+				ChainConfigs: []map[string]interface{}{{
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "unknown topology provider type ipfs",
+			outConfig:  config.Config{},
+		},
+		{
+			name: "topology required signatures exceed admins",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					RawRelayerConfig: coreRelayer.RawRelayerConfig{
+						LogLevel: "info",
+					},
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							AccessKey:          "access-key",
+							SecKey:             "sec-key",
+							AdminAddresses:     []string{"0xff93B45308FD417dF303D6515aB04D9e89a750Ca"},
+							RequiredSignatures: 2,
+						},
+						Port: "2020",
+					},
+				},
+				ChainConfigs: []map[string]interface{}{{
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "topology configuration required signatures exceed number of admin addresses",
+			outConfig:  config.Config{},
+		},
+		{
+			name: "file topology provider without path",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					RawRelayerConfig: coreRelayer.RawRelayerConfig{
+						LogLevel: "info",
+					},
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							Type: "file",
 						},
 						Port: "2020",
 					},
@@ -260,7 +426,30 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 				}},
 			},
 			shouldFail: true,
-			errorMsg:   "topology configuration encryption key not provided",
+			errorMsg:   "topology configuration file path not provided",
+			outConfig:  config.Config{},
+		},
+		{
+			name: "http topology provider without URL",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					RawRelayerConfig: coreRelayer.RawRelayerConfig{
+						LogLevel: "info",
+					},
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							Type: "http",
+						},
+						Port: "2020",
+					},
+				},
+				ChainConfigs: []map[string]interface{}{{
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "topology configuration URL not provided",
 			outConfig:  config.Config{},
 		},
 		{
@@ -294,11 +483,18 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 					},
 					HealthPort: 9001,
 					MpcConfig: relayer.MpcRelayerConfig{
-						Port: 9000,
+						Port:                  9000,
+						KeyshareBackend:       "file",
+						PresignaturePoolSize:  10,
+						ReputationWindow:      time.Hour,
+						KeyshareCheckInterval: 10 * time.Minute,
+						PeerCheckInterval:     30 * time.Second,
 						TopologyConfiguration: relayer.TopologyConfiguration{
 							AccessKey:      "access-key",
 							EncryptionKey:  "enc-key",
 							SecKey:         "sec-key",
+							Type:           "s3",
+							HashMode:       "transition",
 							DocumentName:   "topology.json",
 							BucketRegion:   "us-east-1",
 							BucketName:     "mpc-topology",
@@ -312,6 +508,14 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 						ElectionWaitTime: 2 * time.Second,
 						BullyWaitTime:    25 * time.Second,
 					},
+					MessageLimits: relayer.MessageLimitsConfig{
+						PeerRate:           50,
+						PeerBurst:          500,
+						MaxSessionsPerPeer: 200,
+						SessionWindow:      time.Minute,
+						MaxMessageAge:      2 * time.Minute,
+						MaxMessageSizes:    map[comm.MessageType]int{},
+					},
 				},
 				ChainConfigs: []map[string]interface{}{{
 					"type": "evm",
@@ -330,14 +534,17 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 					HealthPort: "9002",
 					MpcConfig: relayer.RawMpcRelayerConfig{
 						TopologyConfiguration: relayer.TopologyConfiguration{
-							AccessKey:     "access-key",
-							SecKey:        "sec-key",
-							EncryptionKey: "enc-key",
-							BucketName:    "test-mpc-bucket",
+							AccessKey:                      "access-key",
+							SecKey:                         "sec-key",
+							EncryptionKey:                  "enc-key",
+							BucketName:                     "test-mpc-bucket",
+							RequireAuthenticatedEncryption: true,
 						},
-						Port:         "2020",
-						KeysharePath: "./share.key",
-						Key:          "./key.pk",
+						Port:                        "2020",
+						KeysharePath:                "./share.key",
+						KeysharePassphrase:          "passphrase",
+						Key:                         "./key.pk",
+						RequireProposalVerification: true,
 					},
 					BullyConfig: relayer.RawBullyConfig{
 						PingWaitTime:     "1s",
@@ -346,6 +553,9 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 						ElectionWaitTime: "1s",
 						BullyWaitTime:    "1s",
 					},
+					MessageLimits: relayer.RawMessageLimitsConfig{
+						RequireMessageTimestamps: true,
+					},
 				},
 				ChainConfigs: []map[string]interface{}{{
 					"type": "evm",
@@ -363,17 +573,27 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 					},
 					HealthPort: 9002,
 					MpcConfig: relayer.MpcRelayerConfig{
-						Port:         2020,
-						KeysharePath: "./share.key",
-						Key:          "./key.pk",
+						Port:                        2020,
+						KeyshareBackend:             "file",
+						KeysharePath:                "./share.key",
+						KeysharePassphrase:          "passphrase",
+						Key:                         "./key.pk",
+						PresignaturePoolSize:        10,
+						ReputationWindow:            time.Hour,
+						KeyshareCheckInterval:       10 * time.Minute,
+						PeerCheckInterval:           30 * time.Second,
+						RequireProposalVerification: true,
 						TopologyConfiguration: relayer.TopologyConfiguration{
-							AccessKey:      "access-key",
-							SecKey:         "sec-key",
-							EncryptionKey:  "enc-key",
-							DocumentName:   "topology.json",
-							BucketRegion:   "us-east-1",
-							BucketName:     "test-mpc-bucket",
-							ServiceAddress: "buckets.chainsafe.io",
+							AccessKey:                      "access-key",
+							SecKey:                         "sec-key",
+							EncryptionKey:                  "enc-key",
+							Type:                           "s3",
+							HashMode:                       "transition",
+							DocumentName:                   "topology.json",
+							BucketRegion:                   "us-east-1",
+							BucketName:                     "test-mpc-bucket",
+							ServiceAddress:                 "buckets.chainsafe.io",
+							RequireAuthenticatedEncryption: true,
 						},
 					},
 					BullyConfig: relayer.BullyConfig{
@@ -383,6 +603,15 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 						ElectionWaitTime: time.Second,
 						BullyWaitTime:    time.Second,
 					},
+					MessageLimits: relayer.MessageLimitsConfig{
+						PeerRate:                 50,
+						PeerBurst:                500,
+						MaxSessionsPerPeer:       200,
+						SessionWindow:            time.Minute,
+						MaxMessageAge:            2 * time.Minute,
+						RequireMessageTimestamps: true,
+						MaxMessageSizes:          map[comm.MessageType]int{},
+					},
 				},
 				ChainConfigs: []map[string]interface{}{{
 					"type": "evm",
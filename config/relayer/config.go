@@ -12,20 +12,36 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/ChainSafe/chainbridge-core/config/relayer"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
 )
 
 type RelayerConfig struct {
 	relayer.RelayerConfig
-	HealthPort  uint16
-	MpcConfig   MpcRelayerConfig
-	BullyConfig BullyConfig
+	HealthPort    uint16
+	MpcConfig     MpcRelayerConfig
+	BullyConfig   BullyConfig
+	MessageLimits MessageLimitsConfig
 }
 
 type MpcRelayerConfig struct {
 	TopologyConfiguration TopologyConfiguration
 	Port                  uint16
+	KeyshareBackend       string
 	KeysharePath          string
+	KeyshareRemoteURL     string
+	KeyshareRemoteToken   string
+	KeysharePassphrase    string
+	KeyshareKeyFile       string
 	Key                   string
+	PresignaturePoolSize  int
+	ReputationWindow      time.Duration
+	KeyshareCheckInterval time.Duration
+	PeerCheckInterval     time.Duration
+	// RequireProposalVerification rejects signing sessions of coordinators that don't send
+	// proposals with start params. Enable after all relayers support proposal verification.
+	RequireProposalVerification bool
 }
 
 type BullyConfig struct {
@@ -36,29 +52,80 @@ type BullyConfig struct {
 	BullyWaitTime    time.Duration
 }
 
+// MessageLimitsConfig limits messages received from a single peer.
+type MessageLimitsConfig struct {
+	// PeerRate is the number of messages per second accepted from a peer
+	PeerRate float64
+	// PeerBurst is the number of messages a peer can send at once above the rate
+	PeerBurst int
+	// MaxSessionsPerPeer is the number of sessions a peer can send messages for within SessionWindow
+	MaxSessionsPerPeer int
+	SessionWindow      time.Duration
+	// MaxMessageAge is the maximum difference between the sender timestamp of a message and the time it is received
+	MaxMessageAge time.Duration
+	// RequireMessageTimestamps rejects messages without sender timestamps, which are sent
+	// by relayers that don't support replay protection
+	RequireMessageTimestamps bool
+	// MaxMessageSizes overrides default maximum payload sizes of message types
+	MaxMessageSizes map[comm.MessageType]int
+}
+
+const (
+	S3TopologyProvider   = "s3"
+	FileTopologyProvider = "file"
+	HTTPTopologyProvider = "http"
+)
+
+const (
+	LegacyTopologyHash     = "legacy"
+	CanonicalTopologyHash  = "canonical"
+	TransitionTopologyHash = "transition"
+)
+
 type TopologyConfiguration struct {
-	EncryptionKey  string `mapstructure:"EncryptionKey" json:"encryptionKey"`
-	AccessKey      string `mapstructure:"AccessKey" json:"accessKey"`
-	SecKey         string `mapstructure:"SecKey" json:"secKey"`
-	DocumentName   string `mapstructure:"DocumentName" default:"topology.json" json:"documentName"`
-	BucketRegion   string `mapstructure:"BucketRegion" default:"us-east-1" json:"bucketRegion"`
-	BucketName     string `mapstructure:"BucketName" default:"mpc-topology" json:"bucketName"`
-	ServiceAddress string `mapstructure:"ServiceAddress" default:"buckets.chainsafe.io" json:"serviceAddress"`
-	Path           string `mapstructure:"Path" json:"path"`
+	Type               string   `mapstructure:"Type" default:"s3" json:"type"`
+	EncryptionKey      string   `mapstructure:"EncryptionKey" json:"encryptionKey"`
+	AccessKey          string   `mapstructure:"AccessKey" json:"accessKey"`
+	SecKey             string   `mapstructure:"SecKey" json:"secKey"`
+	DocumentName       string   `mapstructure:"DocumentName" default:"topology.json" json:"documentName"`
+	BucketRegion       string   `mapstructure:"BucketRegion" default:"us-east-1" json:"bucketRegion"`
+	BucketName         string   `mapstructure:"BucketName" default:"mpc-topology" json:"bucketName"`
+	ServiceAddress     string   `mapstructure:"ServiceAddress" default:"buckets.chainsafe.io" json:"serviceAddress"`
+	FilePath           string   `mapstructure:"FilePath" json:"filePath"`
+	URL                string   `mapstructure:"URL" json:"url"`
+	Path               string   `mapstructure:"Path" json:"path"`
+	AdminAddresses     []string `mapstructure:"AdminAddresses" json:"adminAddresses"`
+	RequiredSignatures int      `mapstructure:"RequiredSignatures" json:"requiredSignatures"`
+	HashMode           string   `mapstructure:"HashMode" default:"transition" json:"hashMode"`
+	// RequireAuthenticatedEncryption rejects topology encrypted with AES-CTR
+	// by previous versions, which is decrypted without integrity checks.
+	RequireAuthenticatedEncryption bool `mapstructure:"RequireAuthenticatedEncryption" json:"requireAuthenticatedEncryption"`
 }
 
 type RawRelayerConfig struct {
 	relayer.RawRelayerConfig `mapstructure:",squash"`
-	HealthPort               string              `mapstructure:"HealthPort" json:"healthPort" default:"9001"`
-	MpcConfig                RawMpcRelayerConfig `mapstructure:"MpcConfig" json:"mpcConfig"`
-	BullyConfig              RawBullyConfig      `mapstructure:"BullyConfig" json:"bullyConfig"`
+	HealthPort               string                 `mapstructure:"HealthPort" json:"healthPort" default:"9001"`
+	MpcConfig                RawMpcRelayerConfig    `mapstructure:"MpcConfig" json:"mpcConfig"`
+	BullyConfig              RawBullyConfig         `mapstructure:"BullyConfig" json:"bullyConfig"`
+	MessageLimits            RawMessageLimitsConfig `mapstructure:"MessageLimits" json:"messageLimits"`
 }
 
 type RawMpcRelayerConfig struct {
+	KeyshareBackend       string                `mapstructure:"KeyshareBackend" json:"keyshareBackend" default:"file"`
 	KeysharePath          string                `mapstructure:"KeysharePath" json:"keysharePath"`
+	KeyshareRemoteURL     string                `mapstructure:"KeyshareRemoteURL" json:"keyshareRemoteURL"`
+	KeyshareRemoteToken   string                `mapstructure:"KeyshareRemoteToken" json:"keyshareRemoteToken"`
+	KeysharePassphrase    string                `mapstructure:"KeysharePassphrase" json:"keysharePassphrase"`
+	KeyshareKeyFile       string                `mapstructure:"KeyshareKeyFile" json:"keyshareKeyFile"`
 	Key                   string                `mapstructure:"Key" json:"key"`
 	Port                  string                `mapstructure:"Port" json:"port" default:"9000"`
+	PresignaturePoolSize  string                `mapstructure:"PresignaturePoolSize" json:"presignaturePoolSize" default:"10"`
+	ReputationWindow      string                `mapstructure:"ReputationWindow" json:"reputationWindow" default:"1h"`
+	KeyshareCheckInterval string                `mapstructure:"KeyshareCheckInterval" json:"keyshareCheckInterval" default:"10m"`
+	PeerCheckInterval     string                `mapstructure:"PeerCheckInterval" json:"peerCheckInterval" default:"30s"`
 	TopologyConfiguration TopologyConfiguration `mapstructure:"TopologyConfiguration" json:"topologyConfiguration"`
+
+	RequireProposalVerification bool `mapstructure:"RequireProposalVerification" json:"requireProposalVerification"`
 }
 
 type RawBullyConfig struct {
@@ -69,20 +136,69 @@ type RawBullyConfig struct {
 	BullyWaitTime    string `mapstructure:"BullyWaitTime" json:"bullyWaitTime" default:"25s"`
 }
 
+type RawMessageLimitsConfig struct {
+	PeerRate                 string         `mapstructure:"PeerRate" json:"peerRate" default:"50"`
+	PeerBurst                string         `mapstructure:"PeerBurst" json:"peerBurst" default:"500"`
+	MaxSessionsPerPeer       string         `mapstructure:"MaxSessionsPerPeer" json:"maxSessionsPerPeer" default:"200"`
+	SessionWindow            string         `mapstructure:"SessionWindow" json:"sessionWindow" default:"1m"`
+	MaxMessageAge            string         `mapstructure:"MaxMessageAge" json:"maxMessageAge" default:"2m"`
+	RequireMessageTimestamps bool           `mapstructure:"RequireMessageTimestamps" json:"requireMessageTimestamps"`
+	MaxMessageSizes          map[string]int `mapstructure:"MaxMessageSizes" json:"maxMessageSizes"`
+}
+
 func (c *RawRelayerConfig) Validate() error {
-	if c.MpcConfig.TopologyConfiguration.AccessKey == "" {
-		return errors.New("topology configuration access key not provided")
+	err := c.MpcConfig.TopologyConfiguration.Validate()
+	if err != nil {
+		return err
 	}
-
-	if c.MpcConfig.TopologyConfiguration.SecKey == "" {
-		return errors.New("topology configuration secret key not provided")
+	if c.MpcConfig.KeysharePassphrase != "" && c.MpcConfig.KeyshareKeyFile != "" {
+		return errors.New("only one of keyshare passphrase or keyshare key file can be provided")
 	}
-	if c.MpcConfig.TopologyConfiguration.EncryptionKey == "" {
-		return errors.New("topology configuration encryption key not provided")
+	switch c.MpcConfig.KeyshareBackend {
+	case keyshare.FileBackendType, keyshare.LvlDBBackendType:
+	case keyshare.RemoteBackendType:
+		if c.MpcConfig.KeyshareRemoteURL == "" {
+			return errors.New("keyshare remote URL not provided")
+		}
+	default:
+		return fmt.Errorf("unknown keyshare backend %s", c.MpcConfig.KeyshareBackend)
 	}
 	return nil
 }
 
+func (c *TopologyConfiguration) Validate() error {
+	switch c.Type {
+	case S3TopologyProvider:
+		if c.AccessKey == "" {
+			return errors.New("topology configuration access key not provided")
+		}
+		if c.SecKey == "" {
+			return errors.New("topology configuration secret key not provided")
+		}
+	case FileTopologyProvider:
+		if c.FilePath == "" {
+			return errors.New("topology configuration file path not provided")
+		}
+	case HTTPTopologyProvider:
+		if c.URL == "" {
+			return errors.New("topology configuration URL not provided")
+		}
+	default:
+		return fmt.Errorf("unknown topology provider type %s", c.Type)
+	}
+
+	if c.RequiredSignatures > len(c.AdminAddresses) {
+		return errors.New("topology configuration required signatures exceed number of admin addresses")
+	}
+
+	switch c.HashMode {
+	case LegacyTopologyHash, CanonicalTopologyHash, TransitionTopologyHash:
+		return nil
+	default:
+		return fmt.Errorf("unknown topology hash mode %s", c.HashMode)
+	}
+}
+
 // NewRelayerConfig parses RawRelayerConfig into RelayerConfig
 func NewRelayerConfig(rawConfig RawRelayerConfig) (RelayerConfig, error) {
 	config := RelayerConfig{}
@@ -118,6 +234,12 @@ func NewRelayerConfig(rawConfig RawRelayerConfig) (RelayerConfig, error) {
 	}
 	config.BullyConfig = bullyConfig
 
+	messageLimits, err := parseMessageLimits(rawConfig)
+	if err != nil {
+		return RelayerConfig{}, err
+	}
+	config.MessageLimits = messageLimits
+
 	return config, nil
 }
 
@@ -130,9 +252,39 @@ func parseMpcConfig(rawConfig RawRelayerConfig) (MpcRelayerConfig, error) {
 	}
 	mpcConfig.Port = uint16(port)
 
+	poolSize, err := strconv.ParseUint(rawConfig.MpcConfig.PresignaturePoolSize, 0, 16)
+	if err != nil {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse presignature pool size from config %v", err)
+	}
+	mpcConfig.PresignaturePoolSize = int(poolSize)
+
+	reputationWindow, err := time.ParseDuration(rawConfig.MpcConfig.ReputationWindow)
+	if err != nil {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse reputation window from config %v", err)
+	}
+	mpcConfig.ReputationWindow = reputationWindow
+
+	keyshareCheckInterval, err := time.ParseDuration(rawConfig.MpcConfig.KeyshareCheckInterval)
+	if err != nil {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse keyshare check interval from config %v", err)
+	}
+	mpcConfig.KeyshareCheckInterval = keyshareCheckInterval
+
+	peerCheckInterval, err := time.ParseDuration(rawConfig.MpcConfig.PeerCheckInterval)
+	if err != nil {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse peer check interval from config %v", err)
+	}
+	mpcConfig.PeerCheckInterval = peerCheckInterval
+
 	mpcConfig.TopologyConfiguration = rawConfig.MpcConfig.TopologyConfiguration
+	mpcConfig.KeyshareBackend = rawConfig.MpcConfig.KeyshareBackend
 	mpcConfig.KeysharePath = rawConfig.MpcConfig.KeysharePath
+	mpcConfig.KeyshareRemoteURL = rawConfig.MpcConfig.KeyshareRemoteURL
+	mpcConfig.KeyshareRemoteToken = rawConfig.MpcConfig.KeyshareRemoteToken
+	mpcConfig.KeysharePassphrase = rawConfig.MpcConfig.KeysharePassphrase
+	mpcConfig.KeyshareKeyFile = rawConfig.MpcConfig.KeyshareKeyFile
 	mpcConfig.Key = rawConfig.MpcConfig.Key
+	mpcConfig.RequireProposalVerification = rawConfig.MpcConfig.RequireProposalVerification
 
 	return mpcConfig, nil
 }
@@ -171,3 +323,49 @@ func parseBullyConfig(rawConfig RawRelayerConfig) (BullyConfig, error) {
 		BullyWaitTime:    bullyWaitTime,
 	}, nil
 }
+
+func parseMessageLimits(rawConfig RawRelayerConfig) (MessageLimitsConfig, error) {
+	peerRate, err := strconv.ParseFloat(rawConfig.MessageLimits.PeerRate, 64)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse peer message rate: %w", err)
+	}
+
+	peerBurst, err := strconv.ParseUint(rawConfig.MessageLimits.PeerBurst, 0, 32)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse peer message burst: %w", err)
+	}
+
+	maxSessions, err := strconv.ParseUint(rawConfig.MessageLimits.MaxSessionsPerPeer, 0, 32)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse max sessions per peer: %w", err)
+	}
+
+	sessionWindow, err := time.ParseDuration(rawConfig.MessageLimits.SessionWindow)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse session window: %w", err)
+	}
+
+	maxMessageAge, err := time.ParseDuration(rawConfig.MessageLimits.MaxMessageAge)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse max message age: %w", err)
+	}
+
+	maxMessageSizes := make(map[comm.MessageType]int)
+	for name, size := range rawConfig.MessageLimits.MaxMessageSizes {
+		msgType, err := comm.ParseMessageType(name)
+		if err != nil {
+			return MessageLimitsConfig{}, fmt.Errorf("invalid max message size: %w", err)
+		}
+		maxMessageSizes[msgType] = size
+	}
+
+	return MessageLimitsConfig{
+		PeerRate:                 peerRate,
+		PeerBurst:                int(peerBurst),
+		MaxSessionsPerPeer:       int(maxSessions),
+		SessionWindow:            sessionWindow,
+		MaxMessageAge:            maxMessageAge,
+		RequireMessageTimestamps: rawConfig.MessageLimits.RequireMessageTimestamps,
+		MaxMessageSizes:          maxMessageSizes,
+	}, nil
+}
@@ -0,0 +1,43 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package devnet
+
+import (
+	// embeds compiled devnet bridge contract
+	_ "embed"
+	"math/big"
+	"strings"
+
+	"github.com/ChainSafe/chainbridge-core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// bridgeRuntimeBin is runtime bytecode of contracts/Bridge.sol compiled with solc 0.8.21.
+// Run `make devnet-contracts` to regenerate it after changing the contract.
+//
+//go:embed contracts/Bridge.bin
+var bridgeRuntimeBin string
+
+// resourceIDToHandlerAddressSlot is the storage slot of the _resourceIDToHandlerAddress
+// mapping of the devnet bridge contract
+const resourceIDToHandlerAddressSlot = 0
+
+// bridgeCode returns runtime bytecode of the devnet bridge contract.
+func bridgeCode() []byte {
+	return common.FromHex(strings.TrimSpace(bridgeRuntimeBin))
+}
+
+// bridgeStorage returns bridge contract storage that maps resource IDs to provided handlers.
+func bridgeStorage(handlers map[types.ResourceID]common.Address) map[common.Hash]common.Hash {
+	storage := make(map[common.Hash]common.Hash)
+	for resourceID, handler := range handlers {
+		slot := crypto.Keccak256Hash(
+			resourceID[:],
+			common.BigToHash(big.NewInt(resourceIDToHandlerAddressSlot)).Bytes(),
+		)
+		storage[slot] = common.BytesToHash(handler.Bytes())
+	}
+	return storage
+}
@@ -0,0 +1,130 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package devnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-core/types"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/consts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	BridgeAddress       = common.HexToAddress("0x6CdE2Cd82a4F8B74693Ff5e194c19CA08c2d1c68")
+	Erc20HandlerAddress = common.HexToAddress("0x1ED1d77911944622FCcDDEad8A731fd77E94173e")
+	Erc20ResourceID     = types.ResourceID(common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"))
+
+	gasLimit       = uint64(30000000)
+	accountBalance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+)
+
+// Chain is a simulated EVM chain with the devnet bridge contract that produces
+// a new block every block time.
+type Chain struct {
+	DomainID  uint8
+	backend   *backends.SimulatedBackend
+	blockTime time.Duration
+	bridgeABI abi.ABI
+}
+
+// NewChain creates a simulated chain with the bridge contract and funded provided accounts.
+func NewChain(domainID uint8, blockTime time.Duration, accounts []common.Address) *Chain {
+	alloc := core.GenesisAlloc{
+		BridgeAddress: {
+			Code:    bridgeCode(),
+			Balance: big.NewInt(0),
+			Storage: bridgeStorage(map[types.ResourceID]common.Address{
+				Erc20ResourceID: Erc20HandlerAddress,
+			}),
+		},
+	}
+	for _, account := range accounts {
+		alloc[account] = core.GenesisAccount{Balance: accountBalance}
+	}
+
+	bridgeABI, _ := abi.JSON(strings.NewReader(consts.BridgeABI))
+	return &Chain{
+		DomainID:  domainID,
+		backend:   backends.NewSimulatedBackend(alloc, gasLimit),
+		blockTime: blockTime,
+		bridgeABI: bridgeABI,
+	}
+}
+
+// Start mines pending transactions into a new block every block time.
+func (c *Chain) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.blockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.backend.Commit()
+		case <-ctx.Done():
+			_ = c.backend.Close()
+			return
+		}
+	}
+}
+
+// Client returns chain client that sends transactions signed by the provided key.
+func (c *Chain) Client(key *ecdsa.PrivateKey) *Client {
+	return NewClient(c, key)
+}
+
+// Erc20Deposit sends deposit of the provided amount to the recipient on the destination domain.
+func (c *Chain) Erc20Deposit(
+	client *Client,
+	destinationDomainID uint8,
+	recipient common.Address,
+	amount *big.Int,
+) (common.Hash, error) {
+	var depositData []byte
+	depositData = append(depositData, common.LeftPadBytes(amount.Bytes(), 32)...)
+	depositData = append(depositData, common.LeftPadBytes(big.NewInt(int64(len(recipient.Bytes()))).Bytes(), 32)...)
+	depositData = append(depositData, recipient.Bytes()...)
+
+	input, err := c.bridgeABI.Pack("deposit", destinationDomainID, Erc20ResourceID, depositData, []byte{})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return client.Transact(BridgeAddress, input)
+}
+
+// StartKeygen emits keygen event which starts keygen on relayers without a keyshare.
+func (c *Chain) StartKeygen(client *Client) (common.Hash, error) {
+	input, err := c.bridgeABI.Pack("startKeygen")
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return client.Transact(BridgeAddress, input)
+}
+
+// EndKeygen sets the MPC address that has to sign executed proposals.
+func (c *Chain) EndKeygen(client *Client, mpcAddress common.Address) (common.Hash, error) {
+	input, err := c.bridgeABI.Pack("endKeygen", mpcAddress)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return client.Transact(BridgeAddress, input)
+}
+
+func (c *Chain) sendTransaction(ctx context.Context, tx *ethTypes.Transaction) (err error) {
+	// simulated backend panics on transactions that can't be included in the block
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("transaction rejected: %v", r)
+		}
+	}()
+	return c.backend.SendTransaction(ctx, tx)
+}
@@ -0,0 +1,159 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package devnet_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	coreEvents "github.com/ChainSafe/chainbridge-core/chains/evm/calls/events"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/evmgaspricer"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/evmtransaction"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/transactor"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/transactor/signAndSend"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
+	"github.com/ChainSafe/chainbridge-core/relayer/message"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/contracts/bridge"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
+	"github.com/ChainSafe/sygma-relayer/devnet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChainTestSuite struct {
+	suite.Suite
+	chain     *devnet.Chain
+	client    *devnet.Client
+	bridge    *bridge.BridgeContract
+	mpcKey    *ecdsa.PrivateKey
+	proposals []*proposal.Proposal
+	cancel    context.CancelFunc
+}
+
+func TestRunChainTestSuite(t *testing.T) {
+	suite.Run(t, new(ChainTestSuite))
+}
+
+func (s *ChainTestSuite) SetupTest() {
+	key, _ := crypto.GenerateKey()
+	s.mpcKey, _ = crypto.GenerateKey()
+	s.chain = devnet.NewChain(2, time.Millisecond*10, []common.Address{crypto.PubkeyToAddress(key.PublicKey)})
+	s.client = s.chain.Client(key)
+	gasPricer := evmgaspricer.NewStaticGasPriceDeterminant(s.client, &evmgaspricer.GasPricerOpts{
+		GasPriceFactor: big.NewFloat(2),
+	})
+	t := signAndSend.NewSignAndSendTransactor(evmtransaction.NewTransaction, gasPricer, s.client)
+	s.bridge = bridge.NewBridgeContract(s.client, devnet.BridgeAddress, t)
+	s.proposals = []*proposal.Proposal{
+		proposal.NewProposal(
+			1, 2, 1, devnet.Erc20ResourceID, []byte{1, 2, 3},
+			devnet.Erc20HandlerAddress, devnet.BridgeAddress, message.Metadata{},
+		),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.chain.Start(ctx)
+}
+
+func (s *ChainTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func (s *ChainTestSuite) endKeygen() {
+	_, err := s.chain.EndKeygen(s.client, crypto.PubkeyToAddress(s.mpcKey.PublicKey))
+	s.Nil(err)
+}
+
+func (s *ChainTestSuite) sign(key *ecdsa.PrivateKey, proposals []*proposal.Proposal) []byte {
+	hash, err := s.bridge.ProposalsHash(proposals)
+	s.Nil(err)
+	sig, err := crypto.Sign(hash, key)
+	s.Nil(err)
+	sig[64] += 27
+	return sig
+}
+
+func (s *ChainTestSuite) Test_HandlerAddress() {
+	handler, err := s.bridge.GetHandlerAddressForResourceID(devnet.Erc20ResourceID)
+
+	s.Nil(err)
+	s.Equal(devnet.Erc20HandlerAddress, handler)
+}
+
+func (s *ChainTestSuite) Test_Erc20Deposit_EmitsDepositEvent() {
+	recipient := common.HexToAddress("0x5C1F5961696BaD2e73f73417f07EF55C62a2dC5b")
+
+	_, err := s.chain.Erc20Deposit(s.client, 1, recipient, big.NewInt(10))
+	s.Nil(err)
+	_, err = s.chain.Erc20Deposit(s.client, 1, recipient, big.NewInt(20))
+	s.Nil(err)
+
+	latest, _ := s.client.LatestBlock()
+	deposits, err := coreEvents.NewListener(s.client).FetchDeposits(context.Background(), devnet.BridgeAddress, big.NewInt(0), latest)
+	s.Nil(err)
+	s.Equal(2, len(deposits))
+	s.Equal(uint8(1), deposits[1].DestinationDomainID)
+	s.Equal(uint64(2), deposits[1].DepositNonce)
+	s.Equal(devnet.Erc20ResourceID, deposits[1].ResourceID)
+	s.Equal(s.client.From(), deposits[1].SenderAddress)
+	s.Equal(recipient.Bytes(), deposits[1].Data[64:])
+}
+
+func (s *ChainTestSuite) Test_StartKeygen_EmitsKeygenEvent() {
+	_, err := s.chain.StartKeygen(s.client)
+	s.Nil(err)
+
+	latest, _ := s.client.LatestBlock()
+	keygenEvents, err := events.NewListener(s.client).FetchKeygenEvents(context.Background(), devnet.BridgeAddress, big.NewInt(0), latest)
+	s.Nil(err)
+	s.Equal(1, len(keygenEvents))
+}
+
+func (s *ChainTestSuite) Test_ExecuteProposals_InvalidSignature() {
+	s.endKeygen()
+	invalidKey, _ := crypto.GenerateKey()
+
+	_, err := s.bridge.ExecuteProposals(s.proposals, s.sign(invalidKey, s.proposals), transactor.TransactOptions{})
+
+	s.NotNil(err)
+	executed, err := s.bridge.IsProposalExecuted(s.proposals[0])
+	s.Nil(err)
+	s.False(executed)
+}
+
+func (s *ChainTestSuite) Test_ExecuteProposals_MPCAddressNotSet() {
+	_, err := s.bridge.ExecuteProposals(s.proposals, s.sign(s.mpcKey, s.proposals), transactor.TransactOptions{})
+
+	s.NotNil(err)
+}
+
+func (s *ChainTestSuite) Test_EndKeygen_MPCAddressAlreadySet() {
+	s.endKeygen()
+	newKey, _ := crypto.GenerateKey()
+
+	_, err := s.chain.EndKeygen(s.client, crypto.PubkeyToAddress(newKey.PublicKey))
+
+	s.NotNil(err)
+	_, err = s.bridge.ExecuteProposals(s.proposals, s.sign(newKey, s.proposals), transactor.TransactOptions{})
+	s.NotNil(err)
+}
+
+func (s *ChainTestSuite) Test_ExecuteProposals_ValidSignature() {
+	s.endKeygen()
+	_, err := s.bridge.ExecuteProposals(s.proposals, s.sign(s.mpcKey, s.proposals), transactor.TransactOptions{})
+	s.Nil(err)
+
+	executed, err := s.bridge.IsProposalExecuted(s.proposals[0])
+	s.Nil(err)
+	s.True(executed)
+	latest, _ := s.client.LatestBlock()
+	executions, err := s.client.FetchEventLogs(context.Background(), devnet.BridgeAddress, string(events.ProposalExecutionSig), big.NewInt(0), latest)
+	s.Nil(err)
+	s.Equal(1, len(executions))
+}
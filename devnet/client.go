@@ -0,0 +1,207 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package devnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/evmclient"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/evmtransaction"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	receiptRetries  = 50
+	receiptInterval = 500 * time.Millisecond
+)
+
+// Client implements chain client interfaces used by the relayer on top of the simulated chain.
+type Client struct {
+	chain     *Chain
+	key       *ecdsa.PrivateKey
+	nonce     *big.Int
+	nonceLock sync.Mutex
+}
+
+func NewClient(chain *Chain, key *ecdsa.PrivateKey) *Client {
+	return &Client{
+		chain: chain,
+		key:   key,
+	}
+}
+
+// LatestBlock returns the latest block from the current chain
+func (c *Client) LatestBlock() (*big.Int, error) {
+	return c.chain.backend.Blockchain().CurrentBlock().Number(), nil
+}
+
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chain.backend.Blockchain().Config().ChainID, nil
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.chain.backend.SuggestGasPrice(ctx)
+}
+
+func (c *Client) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.chain.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+func (c *Client) CallContract(ctx context.Context, callArgs map[string]interface{}, blockNumber *big.Int) ([]byte, error) {
+	msg := ethereum.CallMsg{}
+	if from, ok := callArgs["from"].(common.Address); ok {
+		msg.From = from
+	}
+	if to, ok := callArgs["to"].(*common.Address); ok {
+		msg.To = to
+	}
+	if data, ok := callArgs["data"].(hexutil.Bytes); ok {
+		msg.Data = data
+	}
+	if value, ok := callArgs["value"].(*hexutil.Big); ok {
+		msg.Value = value.ToInt()
+	}
+	if gas, ok := callArgs["gas"].(hexutil.Uint64); ok {
+		msg.Gas = uint64(gas)
+	}
+
+	return c.chain.backend.CallContract(ctx, msg, blockNumber)
+}
+
+func (c *Client) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	logs, err := c.chain.backend.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: startBlock,
+		ToBlock:   endBlock,
+		Addresses: []common.Address{contractAddress},
+		Topics: [][]common.Hash{
+			{crypto.Keccak256Hash([]byte(event))},
+		},
+	})
+	if err != nil {
+		return []types.Log{}, err
+	}
+
+	validLogs := make([]types.Log, 0)
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+
+		validLogs = append(validLogs, log)
+	}
+	return validLogs, nil
+}
+
+func (c *Client) WaitAndReturnTxReceipt(h common.Hash) (*types.Receipt, error) {
+	for i := 0; i < receiptRetries; i++ {
+		receipt, err := c.chain.backend.TransactionReceipt(context.Background(), h)
+		if err != nil || receipt == nil {
+			time.Sleep(receiptInterval)
+			continue
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return receipt, fmt.Errorf("transaction failed on chain. Receipt status %v", receipt.Status)
+		}
+		return receipt, nil
+	}
+	return nil, errors.New("tx did not appear")
+}
+
+func (c *Client) GetTransactionByHash(h common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	return c.chain.backend.TransactionByHash(context.Background(), h)
+}
+
+func (c *Client) SignAndSendTransaction(ctx context.Context, tx evmclient.CommonTransaction) (common.Hash, error) {
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	rawTx, err := tx.RawWithSignature(c.key, chainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	signedTx := &types.Transaction{}
+	err = signedTx.UnmarshalBinary(rawTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	err = c.chain.sendTransaction(ctx, signedTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return signedTx.Hash(), nil
+}
+
+// Transact signs and sends transaction to the provided contract and waits until it is mined.
+func (c *Client) Transact(to common.Address, data []byte) (common.Hash, error) {
+	c.LockNonce()
+	defer c.UnlockNonce()
+
+	nonce, err := c.UnsafeNonce()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gasPrice, err := c.SuggestGasPrice(context.Background())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := evmtransaction.NewTransaction(nonce.Uint64(), &to, big.NewInt(0), 2000000, []*big.Int{new(big.Int).Mul(gasPrice, big.NewInt(2))}, data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, err := c.SignAndSendTransaction(context.Background(), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	err = c.UnsafeIncreaseNonce()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	_, err = c.WaitAndReturnTxReceipt(hash)
+	return hash, err
+}
+
+func (c *Client) From() common.Address {
+	return crypto.PubkeyToAddress(c.key.PublicKey)
+}
+
+func (c *Client) LockNonce() {
+	c.nonceLock.Lock()
+}
+
+func (c *Client) UnlockNonce() {
+	c.nonceLock.Unlock()
+}
+
+func (c *Client) UnsafeNonce() (*big.Int, error) {
+	if c.nonce == nil {
+		nonce, err := c.chain.backend.PendingNonceAt(context.Background(), c.From())
+		if err != nil {
+			return nil, err
+		}
+		c.nonce = new(big.Int).SetUint64(nonce)
+	}
+	return c.nonce, nil
+}
+
+func (c *Client) UnsafeIncreaseNonce() error {
+	nonce, err := c.UnsafeNonce()
+	if err != nil {
+		return err
+	}
+	c.nonce = new(big.Int).Add(nonce, big.NewInt(1))
+	return nil
+}
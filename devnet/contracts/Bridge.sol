@@ -0,0 +1,127 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+pragma solidity 0.8.21;
+
+/**
+    @title Minimal bridge contract used by devnet chains.
+    @notice Implements the part of the bridge ABI the relayer interacts with. Deposits
+    and executed proposals emit the same events as the bridge without calling handlers.
+    Proposals are verified against the MPC signature the same way as on the bridge.
+    Access control is omitted as all devnet accounts are trusted.
+
+    Storage layout is relied on by the devnet chain genesis, which sets handlers of
+    resource IDs directly in storage.
+ */
+contract Bridge {
+    struct Proposal {
+        uint8 originDomainID;
+        uint64 depositNonce;
+        bytes32 resourceID;
+        bytes data;
+    }
+
+    bytes32 private constant _DOMAIN_TYPEHASH =
+        keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)");
+    bytes32 private constant _PROPOSALS_TYPEHASH =
+        keccak256("Proposals(Proposal[] proposals)Proposal(uint8 originDomainID,uint64 depositNonce,bytes32 resourceID,bytes data)");
+    bytes32 private constant _PROPOSAL_TYPEHASH =
+        keccak256("Proposal(uint8 originDomainID,uint64 depositNonce,bytes32 resourceID,bytes data)");
+
+    // resourceID => handler address
+    mapping(bytes32 => address) public _resourceIDToHandlerAddress;
+    // destinationDomainID => number of deposits
+    mapping(uint8 => uint64) public _depositCounts;
+    // originDomainID => depositNonce / 256 => bitmap of executed proposals
+    mapping(uint8 => mapping(uint256 => uint256)) public usedNonces;
+    address public _MPCAddress;
+
+    event Deposit(
+        uint8 destinationDomainID,
+        bytes32 resourceID,
+        uint64 depositNonce,
+        address indexed user,
+        bytes data,
+        bytes handlerResponse
+    );
+    event ProposalExecution(uint8 originDomainID, uint64 depositNonce, bytes32 dataHash);
+    event StartKeygen();
+    event EndKeygen();
+
+    function deposit(
+        uint8 destinationDomainID,
+        bytes32 resourceID,
+        bytes calldata depositData,
+        bytes calldata /* feeData */
+    ) external payable {
+        uint64 depositNonce = ++_depositCounts[destinationDomainID];
+        emit Deposit(destinationDomainID, resourceID, depositNonce, msg.sender, depositData, "");
+    }
+
+    function startKeygen() external {
+        require(_MPCAddress == address(0), "MPC address is already set");
+        emit StartKeygen();
+    }
+
+    function endKeygen(address MPCAddress) external {
+        require(MPCAddress != address(0), "MPC address can't be null-address");
+        require(_MPCAddress == address(0), "MPC address can't be updated");
+        _MPCAddress = MPCAddress;
+        emit EndKeygen();
+    }
+
+    function executeProposals(Proposal[] memory proposals, bytes calldata signature) external {
+        require(proposals.length > 0, "Proposals can't be an empty array");
+        require(verify(proposals, signature), "Invalid proposal signer");
+
+        for (uint256 i = 0; i < proposals.length; i++) {
+            Proposal memory proposal = proposals[i];
+            if (isProposalExecuted(proposal.originDomainID, proposal.depositNonce)) {
+                continue;
+            }
+
+            address handler = _resourceIDToHandlerAddress[proposal.resourceID];
+            bytes32 dataHash = keccak256(abi.encodePacked(handler, proposal.data));
+            usedNonces[proposal.originDomainID][proposal.depositNonce / 256] |= 1 << (proposal.depositNonce % 256);
+            emit ProposalExecution(proposal.originDomainID, proposal.depositNonce, dataHash);
+        }
+    }
+
+    function isProposalExecuted(uint8 domainID, uint256 depositNonce) public view returns (bool) {
+        return usedNonces[domainID][depositNonce / 256] & (1 << (depositNonce % 256)) != 0;
+    }
+
+    function verify(Proposal[] memory proposals, bytes calldata signature) public view returns (bool) {
+        bytes32[] memory keccakData = new bytes32[](proposals.length);
+        for (uint256 i = 0; i < proposals.length; i++) {
+            keccakData[i] = keccak256(abi.encode(
+                _PROPOSAL_TYPEHASH,
+                proposals[i].originDomainID,
+                proposals[i].depositNonce,
+                proposals[i].resourceID,
+                keccak256(proposals[i].data)
+            ));
+        }
+        bytes32 structHash = keccak256(abi.encode(_PROPOSALS_TYPEHASH, keccak256(abi.encodePacked(keccakData))));
+        bytes32 domainSeparator = keccak256(abi.encode(
+            _DOMAIN_TYPEHASH,
+            keccak256("Bridge"),
+            keccak256("3.1.0"),
+            block.chainid,
+            address(this)
+        ));
+        bytes32 digest = keccak256(abi.encodePacked("\x19\x01", domainSeparator, structHash));
+
+        return _MPCAddress != address(0) && _recover(digest, signature) == _MPCAddress;
+    }
+
+    function _recover(bytes32 digest, bytes calldata signature) private pure returns (address) {
+        if (signature.length != 65) {
+            return address(0);
+        }
+        bytes32 r = bytes32(signature[0:32]);
+        bytes32 s = bytes32(signature[32:64]);
+        uint8 v = uint8(signature[64]);
+        return ecrecover(digest, v, r, s);
+    }
+}
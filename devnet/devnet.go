@@ -0,0 +1,384 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package devnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	coreEvm "github.com/ChainSafe/chainbridge-core/chains/evm"
+	coreEvents "github.com/ChainSafe/chainbridge-core/chains/evm/calls/events"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/evmgaspricer"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/evmtransaction"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/calls/transactor/signAndSend"
+	coreExecutor "github.com/ChainSafe/chainbridge-core/chains/evm/executor"
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
+	coreListener "github.com/ChainSafe/chainbridge-core/chains/evm/listener"
+	"github.com/ChainSafe/chainbridge-core/config/chain"
+	"github.com/ChainSafe/chainbridge-core/lvldb"
+	"github.com/ChainSafe/chainbridge-core/opentelemetry"
+	"github.com/ChainSafe/chainbridge-core/relayer"
+	"github.com/ChainSafe/chainbridge-core/store"
+	"github.com/ChainSafe/sygma-relayer/chains/evm"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/contracts/bridge"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/executor"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/listener"
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
+	relayerConfig "github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/journal"
+	"github.com/ChainSafe/sygma-relayer/tss/presign"
+	"github.com/ChainSafe/sygma-relayer/tss/reputation"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p"
+	libp2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/rs/zerolog/log"
+)
+
+const fixtureParties = 3
+
+type Config struct {
+	// Relayers is the number of relayers started in the devnet
+	Relayers int
+	// Domains is the number of simulated chains
+	Domains int
+	// Deposits is the number of deposits sent from the first to the second domain
+	Deposits int
+	// Keygen generates a new MPC key instead of using fixture keyshares
+	Keygen    bool
+	Threshold int
+	// FixturesDir is the directory with test libp2p keys and keyshares
+	FixturesDir          string
+	BlockTime            time.Duration
+	PresignaturePoolSize int
+	BullyConfig          relayerConfig.BullyConfig
+}
+
+// Devnet runs relayers on simulated chains inside a single process.
+type Devnet struct {
+	config    Config
+	dir       string
+	chains    []*Chain
+	hosts     []host.Host
+	keyshares []*keyshare.KeyshareStore
+	keys      []*ecdsa.PrivateKey
+	depositor *ecdsa.PrivateKey
+}
+
+// NewDevnet creates simulated chains and relayer identities in a temporary directory.
+func NewDevnet(config Config) (*Devnet, error) {
+	if config.Relayers < config.Threshold+1 {
+		return nil, fmt.Errorf("at least %d relayers required for threshold %d", config.Threshold+1, config.Threshold)
+	}
+	if config.Domains < 2 {
+		return nil, fmt.Errorf("at least 2 domains required")
+	}
+	if !config.Keygen && (config.Relayers != fixtureParties || config.Threshold != 1) {
+		log.Info().Msgf("Test keyshares require %d relayers with threshold 1, running keygen", fixtureParties)
+		config.Keygen = true
+	}
+
+	dir, err := ioutil.TempDir("", "sygma-devnet")
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Devnet{
+		config: config,
+		dir:    dir,
+	}
+	d.depositor, err = crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	accounts := []common.Address{crypto.PubkeyToAddress(d.depositor.PublicKey)}
+	for i := 0; i < config.Relayers; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		d.keys = append(d.keys, key)
+		accounts = append(accounts, crypto.PubkeyToAddress(key.PublicKey))
+
+		h, err := d.newHost(i)
+		if err != nil {
+			return nil, err
+		}
+		d.hosts = append(d.hosts, h)
+
+		ks, err := d.newKeyshareStore(i)
+		if err != nil {
+			return nil, err
+		}
+		d.keyshares = append(d.keyshares, ks)
+	}
+	for _, h := range d.hosts {
+		for _, p := range d.hosts {
+			h.Peerstore().AddAddrs(p.ID(), p.Addrs(), peerstore.PermanentAddrTTL)
+		}
+	}
+
+	for i := 1; i <= config.Domains; i++ {
+		d.chains = append(d.chains, NewChain(uint8(i), config.BlockTime, accounts))
+	}
+	return d, nil
+}
+
+// Start starts chains and relayers and sends configured deposits. It returns after
+// all deposits are executed or the context is canceled.
+func (d *Devnet) Start(ctx context.Context, errChn chan error) error {
+	for _, c := range d.chains {
+		go c.Start(ctx)
+	}
+
+	network := memory.NewNetwork()
+	electorNetwork := memory.NewNetwork()
+	for i := range d.hosts {
+		r, presigner, err := d.newRelayer(i, network, electorNetwork)
+		if err != nil {
+			return err
+		}
+
+		go r.Start(ctx, errChn)
+		go presigner.Start(ctx)
+		log.Info().Msgf("Started relayer %d with PID: %s", i, d.hosts[i].ID().Pretty())
+	}
+
+	if d.config.Keygen {
+		_, err := d.chains[0].StartKeygen(d.chains[0].Client(d.depositor))
+		if err != nil {
+			return err
+		}
+		err = d.waitForKeygen(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	mpcAddress, err := d.mpcAddress()
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Using MPC address %s", mpcAddress)
+	for _, c := range d.chains {
+		_, err := c.EndKeygen(c.Client(d.depositor), mpcAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	source := d.chains[0]
+	destination := d.chains[1]
+	client := source.Client(d.depositor)
+	for i := 0; i < d.config.Deposits; i++ {
+		hash, err := source.Erc20Deposit(client, destination.DomainID, client.From(), big.NewInt(int64(i+1)))
+		if err != nil {
+			return err
+		}
+		log.Info().Msgf("Sent deposit %d from domain %d to domain %d with hash %s", i+1, source.DomainID, destination.DomainID, hash)
+	}
+
+	return d.waitForExecution(ctx, source.DomainID, destination)
+}
+
+// Close removes devnet data.
+func (d *Devnet) Close() error {
+	for _, h := range d.hosts {
+		_ = h.Close()
+	}
+	return os.RemoveAll(d.dir)
+}
+
+func (d *Devnet) newRelayer(i int, network *memory.Network, electorNetwork *memory.Network) (*relayer.Relayer, *presign.Manager, error) {
+	h := d.hosts[i]
+	relayerDir := filepath.Join(d.dir, fmt.Sprintf("relayer-%d", i))
+	db, err := lvldb.NewLvlDB(filepath.Join(relayerDir, "lvldbdata"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockstore := store.NewBlockStore(db)
+	sessionJournal := journal.NewSessionJournal(db)
+	communication := memory.NewCommunication(network, h.ID())
+	reputationTracker := reputation.NewTracker(time.Hour)
+	electorFactory := elector.NewCoordinatorElectorFactoryWithCommunication(
+		h, memory.NewCommunication(electorNetwork, h.ID()), d.config.BullyConfig,
+	)
+	coordinator := tss.NewCoordinator(h, communication, electorFactory, sessionJournal, reputationTracker)
+	keyshareStore := d.keyshares[i]
+	presignaturePool := presign.NewPool(db, keyshareStore, d.config.PresignaturePoolSize)
+	presigner := presign.NewManager(presignaturePool, h, communication, coordinator, keyshareStore, d.config.PresignaturePoolSize)
+
+	chains := []relayer.RelayedChain{}
+	for _, c := range d.chains {
+		config, err := chain.NewEVMConfig(map[string]interface{}{
+			"name":               fmt.Sprintf("devnet-%d", c.DomainID),
+			"id":                 c.DomainID,
+			"endpoint":           "simulated",
+			"type":               "evm",
+			"bridge":             BridgeAddress.Hex(),
+			"erc20Handler":       Erc20HandlerAddress.Hex(),
+			"blockConfirmations": 1,
+			"blockInterval":      1,
+			"blockRetryInterval": 1,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		client := c.Client(d.keys[i])
+		gasPricer := evmgaspricer.NewStaticGasPriceDeterminant(client, &evmgaspricer.GasPricerOpts{
+			GasPriceFactor: big.NewFloat(2),
+		})
+		t := signAndSend.NewSignAndSendTransactor(evmtransaction.NewTransaction, gasPricer, client)
+		bridgeContract := bridge.NewBridgeContract(client, BridgeAddress, t)
+
+		depositHandler := coreListener.NewETHDepositHandler(bridgeContract)
+		depositHandler.RegisterDepositHandler(config.Erc20Handler, coreListener.Erc20DepositHandler)
+		depositListener := coreEvents.NewListener(client)
+		tssListener := events.NewListener(client)
+		eventHandlers := make([]coreListener.EventHandler, 0)
+		eventHandlers = append(eventHandlers, coreListener.NewDepositEventHandler(depositListener, depositHandler, BridgeAddress, c.DomainID))
+		eventHandlers = append(eventHandlers, listener.NewKeygenEventHandler(tssListener, coordinator, h, communication, keyshareStore, sessionJournal, presigner, BridgeAddress, d.config.Threshold))
+		eventHandlers = append(eventHandlers, listener.NewRetryEventHandler(tssListener, depositHandler, BridgeAddress, c.DomainID, config.BlockConfirmations))
+		evmListener := coreListener.NewEVMListener(client, eventHandlers, blockstore, config)
+
+		mh := coreExecutor.NewEVMMessageHandler(bridgeContract)
+		mh.RegisterMessageHandler(config.Erc20Handler, coreExecutor.ERC20MessageHandler)
+		executor := executor.NewExecutor(h, communication, coordinator, mh, bridgeContract, keyshareStore, sessionJournal, presigner, reputationTracker, c.DomainID)
+		// all devnet relayers run the same version
+		executor.RequireProposalVerification = true
+
+		coreEvmChain := coreEvm.NewEVMChain(evmListener, nil, blockstore, config)
+		chains = append(chains, evm.NewEVMChain(*coreEvmChain, executor))
+	}
+
+	return relayer.NewRelayer(chains, &opentelemetry.ConsoleTelemetry{}), presigner, nil
+}
+
+// newHost creates libp2p host listening on the loopback interface. Fixture keys
+// are used for the first relayers so they match peers of fixture keyshares.
+func (d *Devnet) newHost(i int) (host.Host, error) {
+	var priv libp2pCrypto.PrivKey
+	if i < fixtureParties {
+		privBytes, err := ioutil.ReadFile(filepath.Join(d.config.FixturesDir, fmt.Sprintf("pks/%d.pk", i)))
+		if err != nil {
+			return nil, err
+		}
+		priv, err = libp2pCrypto.UnmarshalPrivateKey(privBytes)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		priv, _, err = libp2pCrypto.GenerateKeyPair(libp2pCrypto.Secp256k1, 256)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return libp2p.New(
+		libp2p.Identity(priv),
+		libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		libp2p.DisableRelay(),
+	)
+}
+
+// newKeyshareStore creates keyshare store of the relayer and copies the fixture
+// keyshare into it if keygen is not executed.
+func (d *Devnet) newKeyshareStore(i int) (*keyshare.KeyshareStore, error) {
+	relayerDir := filepath.Join(d.dir, fmt.Sprintf("relayer-%d", i))
+	err := os.MkdirAll(relayerDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	keysharePath := filepath.Join(relayerDir, "keyshare")
+	if !d.config.Keygen {
+		ks, err := ioutil.ReadFile(filepath.Join(d.config.FixturesDir, fmt.Sprintf("keyshares/%d.keyshare", i)))
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(keysharePath, ks, 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return keyshare.NewKeyshareStore(keysharePath), nil
+}
+
+// mpcAddress returns address of the MPC key from the first relayer keyshare.
+func (d *Devnet) mpcAddress() (common.Address, error) {
+	key, err := d.keyshares[0].GetKeyshare()
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*key.Key.ECDSAPub.ToECDSAPubKey()), nil
+}
+
+func (d *Devnet) waitForKeygen(ctx context.Context) error {
+	ticker := time.NewTicker(d.config.BlockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			{
+				ready := true
+				for _, ks := range d.keyshares {
+					if _, err := ks.GetKeyshare(); err != nil {
+						ready = false
+						break
+					}
+				}
+				if ready {
+					log.Info().Msg("Keygen finished")
+					return nil
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (d *Devnet) waitForExecution(ctx context.Context, source uint8, destination *Chain) error {
+	client := destination.Client(d.depositor)
+	bridgeContract := bridge.NewBridgeContract(client, BridgeAddress, nil)
+	ticker := time.NewTicker(d.config.BlockTime)
+	defer ticker.Stop()
+
+	nonce := uint64(1)
+	for nonce <= uint64(d.config.Deposits) {
+		select {
+		case <-ticker.C:
+			{
+				executed, err := bridgeContract.IsProposalExecuted(&proposal.Proposal{Source: source, DepositNonce: nonce})
+				if err != nil {
+					return err
+				}
+				if executed {
+					log.Info().Msgf("Deposit %d from domain %d executed on domain %d", nonce, source, destination.DomainID)
+					nonce++
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
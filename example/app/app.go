@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -44,8 +45,12 @@ import (
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/ChainSafe/sygma-relayer/config"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/keyshare/consistency"
 	"github.com/ChainSafe/sygma-relayer/topology"
 	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/journal"
+	"github.com/ChainSafe/sygma-relayer/tss/presign"
+	"github.com/ChainSafe/sygma-relayer/tss/reputation"
 )
 
 func Run() error {
@@ -68,6 +73,7 @@ func Run() error {
 		panic(err)
 	}
 	blockstore := store.NewBlockStore(db)
+	sessionJournal := journal.NewSessionJournal(db)
 
 	privBytes, err := crypto.ConfigDecodeKey(configuration.RelayerConfig.MpcConfig.Key)
 	if err != nil {
@@ -86,11 +92,42 @@ func Run() error {
 
 	healthComm := p2p.NewCommunication(host, "p2p/health")
 	go comm.ExecuteCommHealthCheck(healthComm, host.Peerstore().Peers())
+	healthChecker := comm.NewHealthChecker(healthComm)
 
 	communication := p2p.NewCommunication(host, "p2p/sygma")
+	http.Handle("/health/messages", communication)
+	reputationTracker := reputation.NewTracker(configuration.RelayerConfig.MpcConfig.ReputationWindow)
+	http.Handle("/reputation", reputationTracker)
 	electorFactory := elector.NewCoordinatorElectorFactory(host, configuration.RelayerConfig.BullyConfig)
-	coordinator := tss.NewCoordinator(host, communication, electorFactory)
-	keyshareStore := keyshare.NewKeyshareStore(configuration.RelayerConfig.MpcConfig.KeysharePath)
+	coordinator := tss.NewCoordinator(host, communication, electorFactory, sessionJournal, reputationTracker)
+	keyshareEncryption, err := keyshare.NewEncryption(
+		configuration.RelayerConfig.MpcConfig.KeysharePassphrase, configuration.RelayerConfig.MpcConfig.KeyshareKeyFile,
+	)
+	if err != nil {
+		panic(err)
+	}
+	keyshareBackend, err := keyshare.NewBackend(
+		configuration.RelayerConfig.MpcConfig.KeyshareBackend,
+		configuration.RelayerConfig.MpcConfig.KeysharePath,
+		db,
+		configuration.RelayerConfig.MpcConfig.KeyshareRemoteURL,
+		configuration.RelayerConfig.MpcConfig.KeyshareRemoteToken,
+	)
+	if err != nil {
+		panic(err)
+	}
+	keyshareStore := keyshare.NewKeyshareStoreWithBackend(keyshareBackend, keyshareEncryption)
+	migrated, err := keyshareStore.Migrate()
+	if err != nil {
+		panic(err)
+	}
+	if migrated {
+		log.Info().Msgf("Encrypted plaintext keyshare in %s keyshare backend", configuration.RelayerConfig.MpcConfig.KeyshareBackend)
+	}
+	keyshareChecker := consistency.NewChecker(host, communication, keyshareStore)
+	http.Handle("/health/keyshare", keyshareChecker)
+	presignaturePool := presign.NewPoolWithEncryption(db, keyshareStore, keyshareEncryption, configuration.RelayerConfig.MpcConfig.PresignaturePoolSize)
+	presigner := presign.NewManager(presignaturePool, host, communication, coordinator, keyshareStore, configuration.RelayerConfig.MpcConfig.PresignaturePoolSize)
 
 	chains := []relayer.RelayedChain{}
 	for _, chainConfig := range configuration.ChainConfigs {
@@ -133,8 +170,10 @@ func Run() error {
 				tssListener := events.NewListener(client)
 				eventHandlers := make([]coreListener.EventHandler, 0)
 				eventHandlers = append(eventHandlers, coreListener.NewDepositEventHandler(depositListener, depositHandler, bridgeAddress, *config.GeneralChainConfig.Id))
-				eventHandlers = append(eventHandlers, listener.NewKeygenEventHandler(tssListener, coordinator, host, communication, keyshareStore, bridgeAddress, networkTopology.Threshold))
-				eventHandlers = append(eventHandlers, listener.NewRefreshEventHandler(nil, nil, tssListener, coordinator, host, communication, connectionGate, keyshareStore, bridgeAddress))
+				eventHandlers = append(eventHandlers, listener.NewKeygenEventHandler(tssListener, coordinator, host, communication, keyshareStore, sessionJournal, presigner, bridgeAddress, networkTopology.Threshold))
+				refreshEventHandler := listener.NewRefreshEventHandler(nil, nil, configuration.RelayerConfig.MpcConfig.TopologyConfiguration.HashMode, tssListener, coordinator, host, communication, connectionGate, healthChecker, keyshareStore, sessionJournal, presigner, keyshareChecker, db, bridgeAddress, *config.GeneralChainConfig.Id)
+				http.Handle(fmt.Sprintf("/health/refresh/%d", *config.GeneralChainConfig.Id), refreshEventHandler)
+				eventHandlers = append(eventHandlers, refreshEventHandler)
 				eventHandlers = append(eventHandlers, listener.NewRetryEventHandler(tssListener, depositHandler, bridgeAddress, *config.GeneralChainConfig.Id, config.BlockConfirmations))
 				evmListener := coreListener.NewEVMListener(client, eventHandlers, blockstore, config)
 
@@ -143,7 +182,12 @@ func Run() error {
 				mh.RegisterMessageHandler(config.Erc721Handler, coreExecutor.ERC721MessageHandler)
 				mh.RegisterMessageHandler(config.GenericHandler, coreExecutor.GenericMessageHandler)
 				mh.RegisterMessageHandler(pGenericHandler, executor.PermissionlessGenericMessageHandler)
-				executor := executor.NewExecutor(host, communication, coordinator, mh, bridgeContract, keyshareStore)
+				executor := executor.NewExecutor(host, communication, coordinator, mh, bridgeContract, keyshareStore, sessionJournal, presigner, reputationTracker, *config.GeneralChainConfig.Id)
+				executor.RequireProposalVerification = configuration.RelayerConfig.MpcConfig.RequireProposalVerification
+				err = executor.Resume()
+				if err != nil {
+					panic(err)
+				}
 
 				coreEvmChain := coreEvm.NewEVMChain(evmListener, nil, blockstore, config)
 				chain := evm.NewEVMChain(*coreEvmChain, executor)
@@ -164,6 +208,8 @@ func Run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go r.Start(ctx, errChn)
+	go presigner.Start(ctx)
+	go keyshareChecker.Start(ctx, configuration.RelayerConfig.MpcConfig.KeyshareCheckInterval)
 
 	sysErr := make(chan os.Signal, 1)
 	signal.Notify(sysErr,
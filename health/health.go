@@ -11,9 +11,10 @@ import (
 )
 
 // StartHealthEndpoint starts /health endpoint on provided port that returns ok on invocation
-func StartHealthEndpoint(port uint16) {
+// or degraded if not enough topology peers are reachable
+func StartHealthEndpoint(port uint16, peerMonitor *PeerMonitor) {
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte("ok"))
+		_, _ = w.Write([]byte(peerMonitor.Status()))
 	})
 	_ = http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
 	log.Info().Msgf("started /health endpoint on port %d", port)
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./health/peers.go
+
+// Package mock_health is a generated GoMock package.
+package mock_health
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	topology "github.com/ChainSafe/sygma-relayer/topology"
+	gomock "github.com/golang/mock/gomock"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+// MockPinger is a mock of Pinger interface.
+type MockPinger struct {
+	ctrl     *gomock.Controller
+	recorder *MockPingerMockRecorder
+}
+
+// MockPingerMockRecorder is the mock recorder for MockPinger.
+type MockPingerMockRecorder struct {
+	mock *MockPinger
+}
+
+// NewMockPinger creates a new mock instance.
+func NewMockPinger(ctrl *gomock.Controller) *MockPinger {
+	mock := &MockPinger{ctrl: ctrl}
+	mock.recorder = &MockPingerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPinger) EXPECT() *MockPingerMockRecorder {
+	return m.recorder
+}
+
+// Ping mocks base method.
+func (m *MockPinger) Ping(ctx context.Context, to peer.ID) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx, to)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockPingerMockRecorder) Ping(ctx, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockPinger)(nil).Ping), ctx, to)
+}
+
+// MockTopologyFetcher is a mock of TopologyFetcher interface.
+type MockTopologyFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockTopologyFetcherMockRecorder
+}
+
+// MockTopologyFetcherMockRecorder is the mock recorder for MockTopologyFetcher.
+type MockTopologyFetcherMockRecorder struct {
+	mock *MockTopologyFetcher
+}
+
+// NewMockTopologyFetcher creates a new mock instance.
+func NewMockTopologyFetcher(ctrl *gomock.Controller) *MockTopologyFetcher {
+	mock := &MockTopologyFetcher{ctrl: ctrl}
+	mock.recorder = &MockTopologyFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTopologyFetcher) EXPECT() *MockTopologyFetcherMockRecorder {
+	return m.recorder
+}
+
+// Topology mocks base method.
+func (m *MockTopologyFetcher) Topology() (topology.NetworkTopology, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Topology")
+	ret0, _ := ret[0].(topology.NetworkTopology)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Topology indicates an expected call of Topology.
+func (mr *MockTopologyFetcherMockRecorder) Topology() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Topology", reflect.TypeOf((*MockTopologyFetcher)(nil).Topology))
+}
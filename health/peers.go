@@ -0,0 +1,265 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ChainSafe/sygma-relayer/topology"
+)
+
+type Status string
+
+const (
+	// Healthy means at least threshold + 1 parties of the topology are reachable.
+	Healthy Status = "ok"
+	// Degraded means less than threshold + 1 parties are reachable and the relayer
+	// is not able to participate in signing.
+	Degraded Status = "degraded"
+)
+
+type PeerState string
+
+const (
+	Reachable   PeerState = "reachable"
+	Unreachable PeerState = "unreachable"
+	// Unknown means the peer was not pinged yet.
+	Unknown PeerState = "unknown"
+)
+
+type Pinger interface {
+	Ping(ctx context.Context, to peer.ID) (time.Duration, error)
+}
+
+type TopologyFetcher interface {
+	Topology() (topology.NetworkTopology, error)
+}
+
+// PeerStatus contains liveness of a single topology peer.
+type PeerStatus struct {
+	Peer        peer.ID   `json:"peer"`
+	State       PeerState `json:"state"`
+	RTT         string    `json:"rtt,omitempty"`
+	LastSeen    time.Time `json:"lastSeen"`
+	LastChecked time.Time `json:"lastChecked"`
+	ErrorStreak int       `json:"errorStreak"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// PeersReport is the result of the latest liveness check of all topology peers.
+type PeersReport struct {
+	Time      time.Time    `json:"time"`
+	Status    Status       `json:"status"`
+	Threshold int          `json:"threshold"`
+	Reachable int          `json:"reachable"`
+	Required  int          `json:"required"`
+	Peers     []PeerStatus `json:"peers"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// PeerMonitor periodically pings all peers of the current topology and keeps
+// their round trip time, last seen time and consecutive failed pings.
+// The monitor reports healthy status until the pinger is set and peers are checked.
+type PeerMonitor struct {
+	fetcher TopologyFetcher
+
+	// Timeout is the maximum duration of pinging a single peer
+	Timeout time.Duration
+
+	checkLock sync.Mutex
+	lock      sync.Mutex
+	self      peer.ID
+	pinger    Pinger
+	peers     map[peer.ID]*PeerStatus
+	report    PeersReport
+}
+
+func NewPeerMonitor(fetcher TopologyFetcher) *PeerMonitor {
+	return &PeerMonitor{
+		fetcher: fetcher,
+		Timeout: time.Second * 10,
+		peers:   make(map[peer.ID]*PeerStatus),
+		report: PeersReport{
+			Status: Healthy,
+			Peers:  []PeerStatus{},
+		},
+	}
+}
+
+// SetPinger sets the local peer ID and the pinger used to ping other peers. The monitor
+// is created before the libp2p host so the health endpoint is available while the relayer starts.
+func (m *PeerMonitor) SetPinger(self peer.ID, pinger Pinger) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.self = self
+	m.pinger = pinger
+}
+
+// Start checks peer liveness immediately and then every interval until the context is canceled.
+func (m *PeerMonitor) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx)
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check pings all peers of the current topology concurrently and updates their status.
+// Relayer itself is counted as a reachable party.
+func (m *PeerMonitor) Check(ctx context.Context) PeersReport {
+	m.checkLock.Lock()
+	defer m.checkLock.Unlock()
+
+	report := PeersReport{
+		Time:   time.Now(),
+		Status: Degraded,
+		Peers:  []PeerStatus{},
+	}
+	m.lock.Lock()
+	self, pinger := m.self, m.pinger
+	m.lock.Unlock()
+	if pinger == nil {
+		report.Error = "pinger not set"
+		m.setReport(report)
+		return report
+	}
+
+	networkTopology, err := m.fetcher.Topology()
+	if err != nil {
+		report.Error = err.Error()
+		m.setReport(report)
+		return report
+	}
+	report.Threshold = networkTopology.Threshold
+	report.Required = networkTopology.Threshold + 1
+
+	type pingResult struct {
+		peer peer.ID
+		rtt  time.Duration
+		err  error
+	}
+	results := make(chan pingResult, len(networkTopology.Peers))
+	peers := peer.IDSlice{}
+	for _, p := range networkTopology.Peers {
+		if p.ID == self {
+			report.Reachable++
+			continue
+		}
+
+		peers = append(peers, p.ID)
+		go func(p peer.ID) {
+			pingCtx, cancel := context.WithTimeout(ctx, m.Timeout)
+			defer cancel()
+			rtt, err := pinger.Ping(pingCtx, p)
+			results <- pingResult{peer: p, rtt: rtt, err: err}
+		}(p.ID)
+	}
+
+	for range peers {
+		result := <-results
+		m.update(result.peer, result.rtt, result.err, report.Time)
+	}
+
+	m.lock.Lock()
+	statuses := make(map[peer.ID]*PeerStatus)
+	for _, p := range peers {
+		status := m.peers[p]
+		statuses[p] = status
+		if status.State == Reachable {
+			report.Reachable++
+		}
+		report.Peers = append(report.Peers, *status)
+	}
+	// forget peers removed from the topology
+	m.peers = statuses
+	m.lock.Unlock()
+
+	if report.Reachable >= report.Required {
+		report.Status = Healthy
+	} else {
+		log.Warn().Msgf("Only %d of %d required parties are reachable", report.Reachable, report.Required)
+	}
+	m.setReport(report)
+	return report
+}
+
+// Status returns overall peer health from the latest check.
+func (m *PeerMonitor) Status() Status {
+	return m.Report().Status
+}
+
+// Report returns the latest liveness report.
+func (m *PeerMonitor) Report() PeersReport {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.report
+}
+
+// ServeHTTP returns the latest liveness report as JSON. Degraded health is
+// reported with the service unavailable status code.
+func (m *PeerMonitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := m.Report()
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == Degraded {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func (m *PeerMonitor) update(p peer.ID, rtt time.Duration, err error, checked time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	status, ok := m.peers[p]
+	if !ok {
+		status = &PeerStatus{
+			Peer:  p,
+			State: Unknown,
+		}
+		m.peers[p] = status
+	}
+	status.LastChecked = checked
+
+	if err != nil {
+		if status.State != Unreachable {
+			log.Warn().Err(err).Msgf("Peer %s is unreachable", p)
+		}
+		status.State = Unreachable
+		status.ErrorStreak++
+		status.LastError = err.Error()
+		return
+	}
+
+	if status.State == Unreachable {
+		log.Info().Msgf("Peer %s is reachable again after %d failed pings", p, status.ErrorStreak)
+	}
+	status.State = Reachable
+	status.RTT = rtt.String()
+	status.LastSeen = time.Now()
+	status.ErrorStreak = 0
+	status.LastError = ""
+}
+
+func (m *PeerMonitor) setReport(report PeersReport) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.report = report
+}
@@ -0,0 +1,144 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/health"
+	mock_health "github.com/ChainSafe/sygma-relayer/health/mock"
+	"github.com/ChainSafe/sygma-relayer/topology"
+)
+
+type PeerMonitorTestSuite struct {
+	suite.Suite
+	mockPinger          *mock_health.MockPinger
+	mockTopologyFetcher *mock_health.MockTopologyFetcher
+	peers               []*peer.AddrInfo
+	monitor             *health.PeerMonitor
+}
+
+func TestRunPeerMonitorTestSuite(t *testing.T) {
+	suite.Run(t, new(PeerMonitorTestSuite))
+}
+
+func (s *PeerMonitorTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockPinger = mock_health.NewMockPinger(ctrl)
+	s.mockTopologyFetcher = mock_health.NewMockTopologyFetcher(ctrl)
+
+	s.peers = []*peer.AddrInfo{}
+	for _, address := range []string{
+		"/dns4/relayer1/tcp/9000/p2p/QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX",
+		"/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT",
+		"/dns4/relayer3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK",
+	} {
+		p, _ := peer.AddrInfoFromString(address)
+		s.peers = append(s.peers, p)
+	}
+	s.mockTopologyFetcher.EXPECT().Topology().Return(topology.NetworkTopology{
+		Peers:     s.peers,
+		Threshold: 1,
+	}, nil).AnyTimes()
+
+	s.monitor = health.NewPeerMonitor(s.mockTopologyFetcher)
+	s.monitor.SetPinger(s.peers[0].ID, s.mockPinger)
+}
+
+func (s *PeerMonitorTestSuite) Test_Check_AllPeersReachable() {
+	s.mockPinger.EXPECT().Ping(gomock.Any(), s.peers[1].ID).Return(time.Millisecond*5, nil)
+	s.mockPinger.EXPECT().Ping(gomock.Any(), s.peers[2].ID).Return(time.Millisecond*7, nil)
+
+	report := s.monitor.Check(context.Background())
+
+	s.Equal(health.Healthy, report.Status)
+	s.Equal(3, report.Reachable)
+	s.Equal(2, report.Required)
+	s.Equal(2, len(report.Peers))
+	for _, p := range report.Peers {
+		s.Equal(health.Reachable, p.State)
+		s.Equal(0, p.ErrorStreak)
+		s.False(p.LastSeen.IsZero())
+	}
+	s.Equal(health.Healthy, s.monitor.Status())
+}
+
+func (s *PeerMonitorTestSuite) Test_Check_TracksErrorStreak() {
+	s.mockPinger.EXPECT().Ping(gomock.Any(), s.peers[1].ID).Return(time.Millisecond*5, nil).Times(3)
+	s.mockPinger.EXPECT().Ping(gomock.Any(), s.peers[2].ID).Return(time.Duration(0), errors.New("error")).Times(2)
+	s.mockPinger.EXPECT().Ping(gomock.Any(), s.peers[2].ID).Return(time.Millisecond*7, nil)
+
+	s.monitor.Check(context.Background())
+	report := s.monitor.Check(context.Background())
+
+	s.Equal(health.Healthy, report.Status)
+	s.Equal(2, report.Reachable)
+	status := s.peerStatus(report, s.peers[2].ID)
+	s.Equal(health.Unreachable, status.State)
+	s.Equal(2, status.ErrorStreak)
+	s.Equal("error", status.LastError)
+
+	report = s.monitor.Check(context.Background())
+
+	status = s.peerStatus(report, s.peers[2].ID)
+	s.Equal(health.Reachable, status.State)
+	s.Equal(0, status.ErrorStreak)
+	s.Equal("", status.LastError)
+	s.Equal("7ms", status.RTT)
+}
+
+func (s *PeerMonitorTestSuite) Test_Check_NotEnoughPeersReachable() {
+	s.mockPinger.EXPECT().Ping(gomock.Any(), gomock.Any()).Return(time.Duration(0), errors.New("error")).Times(2)
+
+	report := s.monitor.Check(context.Background())
+
+	s.Equal(health.Degraded, report.Status)
+	s.Equal(1, report.Reachable)
+	s.Equal(health.Degraded, s.monitor.Status())
+}
+
+func (s *PeerMonitorTestSuite) Test_Check_PingerNotSet() {
+	monitor := health.NewPeerMonitor(s.mockTopologyFetcher)
+	s.Equal(health.Healthy, monitor.Status())
+
+	report := monitor.Check(context.Background())
+
+	s.Equal(health.Degraded, report.Status)
+	s.NotEqual("", report.Error)
+}
+
+func (s *PeerMonitorTestSuite) Test_ServeHTTP_Degraded() {
+	s.mockPinger.EXPECT().Ping(gomock.Any(), gomock.Any()).Return(time.Duration(0), errors.New("error")).Times(2)
+	s.monitor.Check(context.Background())
+
+	recorder := httptest.NewRecorder()
+	s.monitor.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/peers", nil))
+
+	s.Equal(http.StatusServiceUnavailable, recorder.Code)
+	report := health.PeersReport{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &report)
+	s.Nil(err)
+	s.Equal(health.Degraded, report.Status)
+	s.Equal(2, len(report.Peers))
+}
+
+func (s *PeerMonitorTestSuite) peerStatus(report health.PeersReport, p peer.ID) health.PeerStatus {
+	for _, status := range report.Peers {
+		if status.Peer == p {
+			return status
+		}
+	}
+	s.Fail("peer status not found")
+	return health.PeerStatus{}
+}
@@ -0,0 +1,79 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	FileBackendType   = "file"
+	LvlDBBackendType  = "lvldb"
+	RemoteBackendType = "remote"
+)
+
+var ErrNotFound = errors.New("keyshare not found")
+
+// Backend persists serialized keyshares. Backends only store opaque data, encryption
+// and keyshare versions are handled by the KeyshareStore so they work the same way
+// for all backends.
+type Backend interface {
+	// Read returns stored data or ErrNotFound if nothing was stored yet
+	Read() ([]byte, error)
+	// Write replaces stored data
+	Write(data []byte) error
+}
+
+// NewBackend creates keyshare backend of the provided type. File backend stores keyshares
+// to the path, lvldb backend into the db and remote backend to the remote URL.
+func NewBackend(backendType string, path string, db KeyValueReaderWriter, remoteURL string, remoteToken string) (Backend, error) {
+	switch backendType {
+	case FileBackendType, "":
+		return NewFileBackend(path), nil
+	case LvlDBBackendType:
+		if db == nil {
+			return nil, errors.New("lvldb keyshare backend requires a database")
+		}
+		return NewLvlDBBackend(db), nil
+	case RemoteBackendType:
+		if remoteURL == "" {
+			return nil, errors.New("remote keyshare backend requires an URL")
+		}
+		return NewRemoteBackend(remoteURL, remoteToken), nil
+	default:
+		return nil, fmt.Errorf("unknown keyshare backend %s", backendType)
+	}
+}
+
+// FileBackend stores keyshares into a local file.
+type FileBackend struct {
+	path string
+}
+
+func NewFileBackend(path string) *FileBackend {
+	return &FileBackend{
+		path: path,
+	}
+}
+
+func (b *FileBackend) Read() ([]byte, error) {
+	kb, err := ioutil.ReadFile(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, err)
+	}
+	return kb, err
+}
+
+// Write replaces the keyshare file so the old keyshare is kept if writing fails.
+func (b *FileBackend) Write(data []byte) error {
+	tmpPath := b.path + ".tmp"
+	err := ioutil.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, b.path)
+}
@@ -0,0 +1,142 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-core/lvldb"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type LvlDBBackendTestSuite struct {
+	suite.Suite
+	db            *lvldb.LVLDB
+	keyshareStore *keyshare.KeyshareStore
+}
+
+func TestRunLvlDBBackendTestSuite(t *testing.T) {
+	suite.Run(t, new(LvlDBBackendTestSuite))
+}
+
+func (s *LvlDBBackendTestSuite) SetupTest() {
+	db, err := lvldb.NewLvlDB(s.T().TempDir())
+	s.Nil(err)
+	s.db = db
+	s.keyshareStore = keyshare.NewKeyshareStoreWithBackend(keyshare.NewLvlDBBackend(db), nil)
+}
+func (s *LvlDBBackendTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *LvlDBBackendTestSuite) Test_Read_MissingKeyshare() {
+	_, err := keyshare.NewLvlDBBackend(s.db).Read()
+
+	s.True(errors.Is(err, keyshare.ErrNotFound))
+}
+
+func (s *LvlDBBackendTestSuite) Test_StoreAndRetrieveShare() {
+	peer1, _ := peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	keyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 3, []peer.ID{peer1})
+
+	err := s.keyshareStore.StoreKeyshare(keyshare, "keygen")
+	s.Nil(err)
+
+	storedKeyshare, err := s.keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(keyshare, storedKeyshare)
+}
+
+type secretStore struct {
+	mu     sync.Mutex
+	token  string
+	secret []byte
+}
+
+func (ss *secretStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+ss.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if ss.secret == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(ss.secret)
+	case http.MethodPut:
+		ss.secret, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+type RemoteBackendTestSuite struct {
+	suite.Suite
+	secretStore *secretStore
+	server      *httptest.Server
+}
+
+func TestRunRemoteBackendTestSuite(t *testing.T) {
+	suite.Run(t, new(RemoteBackendTestSuite))
+}
+
+func (s *RemoteBackendTestSuite) SetupTest() {
+	s.secretStore = &secretStore{token: "token"}
+	s.server = httptest.NewServer(s.secretStore)
+}
+func (s *RemoteBackendTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *RemoteBackendTestSuite) Test_Read_MissingKeyshare() {
+	_, err := keyshare.NewRemoteBackend(s.server.URL, "token").Read()
+
+	s.True(errors.Is(err, keyshare.ErrNotFound))
+}
+
+func (s *RemoteBackendTestSuite) Test_Read_Unauthorized() {
+	s.secretStore.secret = []byte("keyshare")
+
+	_, err := keyshare.NewRemoteBackend(s.server.URL, "invalid").Read()
+
+	s.NotNil(err)
+	s.False(errors.Is(err, keyshare.ErrNotFound))
+}
+
+func (s *RemoteBackendTestSuite) Test_Write_Unauthorized() {
+	err := keyshare.NewRemoteBackend(s.server.URL, "invalid").Write([]byte("keyshare"))
+
+	s.NotNil(err)
+	s.Nil(s.secretStore.secret)
+}
+
+func (s *RemoteBackendTestSuite) Test_StoreAndRetrieveEncryptedShare() {
+	encryption, _ := keyshare.NewPassphraseEncryption("passphrase")
+	keyshareStore := keyshare.NewKeyshareStoreWithBackend(keyshare.NewRemoteBackend(s.server.URL, "token"), encryption)
+	peer1, _ := peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	keyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 3, []peer.ID{peer1})
+
+	err := keyshareStore.StoreKeyshare(keyshare, "keygen")
+	s.Nil(err)
+
+	s.NotContains(string(s.secretStore.secret), "Threshold")
+	storedKeyshare, err := keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(keyshare, storedKeyshare)
+}
@@ -0,0 +1,416 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package consistency
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+)
+
+const checkSessionID = "keyshare-check"
+
+type Status string
+
+const (
+	// Consistent means all peers hold a share of the same key with the same threshold and peers.
+	Consistent Status = "consistent"
+	// Divergent means at least one peer holds a different keyshare.
+	Divergent Status = "divergent"
+	// Incomplete means responding peers agree but some peers didn't respond.
+	Incomplete Status = "incomplete"
+	// Unreachable means the peer didn't respond to the check.
+	Unreachable Status = "unreachable"
+	// Unknown means the check wasn't executed or the relayer has no keyshare.
+	Unknown Status = "unknown"
+)
+
+type KeyshareFetcher interface {
+	GetKeyshare() (keyshare.Keyshare, error)
+	Versions() ([]keyshare.Version, error)
+}
+
+// KeyshareInfo contains public data of the keyshare that relayers compare
+// to verify they hold shares of the same key.
+type KeyshareInfo struct {
+	PublicKey  string    `json:"publicKey"`
+	Address    string    `json:"address"`
+	Threshold  int       `json:"threshold"`
+	Peers      []peer.ID `json:"peers"`
+	Generation int       `json:"generation"`
+}
+
+type checkMessage struct {
+	CheckID  string        `json:"checkID"`
+	Keyshare *KeyshareInfo `json:"keyshare,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// PeerReport contains the keyshare reported by the peer and differences from the local keyshare.
+type PeerReport struct {
+	Peer       peer.ID       `json:"peer"`
+	Status     Status        `json:"status"`
+	Keyshare   *KeyshareInfo `json:"keyshare,omitempty"`
+	Divergence []string      `json:"divergence,omitempty"`
+}
+
+// Report is the result of a single consistency check.
+type Report struct {
+	Time      time.Time     `json:"time"`
+	SessionID string        `json:"sessionID,omitempty"`
+	Status    Status        `json:"status"`
+	Keyshare  *KeyshareInfo `json:"keyshare,omitempty"`
+	Peers     []PeerReport  `json:"peers"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Checker verifies that all peers from the keyshare hold a share of the same public key and
+// agree on threshold and peers. Keyshare generation is reported but not compared as it is
+// local to each relayer.
+type Checker struct {
+	host          host.Host
+	communication comm.Communication
+	fetcher       KeyshareFetcher
+
+	// Timeout is the time the checker waits for peers to respond
+	Timeout time.Duration
+	// SettleDelay is the time the checker waits after resharing for other peers to store the new keyshare
+	SettleDelay time.Duration
+
+	checkLock sync.Mutex
+	lock      sync.Mutex
+	checkID   string
+	responses chan *comm.WrappedMessage
+	report    Report
+}
+
+func NewChecker(host host.Host, communication comm.Communication, fetcher KeyshareFetcher) *Checker {
+	return &Checker{
+		host:          host,
+		communication: communication,
+		fetcher:       fetcher,
+		Timeout:       time.Second * 15,
+		SettleDelay:   time.Second * 10,
+		report: Report{
+			Status: Unknown,
+			Peers:  []PeerReport{},
+		},
+	}
+}
+
+// Start responds to keyshare checks of other peers and checks keyshare consistency
+// every interval until the context is canceled. Checks can only be executed after
+// the checker is started.
+func (c *Checker) Start(ctx context.Context, interval time.Duration) {
+	msgChn := make(chan *comm.WrappedMessage, 10)
+	checkSubID := c.communication.Subscribe(checkSessionID, comm.KeyshareCheckMsg, msgChn)
+	responseSubID := c.communication.Subscribe(checkSessionID, comm.KeyshareCheckResponseMsg, msgChn)
+	defer c.communication.UnSubscribe(checkSubID)
+	defer c.communication.UnSubscribe(responseSubID)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-msgChn:
+			switch msg.MessageType {
+			case comm.KeyshareCheckMsg:
+				c.respond(msg)
+			case comm.KeyshareCheckResponseMsg:
+				c.routeResponse(msg)
+			}
+		case <-ticker.C:
+			go c.Check(ctx, "")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Trigger checks keyshare consistency after keyshare of the session was stored.
+func (c *Checker) Trigger(sessionID string) {
+	go func() {
+		time.Sleep(c.SettleDelay)
+		c.Check(context.Background(), sessionID)
+	}()
+}
+
+// Check requests keyshare data from all peers of the local keyshare and compares it
+// with the local keyshare. Report of the check is logged and returned by the checker
+// health endpoint.
+func (c *Checker) Check(ctx context.Context, sessionID string) Report {
+	c.checkLock.Lock()
+	defer c.checkLock.Unlock()
+
+	report := Report{
+		Time:      time.Now(),
+		SessionID: sessionID,
+		Status:    Unknown,
+		Peers:     []PeerReport{},
+	}
+	local, err := c.keyshareInfo()
+	if err != nil {
+		report.Error = err.Error()
+		c.setReport(report)
+		return report
+	}
+	report.Keyshare = local
+
+	peers := peer.IDSlice{}
+	for _, p := range local.Peers {
+		if p != c.host.ID() {
+			peers = append(peers, p)
+		}
+	}
+	responses := c.request(ctx, local, peers)
+
+	report.Status = Consistent
+	for _, p := range peers {
+		peerReport := PeerReport{
+			Peer:   p,
+			Status: Consistent,
+		}
+
+		msg, ok := responses[p]
+		switch {
+		case !ok:
+			peerReport.Status = Unreachable
+		case msg.Error != "":
+			peerReport.Status = Divergent
+			peerReport.Divergence = []string{msg.Error}
+		default:
+			peerReport.Keyshare = msg.Keyshare
+			peerReport.Divergence = compare(local, msg.Keyshare)
+			if len(peerReport.Divergence) > 0 {
+				peerReport.Status = Divergent
+			}
+		}
+
+		if peerReport.Status == Divergent {
+			report.Status = Divergent
+		} else if peerReport.Status == Unreachable && report.Status == Consistent {
+			report.Status = Incomplete
+		}
+		report.Peers = append(report.Peers, peerReport)
+	}
+
+	c.setReport(report)
+	return report
+}
+
+// Report returns report of the latest keyshare check.
+func (c *Checker) Report() Report {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.report
+}
+
+// ServeHTTP returns the latest keyshare check report as JSON. Divergent keyshares
+// are reported with the service unavailable status code.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Report()
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == Divergent {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// request broadcasts local keyshare data to peers and waits until
+// all peers respond or the timeout is reached.
+func (c *Checker) request(ctx context.Context, local *KeyshareInfo, peers peer.IDSlice) map[peer.ID]checkMessage {
+	responses := make(map[peer.ID]checkMessage)
+	if len(peers) == 0 {
+		return responses
+	}
+
+	checkID := make([]byte, 16)
+	_, _ = rand.Read(checkID)
+	responseChn := make(chan *comm.WrappedMessage, len(peers))
+	c.lock.Lock()
+	c.checkID = hex.EncodeToString(checkID)
+	c.responses = responseChn
+	c.lock.Unlock()
+	defer func() {
+		c.lock.Lock()
+		c.checkID = ""
+		c.responses = nil
+		c.lock.Unlock()
+	}()
+
+	msg, _ := json.Marshal(checkMessage{
+		CheckID:  hex.EncodeToString(checkID),
+		Keyshare: local,
+	})
+	c.communication.Broadcast(peers, msg, comm.KeyshareCheckMsg, checkSessionID, nil)
+
+	timeout := time.NewTimer(c.Timeout)
+	defer timeout.Stop()
+	for len(responses) < len(peers) {
+		select {
+		case wMsg := <-responseChn:
+			response := checkMessage{}
+			err := json.Unmarshal(wMsg.Payload, &response)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Invalid keyshare check response from %s", wMsg.From)
+				continue
+			}
+			if !slices.Contains(peers, wMsg.From) {
+				continue
+			}
+			responses[wMsg.From] = response
+		case <-timeout.C:
+			return responses
+		case <-ctx.Done():
+			return responses
+		}
+	}
+	return responses
+}
+
+// respond sends local keyshare data to the peer that requested the check.
+func (c *Checker) respond(msg *comm.WrappedMessage) {
+	request := checkMessage{}
+	err := json.Unmarshal(msg.Payload, &request)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Invalid keyshare check request from %s", msg.From)
+		return
+	}
+
+	response := checkMessage{
+		CheckID: request.CheckID,
+	}
+	response.Keyshare, err = c.keyshareInfo()
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	payload, _ := json.Marshal(response)
+	c.communication.Broadcast(peer.IDSlice{msg.From}, payload, comm.KeyshareCheckResponseMsg, checkSessionID, nil)
+}
+
+// routeResponse passes the response to the pending check
+func (c *Checker) routeResponse(msg *comm.WrappedMessage) {
+	response := checkMessage{}
+	err := json.Unmarshal(msg.Payload, &response)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Invalid keyshare check response from %s", msg.From)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.responses == nil || response.CheckID != c.checkID {
+		return
+	}
+	select {
+	case c.responses <- msg:
+	default:
+	}
+}
+
+func (c *Checker) keyshareInfo() (*KeyshareInfo, error) {
+	key, err := c.fetcher.GetKeyshare()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &KeyshareInfo{
+		PublicKey: key.PublicKey(),
+		Address:   key.Address(),
+		Threshold: key.Threshold,
+		Peers:     key.Peers,
+	}
+
+	versions, err := c.fetcher.Versions()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Active {
+			info.Generation = v.Generation
+		}
+	}
+
+	return info, nil
+}
+
+func (c *Checker) setReport(report Report) {
+	c.lock.Lock()
+	c.report = report
+	c.lock.Unlock()
+
+	logger := log.With().Str("SessionID", report.SessionID).Logger()
+	switch report.Status {
+	case Consistent:
+		logger.Info().Msgf("Keyshare consistent with all %d peers", len(report.Peers))
+	case Incomplete:
+		for _, p := range report.Peers {
+			if p.Status == Unreachable {
+				logger.Warn().Msgf("Keyshare consistency unknown, peer %s didn't respond", p.Peer)
+			}
+		}
+	case Divergent:
+		for _, p := range report.Peers {
+			if p.Status == Divergent {
+				logger.Error().Msgf("Keyshare diverges from peer %s: %v", p.Peer, p.Divergence)
+			}
+		}
+	default:
+		logger.Warn().Msgf("Unable to check keyshare consistency: %s", report.Error)
+	}
+}
+
+// compare returns fields in which the keyshare differs from the local keyshare
+func compare(local *KeyshareInfo, remote *KeyshareInfo) []string {
+	if remote == nil {
+		return []string{"missing keyshare"}
+	}
+
+	divergence := []string{}
+	if local.PublicKey != remote.PublicKey {
+		divergence = append(divergence, "public key")
+	}
+	if local.Threshold != remote.Threshold {
+		divergence = append(divergence, "threshold")
+	}
+	if !samePeers(local.Peers, remote.Peers) {
+		divergence = append(divergence, "peers")
+	}
+	return divergence
+}
+
+func samePeers(p1 []peer.ID, p2 []peer.ID) bool {
+	if len(p1) != len(p2) {
+		return false
+	}
+
+	sorted1 := make(peer.IDSlice, len(p1))
+	copy(sorted1, p1)
+	sort.Sort(sorted1)
+	sorted2 := make(peer.IDSlice, len(p2))
+	copy(sorted2, p2)
+	sort.Sort(sorted2)
+	for i := range sorted1 {
+		if sorted1[i] != sorted2[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package consistency_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/keyshare/consistency"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckerTestSuite struct {
+	suite.Suite
+	hosts    []host.Host
+	stores   []*keyshare.KeyshareStore
+	checkers []*consistency.Checker
+	ctx      context.Context
+	cancel   context.CancelFunc
+	dir      string
+}
+
+func TestRunCheckerTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckerTestSuite))
+}
+
+func (s *CheckerTestSuite) SetupTest() {
+	s.dir, _ = ioutil.TempDir("", "keyshares")
+	network := memory.NewNetwork()
+	s.hosts = []host.Host{}
+	s.stores = []*keyshare.KeyshareStore{}
+	s.checkers = []*consistency.Checker{}
+	for i := 0; i < 3; i++ {
+		h, err := tsstest.NewHost(i)
+		s.Nil(err)
+		kb, err := ioutil.ReadFile(fmt.Sprintf("../../tss/test/keyshares/%d.keyshare", i))
+		s.Nil(err)
+		path := fmt.Sprintf("%s/%d.keyshare", s.dir, i)
+		err = ioutil.WriteFile(path, kb, 0600)
+		s.Nil(err)
+
+		store := keyshare.NewKeyshareStore(path)
+		checker := consistency.NewChecker(h, memory.NewCommunication(network, h.ID()), store)
+		checker.Timeout = time.Millisecond * 500
+		checker.SettleDelay = 0
+		s.hosts = append(s.hosts, h)
+		s.stores = append(s.stores, store)
+		s.checkers = append(s.checkers, checker)
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+func (s *CheckerTestSuite) TearDownTest() {
+	s.cancel()
+	for _, h := range s.hosts {
+		_ = h.Close()
+	}
+	os.RemoveAll(s.dir)
+}
+
+func (s *CheckerTestSuite) start(checkers ...*consistency.Checker) {
+	for _, checker := range checkers {
+		go checker.Start(s.ctx, time.Hour)
+	}
+	// wait for checkers to subscribe
+	time.Sleep(time.Millisecond * 50)
+}
+
+func (s *CheckerTestSuite) Test_Check_Consistent() {
+	s.start(s.checkers...)
+
+	report := s.checkers[0].Check(context.Background(), "")
+
+	s.Equal(consistency.Consistent, report.Status)
+	s.Equal(1, report.Keyshare.Generation)
+	s.Equal(1, report.Keyshare.Threshold)
+	s.Equal(2, len(report.Peers))
+	for _, p := range report.Peers {
+		s.Equal(consistency.Consistent, p.Status)
+		s.Equal(report.Keyshare.PublicKey, p.Keyshare.PublicKey)
+		s.Equal(report.Keyshare.Address, p.Keyshare.Address)
+	}
+}
+
+func (s *CheckerTestSuite) Test_Check_DivergentThreshold() {
+	key, err := s.stores[2].GetKeyshare()
+	s.Nil(err)
+	key.Threshold = 2
+	err = s.stores[2].StoreKeyshare(key, "resharing")
+	s.Nil(err)
+	s.start(s.checkers...)
+
+	report := s.checkers[0].Check(context.Background(), "")
+
+	s.Equal(consistency.Divergent, report.Status)
+	for _, p := range report.Peers {
+		if p.Peer == s.hosts[2].ID() {
+			s.Equal(consistency.Divergent, p.Status)
+			s.Equal([]string{"threshold"}, p.Divergence)
+			s.Equal(2, p.Keyshare.Generation)
+		} else {
+			s.Equal(consistency.Consistent, p.Status)
+		}
+	}
+}
+
+func (s *CheckerTestSuite) Test_Check_PeerWithoutKeyshare() {
+	err := os.Remove(fmt.Sprintf("%s/%d.keyshare", s.dir, 1))
+	s.Nil(err)
+	s.start(s.checkers...)
+
+	report := s.checkers[0].Check(context.Background(), "")
+
+	s.Equal(consistency.Divergent, report.Status)
+}
+
+func (s *CheckerTestSuite) Test_Check_UnreachablePeer() {
+	s.start(s.checkers[0], s.checkers[1])
+
+	report := s.checkers[0].Check(context.Background(), "")
+
+	s.Equal(consistency.Incomplete, report.Status)
+	for _, p := range report.Peers {
+		if p.Peer == s.hosts[2].ID() {
+			s.Equal(consistency.Unreachable, p.Status)
+		} else {
+			s.Equal(consistency.Consistent, p.Status)
+		}
+	}
+}
+
+func (s *CheckerTestSuite) Test_Check_NoLocalKeyshare() {
+	err := os.Remove(fmt.Sprintf("%s/%d.keyshare", s.dir, 0))
+	s.Nil(err)
+
+	report := s.checkers[0].Check(context.Background(), "")
+
+	s.Equal(consistency.Unknown, report.Status)
+	s.NotEqual("", report.Error)
+}
+
+func (s *CheckerTestSuite) Test_Trigger_UpdatesReport() {
+	s.start(s.checkers...)
+
+	s.checkers[0].Trigger("resharing-1")
+
+	s.Eventually(func() bool {
+		return s.checkers[0].Report().SessionID == "resharing-1"
+	}, time.Second*5, time.Millisecond*10)
+	s.Equal(consistency.Consistent, s.checkers[0].Report().Status)
+}
+
+func (s *CheckerTestSuite) Test_ServeHTTP_Divergent() {
+	key, _ := s.stores[1].GetKeyshare()
+	key.Peers = key.Peers[1:]
+	_ = s.stores[1].StoreKeyshare(key, "resharing")
+	s.start(s.checkers...)
+	s.checkers[0].Check(context.Background(), "")
+
+	recorder := httptest.NewRecorder()
+	s.checkers[0].ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/keyshare", nil))
+
+	s.Equal(http.StatusServiceUnavailable, recorder.Code)
+	s.Contains(recorder.Body.String(), "\"status\":\"divergent\"")
+}
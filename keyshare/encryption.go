@@ -0,0 +1,273 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	envelopeVersion = 1
+
+	kdfScrypt  = "scrypt"
+	kdfKeyFile = "keyfile"
+
+	keyLength  = 32
+	saltLength = 32
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	ErrInvalidEncryptionKey = errors.New("invalid keyshare encryption key")
+	ErrKeyshareEncrypted    = errors.New("keyshare file is encrypted but no encryption key was provided")
+)
+
+// envelope is the format of encrypted keyshare files. Keyshares are encrypted with a random
+// data key which is stored wrapped with the key encryption key derived from the passphrase
+// or read from the key file.
+type envelope struct {
+	Version    int        `json:"version"`
+	KDF        string     `json:"kdf"`
+	KDFParams  *kdfParams `json:"kdfParams,omitempty"`
+	WrappedKey []byte     `json:"wrappedKey"`
+	Ciphertext []byte     `json:"ciphertext"`
+}
+
+type kdfParams struct {
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// Encryption encrypts keyshare files with AES-256-GCM using a key
+// derived from a passphrase or read from a key file.
+type Encryption struct {
+	kdf        string
+	passphrase []byte
+	key        []byte
+
+	mu      sync.Mutex
+	salt    []byte
+	derived map[string][]byte
+}
+
+// NewEncryption creates encryption from the passphrase or the key file, whichever is provided.
+// Returns nil if neither of them is provided, which means keyshares are stored in plaintext.
+func NewEncryption(passphrase string, keyFile string) (*Encryption, error) {
+	switch {
+	case passphrase != "" && keyFile != "":
+		return nil, errors.New("only one of keyshare passphrase or key file can be provided")
+	case passphrase != "":
+		return NewPassphraseEncryption(passphrase)
+	case keyFile != "":
+		return NewKeyFileEncryption(keyFile)
+	default:
+		return nil, nil
+	}
+}
+
+// NewPassphraseEncryption creates encryption with the key derived from the passphrase using scrypt.
+func NewPassphraseEncryption(passphrase string) (*Encryption, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty keyshare passphrase")
+	}
+
+	salt := make([]byte, saltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encryption{
+		kdf:        kdfScrypt,
+		passphrase: []byte(passphrase),
+		salt:       salt,
+		derived:    make(map[string][]byte),
+	}, nil
+}
+
+// NewKeyFileEncryption creates encryption with the 32 byte key read from the key file.
+// Key can be stored either as raw bytes or hex encoded.
+func NewKeyFileEncryption(path string) (*Encryption, error) {
+	kb, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read keyshare key file: %w", err)
+	}
+
+	key := kb
+	if len(kb) != keyLength {
+		key, err = hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(kb)), "0x"))
+		if err != nil || len(key) != keyLength {
+			return nil, fmt.Errorf("keyshare key file must contain a %d byte key", keyLength)
+		}
+	}
+
+	return &Encryption{
+		kdf: kdfKeyFile,
+		key: key,
+	}, nil
+}
+
+// Encrypt encrypts the keyshare file content into the encrypted envelope.
+func (e *Encryption) Encrypt(plaintext []byte) ([]byte, error) {
+	env := envelope{
+		Version: envelopeVersion,
+		KDF:     e.kdf,
+	}
+	if e.kdf == kdfScrypt {
+		env.KDFParams = &kdfParams{
+			Salt: e.salt,
+			N:    scryptN,
+			R:    scryptR,
+			P:    scryptP,
+		}
+	}
+
+	kek, err := e.keyEncryptionKey(env.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, keyLength)
+	_, err = rand.Read(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	env.WrappedKey, err = seal(kek, dataKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	env.Ciphertext, err = seal(dataKey, plaintext, env.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(env)
+}
+
+// Decrypt decrypts the encrypted envelope into the keyshare file content.
+// Returns ErrInvalidEncryptionKey if the envelope was encrypted with a different key.
+func (e *Encryption) Decrypt(data []byte) ([]byte, error) {
+	env := envelope{}
+	err := json.Unmarshal(data, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	if env.Version != envelopeVersion {
+		return nil, fmt.Errorf("unsupported keyshare encryption version %d", env.Version)
+	}
+	if env.KDF != e.kdf {
+		return nil, fmt.Errorf("%w: keyshare is encrypted with %s key, %s key provided", ErrInvalidEncryptionKey, env.KDF, e.kdf)
+	}
+
+	kek, err := e.keyEncryptionKey(env.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := open(kek, env.WrappedKey, nil)
+	if err != nil {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	plaintext, err := open(dataKey, env.Ciphertext, env.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("keyshare file corrupted: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// keyEncryptionKey returns the key used to wrap data keys. Keys derived from the passphrase
+// are cached by salt as key derivation is intentionally slow.
+func (e *Encryption) keyEncryptionKey(params *kdfParams) ([]byte, error) {
+	if e.kdf == kdfKeyFile {
+		return e.key, nil
+	}
+
+	if params == nil {
+		return nil, errors.New("missing keyshare key derivation parameters")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cacheKey := fmt.Sprintf("%x:%d:%d:%d", params.Salt, params.N, params.R, params.P)
+	if key, ok := e.derived[cacheKey]; ok {
+		return key, nil
+	}
+
+	key, err := scrypt.Key(e.passphrase, params.Salt, params.N, params.R, params.P, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	e.derived[cacheKey] = key
+	return key, nil
+}
+
+// isEncrypted checks if keyshare file content is an encrypted envelope.
+func isEncrypted(data []byte) bool {
+	env := envelope{}
+	err := json.Unmarshal(data, &env)
+	if err != nil {
+		return false
+	}
+
+	return env.Ciphertext != nil
+}
+
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce := ciphertext[:gcm.NonceSize()]
+	return gcm.Open(nil, nonce, ciphertext[gcm.NonceSize():], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,209 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type EncryptionTestSuite struct {
+	suite.Suite
+	keyPath string
+}
+
+func TestRunEncryptionTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptionTestSuite))
+}
+
+func (s *EncryptionTestSuite) SetupTest() {
+	s.keyPath = "keyshare.key"
+	err := ioutil.WriteFile(s.keyPath, []byte(hex.EncodeToString(make([]byte, 32))+"\n"), 0600)
+	s.Nil(err)
+}
+func (s *EncryptionTestSuite) TearDownTest() {
+	os.Remove(s.keyPath)
+}
+
+func (s *EncryptionTestSuite) Test_Passphrase_EncryptDecrypt() {
+	encryption, _ := keyshare.NewPassphraseEncryption("passphrase")
+
+	ciphertext, err := encryption.Encrypt([]byte("keyshare"))
+	s.Nil(err)
+	s.NotContains(string(ciphertext), "keyshare")
+
+	decryption, _ := keyshare.NewPassphraseEncryption("passphrase")
+	plaintext, err := decryption.Decrypt(ciphertext)
+	s.Nil(err)
+	s.Equal([]byte("keyshare"), plaintext)
+}
+
+func (s *EncryptionTestSuite) Test_Passphrase_InvalidPassphrase() {
+	encryption, _ := keyshare.NewPassphraseEncryption("passphrase")
+	ciphertext, _ := encryption.Encrypt([]byte("keyshare"))
+
+	decryption, _ := keyshare.NewPassphraseEncryption("invalid")
+	_, err := decryption.Decrypt(ciphertext)
+
+	s.Equal(keyshare.ErrInvalidEncryptionKey, err)
+}
+
+func (s *EncryptionTestSuite) Test_KeyFile_EncryptDecrypt() {
+	encryption, err := keyshare.NewKeyFileEncryption(s.keyPath)
+	s.Nil(err)
+
+	ciphertext, err := encryption.Encrypt([]byte("keyshare"))
+	s.Nil(err)
+	plaintext, err := encryption.Decrypt(ciphertext)
+	s.Nil(err)
+	s.Equal([]byte("keyshare"), plaintext)
+}
+
+func (s *EncryptionTestSuite) Test_KeyFile_InvalidKeyLength() {
+	err := ioutil.WriteFile(s.keyPath, []byte("abcd"), 0600)
+	s.Nil(err)
+
+	_, err = keyshare.NewKeyFileEncryption(s.keyPath)
+
+	s.NotNil(err)
+}
+
+func (s *EncryptionTestSuite) Test_KeyFile_DecryptPassphraseEncrypted() {
+	encryption, _ := keyshare.NewPassphraseEncryption("passphrase")
+	ciphertext, _ := encryption.Encrypt([]byte("keyshare"))
+
+	decryption, _ := keyshare.NewKeyFileEncryption(s.keyPath)
+	_, err := decryption.Decrypt(ciphertext)
+
+	s.True(errors.Is(err, keyshare.ErrInvalidEncryptionKey))
+}
+
+func (s *EncryptionTestSuite) Test_NewEncryption_NoKey() {
+	encryption, err := keyshare.NewEncryption("", "")
+
+	s.Nil(err)
+	s.Nil(encryption)
+}
+
+func (s *EncryptionTestSuite) Test_NewEncryption_PassphraseAndKeyFile() {
+	_, err := keyshare.NewEncryption("passphrase", s.keyPath)
+
+	s.NotNil(err)
+}
+
+type EncryptedKeyshareStoreTestSuite struct {
+	suite.Suite
+	path       string
+	encryption *keyshare.Encryption
+	keyshare   keyshare.Keyshare
+}
+
+func TestRunEncryptedKeyshareStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptedKeyshareStoreTestSuite))
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) SetupTest() {
+	s.path = "encrypted-share.json"
+	s.encryption, _ = keyshare.NewPassphraseEncryption("passphrase")
+	peer1, _ := peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	s.keyshare = keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 3, []peer.ID{peer1})
+}
+func (s *EncryptedKeyshareStoreTestSuite) TearDownTest() {
+	os.Remove(s.path)
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) Test_StoreAndRetrieveShare() {
+	store := keyshare.NewEncryptedKeyshareStore(s.path, s.encryption)
+
+	err := store.StoreKeyshare(s.keyshare, "keygen")
+	s.Nil(err)
+
+	storedKeyshare, err := store.GetKeyshare()
+	s.Nil(err)
+	s.Equal(s.keyshare, storedKeyshare)
+	info, _ := os.Stat(s.path)
+	s.Equal(os.FileMode(0600), info.Mode().Perm())
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) Test_RetrieveWithoutKey() {
+	err := keyshare.NewEncryptedKeyshareStore(s.path, s.encryption).StoreKeyshare(s.keyshare, "keygen")
+	s.Nil(err)
+
+	_, err = keyshare.NewKeyshareStore(s.path).GetKeyshare()
+
+	s.True(errors.Is(err, keyshare.ErrKeyshareEncrypted))
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) Test_RetrieveWithInvalidKey() {
+	err := keyshare.NewEncryptedKeyshareStore(s.path, s.encryption).StoreKeyshare(s.keyshare, "keygen")
+	s.Nil(err)
+
+	invalidEncryption, _ := keyshare.NewPassphraseEncryption("invalid")
+	_, err = keyshare.NewEncryptedKeyshareStore(s.path, invalidEncryption).GetKeyshare()
+
+	s.True(errors.Is(err, keyshare.ErrInvalidEncryptionKey))
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) Test_Migrate_EncryptsPlaintextShare() {
+	err := keyshare.NewKeyshareStore(s.path).StoreKeyshare(s.keyshare, "keygen")
+	s.Nil(err)
+	store := keyshare.NewEncryptedKeyshareStore(s.path, s.encryption)
+
+	migrated, err := store.Migrate()
+	s.Nil(err)
+	s.True(migrated)
+	migrated, err = store.Migrate()
+	s.Nil(err)
+	s.False(migrated)
+
+	_, err = keyshare.NewKeyshareStore(s.path).GetKeyshare()
+	s.True(errors.Is(err, keyshare.ErrKeyshareEncrypted))
+	storedKeyshare, err := store.GetKeyshare()
+	s.Nil(err)
+	s.Equal(s.keyshare, storedKeyshare)
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) Test_Migrate_MissingFile() {
+	migrated, err := keyshare.NewEncryptedKeyshareStore(s.path, s.encryption).Migrate()
+
+	s.Nil(err)
+	s.False(migrated)
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) Test_Reencrypt_NewKey() {
+	store := keyshare.NewEncryptedKeyshareStore(s.path, s.encryption)
+	err := store.StoreKeyshare(s.keyshare, "keygen")
+	s.Nil(err)
+	newEncryption, _ := keyshare.NewPassphraseEncryption("new-passphrase")
+
+	err = store.Reencrypt(newEncryption)
+	s.Nil(err)
+
+	_, err = keyshare.NewEncryptedKeyshareStore(s.path, s.encryption).GetKeyshare()
+	s.True(errors.Is(err, keyshare.ErrInvalidEncryptionKey))
+	storedKeyshare, err := keyshare.NewEncryptedKeyshareStore(s.path, newEncryption).GetKeyshare()
+	s.Nil(err)
+	s.Equal(s.keyshare, storedKeyshare)
+}
+
+func (s *EncryptedKeyshareStoreTestSuite) Test_Reencrypt_Decrypt() {
+	store := keyshare.NewEncryptedKeyshareStore(s.path, s.encryption)
+	err := store.StoreKeyshare(s.keyshare, "keygen")
+	s.Nil(err)
+
+	err = store.Reencrypt(nil)
+	s.Nil(err)
+
+	storedKeyshare, err := keyshare.NewKeyshareStore(s.path).GetKeyshare()
+	s.Nil(err)
+	s.Equal(s.keyshare, storedKeyshare)
+}
@@ -0,0 +1,250 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
+)
+
+const (
+	exportVersion   = 1
+	importSessionID = "import"
+)
+
+// Info contains public data of the active keyshare
+type Info struct {
+	Curve      string    `json:"curve"`
+	PublicKey  string    `json:"publicKey"`
+	Address    string    `json:"address"`
+	Threshold  int       `json:"threshold"`
+	Peers      []peer.ID `json:"peers"`
+	PartyIndex int       `json:"partyIndex"`
+	Generation int       `json:"generation"`
+}
+
+// exportFile contains keyshare moved between relayer hosts. Checksum is the
+// sha256 hash of the keyshare and is used to detect corrupted exports.
+type exportFile struct {
+	Version  int             `json:"version"`
+	Curve    string          `json:"curve"`
+	Address  string          `json:"address"`
+	Checksum string          `json:"checksum"`
+	Keyshare json.RawMessage `json:"keyshare"`
+}
+
+type exportable interface {
+	addressable
+	PublicKey() string
+	PartyIndex() int
+	Validate() error
+}
+
+// Info returns public data of the active keyshare.
+func (ks *KeyshareStore) Info() (Info, error) {
+	return ks.info(ecdsaKey, curve.Secp256k1, &Keyshare{})
+}
+
+// Verify validates the active keyshare and checks that it is a share of the MPC
+// key with the expected address.
+func (ks *KeyshareStore) Verify(address string) error {
+	return ks.verify(ecdsaKey, curve.Secp256k1, &Keyshare{}, address)
+}
+
+// Export returns the active keyshare as an export file that is encrypted
+// with the provided encryption. Export is in plaintext if encryption is nil.
+func (ks *KeyshareStore) Export(encryption *Encryption) ([]byte, error) {
+	return ks.export(ecdsaKey, curve.Secp256k1, &Keyshare{}, encryption)
+}
+
+// Import validates keyshare from the export file and stores it as the new active
+// keyshare version. Encrypted exports are decrypted with the provided encryption.
+// If address is provided keyshare is only imported if it matches the MPC address.
+func (ks *KeyshareStore) Import(data []byte, encryption *Encryption, address string) (Keyshare, error) {
+	k := Keyshare{}
+	err := ks.importKey(ecdsaKey, curve.Secp256k1, &k, data, encryption, address)
+	if err != nil {
+		return k, err
+	}
+	return k, ks.storeKey(ecdsaKey, k, importSessionID)
+}
+
+// Info returns public data of the active EdDSA keyshare.
+func (ks *EdDSAKeyshareStore) Info() (Info, error) {
+	return ks.store.info(eddsaKey, curve.Edwards25519, &EdDSAKeyshare{})
+}
+
+// Verify validates the active EdDSA keyshare and checks that it is a share of the MPC
+// key with the expected public key.
+func (ks *EdDSAKeyshareStore) Verify(address string) error {
+	return ks.store.verify(eddsaKey, curve.Edwards25519, &EdDSAKeyshare{}, address)
+}
+
+// Export returns the active EdDSA keyshare as an export file that is encrypted
+// with the provided encryption. Export is in plaintext if encryption is nil.
+func (ks *EdDSAKeyshareStore) Export(encryption *Encryption) ([]byte, error) {
+	return ks.store.export(eddsaKey, curve.Edwards25519, &EdDSAKeyshare{}, encryption)
+}
+
+// Import validates EdDSA keyshare from the export file and stores it as the new active
+// EdDSA keyshare version. Encrypted exports are decrypted with the provided encryption.
+// If public key is provided keyshare is only imported if it matches the MPC public key.
+func (ks *EdDSAKeyshareStore) Import(data []byte, encryption *Encryption, publicKey string) (EdDSAKeyshare, error) {
+	k := EdDSAKeyshare{}
+	err := ks.store.importKey(eddsaKey, curve.Edwards25519, &k, data, encryption, publicKey)
+	if err != nil {
+		return k, err
+	}
+	return k, ks.store.storeKey(eddsaKey, k, importSessionID)
+}
+
+func (ks *KeyshareStore) info(curveKey string, c curve.Curve, keyshare exportable) (Info, error) {
+	h, err := ks.history(curveKey)
+	if err != nil {
+		return Info{}, err
+	}
+	v, err := h.active()
+	if err != nil {
+		return Info{}, err
+	}
+	err = unmarshalKey(v.Keyshare, c, keyshare)
+	if err != nil {
+		return Info{}, err
+	}
+
+	info := Info{
+		Curve:      curveKey,
+		PublicKey:  keyshare.PublicKey(),
+		Address:    keyshare.Address(),
+		PartyIndex: keyshare.PartyIndex(),
+		Generation: v.Generation,
+	}
+	switch k := keyshare.(type) {
+	case *Keyshare:
+		info.Threshold, info.Peers = k.Threshold, k.Peers
+	case *EdDSAKeyshare:
+		info.Threshold, info.Peers = k.Threshold, k.Peers
+	}
+	return info, nil
+}
+
+func (ks *KeyshareStore) verify(curveKey string, c curve.Curve, keyshare exportable, address string) error {
+	err := ks.key(curveKey, c, keyshare)
+	if err != nil {
+		return err
+	}
+
+	err = keyshare.Validate()
+	if err != nil {
+		return err
+	}
+	return matchAddress(keyshare, address)
+}
+
+func (ks *KeyshareStore) export(curveKey string, c curve.Curve, keyshare exportable, encryption *Encryption) ([]byte, error) {
+	h, err := ks.history(curveKey)
+	if err != nil {
+		return nil, err
+	}
+	v, err := h.active()
+	if err != nil {
+		return nil, err
+	}
+	err = unmarshalKey(v.Keyshare, c, keyshare)
+	if err != nil {
+		return nil, err
+	}
+
+	kb := &bytes.Buffer{}
+	err = json.Compact(kb, v.Keyshare)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(kb.Bytes())
+	data, err := json.Marshal(exportFile{
+		Version:  exportVersion,
+		Curve:    curveKey,
+		Address:  keyshare.Address(),
+		Checksum: hex.EncodeToString(checksum[:]),
+		Keyshare: kb.Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if encryption == nil {
+		return data, nil
+	}
+	return encryption.Encrypt(data)
+}
+
+// importKey decrypts the export file and unmarshals the exported keyshare
+// after checking its integrity.
+func (ks *KeyshareStore) importKey(
+	curveKey string, c curve.Curve, keyshare exportable, data []byte, encryption *Encryption, address string,
+) error {
+	var err error
+	if isEncrypted(data) {
+		if encryption == nil {
+			return ErrKeyshareEncrypted
+		}
+		data, err = encryption.Decrypt(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	export := exportFile{}
+	err = json.Unmarshal(data, &export)
+	if err != nil {
+		return fmt.Errorf("invalid keyshare export: %w", err)
+	}
+	if export.Version != exportVersion {
+		return fmt.Errorf("unsupported keyshare export version %d", export.Version)
+	}
+	if export.Curve != curveKey {
+		return fmt.Errorf("keyshare export contains %s keyshare", export.Curve)
+	}
+
+	kb := &bytes.Buffer{}
+	err = json.Compact(kb, export.Keyshare)
+	if err != nil {
+		return fmt.Errorf("invalid keyshare export: %w", err)
+	}
+	checksum := sha256.Sum256(kb.Bytes())
+	if hex.EncodeToString(checksum[:]) != export.Checksum {
+		return fmt.Errorf("keyshare export checksum mismatch")
+	}
+
+	err = unmarshalKey(kb.Bytes(), c, keyshare)
+	if err != nil {
+		return err
+	}
+	err = keyshare.Validate()
+	if err != nil {
+		return err
+	}
+	if keyshare.Address() != export.Address {
+		return fmt.Errorf("keyshare address %s does not match exported address %s", keyshare.Address(), export.Address)
+	}
+	if address == "" {
+		return nil
+	}
+	return matchAddress(keyshare, address)
+}
+
+func matchAddress(keyshare addressable, address string) error {
+	if !strings.EqualFold(strings.TrimPrefix(keyshare.Address(), "0x"), strings.TrimPrefix(address, "0x")) {
+		return fmt.Errorf("keyshare address %s does not match expected address %s", keyshare.Address(), address)
+	}
+	return nil
+}
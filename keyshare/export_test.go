@@ -0,0 +1,185 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportTestSuite struct {
+	suite.Suite
+	dir        string
+	stores     []*keyshare.KeyshareStore
+	eddsaStore *keyshare.KeyshareStore
+	target     *keyshare.KeyshareStore
+}
+
+func TestRunExportTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportTestSuite))
+}
+
+func (s *ExportTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.stores = []*keyshare.KeyshareStore{}
+	for i := 0; i < 3; i++ {
+		s.stores = append(s.stores, keyshare.NewKeyshareStore(s.copyKeyshare(fmt.Sprintf("../tss/test/keyshares/%d.keyshare", i))))
+	}
+	s.eddsaStore = keyshare.NewKeyshareStore(s.copyKeyshare("../tss/test/keyshares/eddsa/0.keyshare"))
+	s.target = keyshare.NewKeyshareStore(fmt.Sprintf("%s/target.keyshare", s.dir))
+}
+
+func (s *ExportTestSuite) copyKeyshare(path string) string {
+	kb, err := ioutil.ReadFile(path)
+	s.Nil(err)
+	target := fmt.Sprintf("%s/%d.keyshare", s.dir, len(s.stores))
+	if strings.Contains(path, "eddsa") {
+		target = fmt.Sprintf("%s/eddsa.keyshare", s.dir)
+	}
+	err = ioutil.WriteFile(target, kb, 0600)
+	s.Nil(err)
+	return target
+}
+
+func (s *ExportTestSuite) Test_Info() {
+	info, err := s.stores[1].Info()
+	s.Nil(err)
+
+	key, _ := s.stores[1].GetKeyshare()
+	s.Equal("ecdsa", info.Curve)
+	s.Equal(key.Address(), info.Address)
+	s.Equal(key.PublicKey(), info.PublicKey)
+	s.Equal(66, len(info.PublicKey))
+	s.Equal(key.Threshold, info.Threshold)
+	s.Equal(key.Peers, info.Peers)
+	s.Equal(1, info.Generation)
+	s.NotEqual(-1, info.PartyIndex)
+
+	otherInfo, _ := s.stores[0].Info()
+	s.Equal(info.Address, otherInfo.Address)
+	s.NotEqual(info.PartyIndex, otherInfo.PartyIndex)
+}
+
+func (s *ExportTestSuite) Test_Info_EdDSA() {
+	info, err := s.eddsaStore.EdDSAStore().Info()
+	s.Nil(err)
+
+	s.Equal("eddsa", info.Curve)
+	s.Equal(info.Address, info.PublicKey)
+	s.NotEqual("", info.PublicKey)
+	s.NotEqual(-1, info.PartyIndex)
+}
+
+func (s *ExportTestSuite) Test_Verify_ValidAddress() {
+	info, _ := s.stores[0].Info()
+
+	err := s.stores[0].Verify(strings.ToLower(info.Address))
+
+	s.Nil(err)
+}
+
+func (s *ExportTestSuite) Test_Verify_InvalidAddress() {
+	err := s.stores[0].Verify("0x0000000000000000000000000000000000000000")
+
+	s.NotNil(err)
+}
+
+func (s *ExportTestSuite) Test_Verify_InvalidShare() {
+	key, _ := s.stores[0].GetKeyshare()
+	key.Key.Xi.Add(key.Key.Xi, key.Key.Xi)
+	_ = s.stores[0].StoreKeyshare(key, "keygen")
+
+	err := s.stores[0].Verify(key.Address())
+
+	s.NotNil(err)
+}
+
+func (s *ExportTestSuite) Test_ExportImport() {
+	data, err := s.stores[0].Export(nil)
+	s.Nil(err)
+
+	imported, err := s.target.Import(data, nil, "")
+	s.Nil(err)
+
+	key, _ := s.stores[0].GetKeyshare()
+	storedKey, err := s.target.GetKeyshare()
+	s.Nil(err)
+	s.Equal(key, imported)
+	s.Equal(key, storedKey)
+	versions, _ := s.target.Versions()
+	s.Equal("import", versions[0].SessionID)
+}
+
+func (s *ExportTestSuite) Test_ExportImport_EdDSA() {
+	data, err := s.eddsaStore.EdDSAStore().Export(nil)
+	s.Nil(err)
+
+	_, err = s.target.Import(data, nil, "")
+	s.NotNil(err)
+	_, err = s.target.EdDSAStore().Import(data, nil, "")
+	s.Nil(err)
+
+	key, _ := s.eddsaStore.EdDSAStore().GetKeyshare()
+	storedKey, err := s.target.EdDSAStore().GetKeyshare()
+	s.Nil(err)
+	s.Equal(key, storedKey)
+}
+
+func (s *ExportTestSuite) Test_ExportImport_Encrypted() {
+	encryption, _ := keyshare.NewPassphraseEncryption("passphrase")
+	data, err := s.stores[0].Export(encryption)
+	s.Nil(err)
+	s.NotContains(string(data), "Threshold")
+
+	_, err = s.target.Import(data, nil, "")
+	s.Equal(keyshare.ErrKeyshareEncrypted, err)
+
+	invalidEncryption, _ := keyshare.NewPassphraseEncryption("invalid")
+	_, err = s.target.Import(data, invalidEncryption, "")
+	s.True(errors.Is(err, keyshare.ErrInvalidEncryptionKey))
+
+	decryption, _ := keyshare.NewPassphraseEncryption("passphrase")
+	_, err = s.target.Import(data, decryption, "")
+	s.Nil(err)
+}
+
+func (s *ExportTestSuite) Test_Import_UnexpectedAddress() {
+	data, _ := s.stores[0].Export(nil)
+
+	_, err := s.target.Import(data, nil, "0x0000000000000000000000000000000000000000")
+
+	s.NotNil(err)
+	_, err = s.target.GetKeyshare()
+	s.NotNil(err)
+}
+
+func (s *ExportTestSuite) Test_Import_ExpectedAddress() {
+	data, _ := s.stores[0].Export(nil)
+	key, _ := s.stores[0].GetKeyshare()
+
+	_, err := s.target.Import(data, nil, key.Address())
+
+	s.Nil(err)
+}
+
+func (s *ExportTestSuite) Test_Import_ChecksumMismatch() {
+	data, _ := s.stores[0].Export(nil)
+	export := make(map[string]interface{})
+	_ = json.Unmarshal(data, &export)
+	export["keyshare"].(map[string]interface{})["Threshold"] = 2
+	data, _ = json.Marshal(export)
+
+	_, err := s.target.Import(data, nil, "")
+
+	s.NotNil(err)
+	_, err = s.target.GetKeyshare()
+	s.NotNil(err)
+}
@@ -0,0 +1,143 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Version describes a keyshare generated by keygen or resharing
+// that is kept in the keyshare history.
+type Version struct {
+	Generation int       `json:"generation"`
+	Threshold  int       `json:"threshold"`
+	Peers      []peer.ID `json:"peers"`
+	Address    string    `json:"address"`
+	CreatedAt  time.Time `json:"createdAt"`
+	SessionID  string    `json:"sessionID"`
+	Active     bool      `json:"-"`
+}
+
+type storedVersion struct {
+	Version
+	Keyshare json.RawMessage `json:"keyshare"`
+}
+
+// history contains all stored keyshare versions of a curve and
+// the generation of the keyshare currently in use.
+type history struct {
+	Active   int              `json:"active"`
+	Versions []*storedVersion `json:"versions"`
+}
+
+// parseHistory parses keyshare history of a curve. Keyshares stored before
+// versioning was introduced are parsed as the first generation.
+func parseHistory(raw json.RawMessage) (*history, error) {
+	h := &history{}
+	err := json.Unmarshal(raw, h)
+	if err != nil {
+		return nil, err
+	}
+	if h.Versions != nil {
+		return h, nil
+	}
+
+	legacy := struct {
+		Threshold int
+		Peers     []peer.ID
+	}{}
+	err = json.Unmarshal(raw, &legacy)
+	if err != nil {
+		return nil, err
+	}
+	return &history{
+		Active: 1,
+		Versions: []*storedVersion{
+			{
+				Version: Version{
+					Generation: 1,
+					Threshold:  legacy.Threshold,
+					Peers:      legacy.Peers,
+				},
+				Keyshare: raw,
+			},
+		},
+	}, nil
+}
+
+// add stores the keyshare as the newest generation and activates it.
+func (h *history) add(version Version, keyshare json.RawMessage) {
+	version.Generation = 1
+	if len(h.Versions) > 0 {
+		version.Generation = h.Versions[len(h.Versions)-1].Generation + 1
+	}
+
+	h.Versions = append(h.Versions, &storedVersion{
+		Version:  version,
+		Keyshare: keyshare,
+	})
+	h.Active = version.Generation
+}
+
+func (h *history) active() (*storedVersion, error) {
+	return h.version(h.Active)
+}
+
+func (h *history) version(generation int) (*storedVersion, error) {
+	for _, v := range h.Versions {
+		if v.Generation == generation {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("keyshare generation %d not found", generation)
+}
+
+func (h *history) activate(generation int) error {
+	_, err := h.version(generation)
+	if err != nil {
+		return err
+	}
+
+	h.Active = generation
+	return nil
+}
+
+// prune removes all but the newest keep versions. Active version is never removed.
+// Returns generations of the removed versions.
+func (h *history) prune(keep int) ([]int, error) {
+	if keep < 1 {
+		return nil, errors.New("at least one keyshare version has to be kept")
+	}
+	if len(h.Versions) == 0 {
+		return nil, errors.New("no keyshare versions stored")
+	}
+
+	pruned := []int{}
+	versions := []*storedVersion{}
+	for i, v := range h.Versions {
+		if i < len(h.Versions)-keep && v.Generation != h.Active {
+			pruned = append(pruned, v.Generation)
+			continue
+		}
+
+		versions = append(versions, v)
+	}
+	h.Versions = versions
+	return pruned, nil
+}
+
+func (h *history) versions() []Version {
+	versions := make([]Version, len(h.Versions))
+	for i, v := range h.Versions {
+		versions[i] = v.Version
+		versions[i].Active = v.Generation == h.Active
+	}
+	return versions
+}
@@ -4,14 +4,28 @@
 package keyshare
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"os"
+	"math/big"
 	"sync"
+	"time"
 
+	tssCrypto "github.com/binance-chain/tss-lib/crypto"
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	eddsaKeygen "github.com/binance-chain/tss-lib/eddsa/keygen"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
+)
+
+const (
+	ecdsaKey = "ecdsa"
+	eddsaKey = "eddsa"
 )
 
 // Keyshare stores key received from keygen or resharing
@@ -30,15 +44,148 @@ func NewKeyshare(key keygen.LocalPartySaveData, threshold int, peers []peer.ID)
 	}
 }
 
+// Address returns the ethereum address of the MPC key
+func (k Keyshare) Address() string {
+	if k.Key.ECDSAPub == nil {
+		return ""
+	}
+
+	return crypto.PubkeyToAddress(*k.Key.ECDSAPub.ToECDSAPubKey()).Hex()
+}
+
+// PublicKey returns the hex encoded compressed MPC public key
+func (k Keyshare) PublicKey() string {
+	if k.Key.ECDSAPub == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(crypto.CompressPubkey(k.Key.ECDSAPub.ToECDSAPubKey()))
+}
+
+// PartyIndex returns index of the relayer share in the MPC key or -1 if it is not found
+func (k Keyshare) PartyIndex() int {
+	return partyIndex(k.Key.ShareID, k.Key.Ks)
+}
+
+// Validate checks that the keyshare contains the MPC public key and that the
+// local secret share matches its public share.
+func (k Keyshare) Validate() error {
+	if k.Key.ECDSAPub == nil {
+		return errors.New("keyshare public key missing")
+	}
+	return validateShare(curve.Secp256k1, k.Key.Xi, k.PartyIndex(), k.Key.BigXj, k.Threshold, k.Peers)
+}
+
+// EdDSAKeyshare stores EdDSA key received from keygen or resharing
+// and treshold and peers from current signing committee
+type EdDSAKeyshare struct {
+	Key       eddsaKeygen.LocalPartySaveData
+	Threshold int
+	Peers     []peer.ID
+}
+
+func NewEdDSAKeyshare(key eddsaKeygen.LocalPartySaveData, threshold int, peers []peer.ID) EdDSAKeyshare {
+	return EdDSAKeyshare{
+		Key:       key,
+		Threshold: threshold,
+		Peers:     peers,
+	}
+}
+
+// Address returns the hex encoded MPC public key
+func (k EdDSAKeyshare) Address() string {
+	if k.Key.EDDSAPub == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(edwards.NewPublicKey(k.Key.EDDSAPub.X(), k.Key.EDDSAPub.Y()).SerializeCompressed())
+}
+
+// PublicKey returns the hex encoded compressed MPC public key
+func (k EdDSAKeyshare) PublicKey() string {
+	return k.Address()
+}
+
+// PartyIndex returns index of the relayer share in the MPC key or -1 if it is not found
+func (k EdDSAKeyshare) PartyIndex() int {
+	return partyIndex(k.Key.ShareID, k.Key.Ks)
+}
+
+// Validate checks that the keyshare contains the MPC public key and that the
+// local secret share matches its public share.
+func (k EdDSAKeyshare) Validate() error {
+	if k.Key.EDDSAPub == nil {
+		return errors.New("keyshare public key missing")
+	}
+	return validateShare(curve.Edwards25519, k.Key.Xi, k.PartyIndex(), k.Key.BigXj, k.Threshold, k.Peers)
+}
+
+type addressable interface {
+	Address() string
+}
+
+func partyIndex(shareID *big.Int, ks []*big.Int) int {
+	if shareID == nil {
+		return -1
+	}
+	for i, k := range ks {
+		if k != nil && k.Cmp(shareID) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateShare(c curve.Curve, xi *big.Int, index int, bigXj []*tssCrypto.ECPoint, threshold int, peers []peer.ID) error {
+	if threshold < 0 || threshold >= len(peers) {
+		return fmt.Errorf("invalid threshold %d for %d peers", threshold, len(peers))
+	}
+	if xi == nil || index < 0 || index >= len(bigXj) {
+		return errors.New("keyshare secret share missing")
+	}
+	if !tssCrypto.ScalarBaseMult(c.EC(), xi).Equals(bigXj[index]) {
+		return errors.New("keyshare secret share does not match its public share")
+	}
+	return nil
+}
+
+// KeyshareStore stores the keyshare history per curve into a single backend entry.
+// Keys are stored under the curve name, files containing only the ECDSA keyshare
+// from before EdDSA support are still readable.
+// If encryption is provided the keyshare is encrypted at rest.
 type KeyshareStore struct {
-	mu   sync.Mutex
-	path string
+	mu         sync.Mutex
+	fileMu     sync.Mutex
+	backend    Backend
+	encryption *Encryption
+	eddsa      *EdDSAKeyshareStore
 }
 
 func NewKeyshareStore(filePath string) *KeyshareStore {
-	return &KeyshareStore{
-		path: filePath,
+	return NewEncryptedKeyshareStore(filePath, nil)
+}
+
+// NewEncryptedKeyshareStore creates keyshare store which encrypts the keyshare file
+// with the provided encryption. Keyshares are stored in plaintext if encryption is nil.
+func NewEncryptedKeyshareStore(filePath string, encryption *Encryption) *KeyshareStore {
+	return NewKeyshareStoreWithBackend(NewFileBackend(filePath), encryption)
+}
+
+// NewKeyshareStoreWithBackend creates keyshare store which persists keyshares
+// with the provided backend.
+func NewKeyshareStoreWithBackend(backend Backend, encryption *Encryption) *KeyshareStore {
+	ks := &KeyshareStore{
+		backend:    backend,
+		encryption: encryption,
 	}
+	ks.eddsa = &EdDSAKeyshareStore{store: ks}
+	return ks
+}
+
+// EdDSAStore returns store for the EdDSA keyshare persisted in the same file
+// which can be locked independently of the ECDSA keyshare.
+func (ks *KeyshareStore) EdDSAStore() *EdDSAKeyshareStore {
+	return ks.eddsa
 }
 
 // LockKeyshare locks keyshare from reading and writing to
@@ -53,38 +200,320 @@ func (ks *KeyshareStore) UnlockKeyshare() {
 	ks.mu.Unlock()
 }
 
-// StoreKeyshare stores keyshare generated by keygen or reshare into file and truncates
-// old keyshare.
-func (ks *KeyshareStore) StoreKeyshare(keyshare Keyshare) error {
-	f, err := os.OpenFile(ks.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+// StoreKeyshare stores keyshare generated by keygen or reshare of the session as the
+// new active keyshare version. Previous keyshares are kept in the keyshare history.
+func (ks *KeyshareStore) StoreKeyshare(keyshare Keyshare, sessionID string) error {
+	return ks.storeKey(ecdsaKey, keyshare, sessionID)
+}
+
+// GetKeyshare fetches current keyshare from file.
+// Can be a blocking call if keygen or resharing are pending.
+func (ks *KeyshareStore) GetKeyshare() (Keyshare, error) {
+	k := Keyshare{}
+	err := ks.key(ecdsaKey, curve.Secp256k1, &k)
+	return k, err
+}
+
+// Versions returns all stored keyshare versions ordered by generation.
+func (ks *KeyshareStore) Versions() ([]Version, error) {
+	return ks.versions(ecdsaKey, curve.Secp256k1, func() addressable { return &Keyshare{} })
+}
+
+// ActivateVersion makes keyshare of the provided generation the current keyshare.
+func (ks *KeyshareStore) ActivateVersion(generation int) error {
+	return ks.updateHistory(ecdsaKey, func(h *history) error { return h.activate(generation) })
+}
+
+// PruneVersions removes all but the newest keep keyshare versions, the active
+// keyshare version is always kept. Returns generations of removed versions.
+func (ks *KeyshareStore) PruneVersions(keep int) ([]int, error) {
+	var pruned []int
+	err := ks.updateHistory(ecdsaKey, func(h *history) error {
+		var err error
+		pruned, err = h.prune(keep)
+		return err
+	})
+	return pruned, err
+}
+
+func (ks *KeyshareStore) storeKey(curveKey string, keyshare addressable, sessionID string) error {
+	kb, err := json.Marshal(keyshare)
+	if err != nil {
+		return err
+	}
+
+	version := Version{
+		Address:   keyshare.Address(),
+		CreatedAt: time.Now().UTC(),
+		SessionID: sessionID,
+	}
+	switch k := keyshare.(type) {
+	case Keyshare:
+		version.Threshold, version.Peers = k.Threshold, k.Peers
+	case EdDSAKeyshare:
+		version.Threshold, version.Peers = k.Threshold, k.Peers
+	}
+
+	return ks.updateHistory(curveKey, func(h *history) error {
+		h.add(version, kb)
+		return nil
+	})
+}
+
+// updateHistory applies the update to the keyshare history of the curve and writes it
+// to the file. Missing keyshare file or history are created.
+func (ks *KeyshareStore) updateHistory(curveKey string, update func(h *history) error) error {
+	ks.fileMu.Lock()
+	defer ks.fileMu.Unlock()
+
+	histories, err := ks.histories()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if histories == nil {
+		histories = make(map[string]*history)
+	}
+
+	h, ok := histories[curveKey]
+	if !ok {
+		h = &history{}
+		histories[curveKey] = h
+	}
+	err = update(h)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	kb, err := json.Marshal(&keyshare)
+	fb, err := json.Marshal(histories)
 	if err != nil {
 		return err
 	}
 
-	_, err = f.Write(kb)
-	return err
+	return ks.write(fb, ks.encryption)
 }
 
-// GetKeyshare fetches current keyshare from file.
-// Can be a blocking call if keygen or resharing are pending.
-func (ks *KeyshareStore) GetKeyshare() (Keyshare, error) {
-	k := Keyshare{}
+// Migrate encrypts the plaintext keyshare file if encryption is configured.
+// Returns true if the file was encrypted.
+func (ks *KeyshareStore) Migrate() (bool, error) {
+	ks.fileMu.Lock()
+	defer ks.fileMu.Unlock()
+
+	if ks.encryption == nil {
+		return false, nil
+	}
+
+	kb, err := ks.backend.Read()
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if isEncrypted(kb) {
+		return false, nil
+	}
+
+	return true, ks.write(kb, ks.encryption)
+}
+
+// Reencrypt rewrites the keyshare file with the new encryption and uses it for
+// all following reads and writes. Keyshare file is decrypted to plaintext if
+// the new encryption is nil.
+func (ks *KeyshareStore) Reencrypt(encryption *Encryption) error {
+	ks.fileMu.Lock()
+	defer ks.fileMu.Unlock()
+
+	kb, err := ks.read()
+	if err != nil {
+		return err
+	}
+
+	err = ks.write(kb, encryption)
+	if err != nil {
+		return err
+	}
+
+	ks.encryption = encryption
+	return nil
+}
+
+// key unmarshals active keyshare of the provided curve.
+func (ks *KeyshareStore) key(curveKey string, c curve.Curve, keyshare interface{}) error {
+	h, err := ks.history(curveKey)
+	if err != nil {
+		return err
+	}
+
+	v, err := h.active()
+	if err != nil {
+		return err
+	}
+
+	return unmarshalKey(v.Keyshare, c, keyshare)
+}
+
+// versions returns keyshare versions of the curve. Address of keyshares stored before
+// versioning was introduced is derived from the keyshare.
+func (ks *KeyshareStore) versions(curveKey string, c curve.Curve, newKey func() addressable) ([]Version, error) {
+	h, err := ks.history(curveKey)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := h.versions()
+	for i, v := range h.Versions {
+		if v.Address != "" {
+			continue
+		}
+
+		key := newKey()
+		err = unmarshalKey(v.Keyshare, c, key)
+		if err != nil {
+			return nil, err
+		}
+		versions[i].Address = key.Address()
+	}
+	return versions, nil
+}
+
+func (ks *KeyshareStore) history(curveKey string) (*history, error) {
+	ks.fileMu.Lock()
+	histories, err := ks.histories()
+	ks.fileMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("error on reading keyshare file: %w", err)
+	}
+
+	h, ok := histories[curveKey]
+	if !ok {
+		return nil, fmt.Errorf("%s keyshare not found", curveKey)
+	}
+	return h, nil
+}
 
-	kb, err := ioutil.ReadFile(ks.path)
+// histories reads keyshare histories from the file by curve
+func (ks *KeyshareStore) histories() (map[string]*history, error) {
+	kb, err := ks.read()
 	if err != nil {
-		return k, fmt.Errorf("error on reading keyshare file: %s", err)
+		return nil, err
 	}
 
-	err = json.Unmarshal(kb, &k)
+	keys := make(map[string]json.RawMessage)
+	err = json.Unmarshal(kb, &keys)
 	if err != nil {
-		return k, fmt.Errorf("error on unmarshaling keyshare file: %s", err)
+		return nil, err
 	}
 
+	// keyshare file only containing the ECDSA keyshare
+	if _, ok := keys["Key"]; ok {
+		keys = map[string]json.RawMessage{ecdsaKey: kb}
+	}
+
+	histories := make(map[string]*history)
+	for curveKey, raw := range keys {
+		histories[curveKey], err = parseHistory(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return histories, nil
+}
+
+// unmarshalKey unmarshals keyshare of the provided curve. Unmarshaling key points depends on
+// the curve used by tss-lib so it is acquired for the duration of unmarshaling.
+func unmarshalKey(kb []byte, c curve.Curve, keyshare interface{}) error {
+	err := curve.Acquire(context.Background(), c)
+	if err != nil {
+		return err
+	}
+	defer curve.Release()
+
+	err = json.Unmarshal(kb, keyshare)
+	if err != nil {
+		return fmt.Errorf("error on unmarshaling keyshare file: %s", err)
+	}
+
+	return nil
+}
+
+// read reads the keyshare file and decrypts it if it is encrypted
+func (ks *KeyshareStore) read() ([]byte, error) {
+	kb, err := ks.backend.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEncrypted(kb) {
+		return kb, nil
+	}
+	if ks.encryption == nil {
+		return nil, ErrKeyshareEncrypted
+	}
+	return ks.encryption.Decrypt(kb)
+}
+
+// write encrypts the keyshare file content if encryption is provided and writes
+// it to the backend.
+func (ks *KeyshareStore) write(kb []byte, encryption *Encryption) error {
+	var err error
+	if encryption != nil {
+		kb, err = encryption.Encrypt(kb)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ks.backend.Write(kb)
+}
+
+type EdDSAKeyshareStore struct {
+	mu    sync.Mutex
+	store *KeyshareStore
+}
+
+// LockKeyshare locks EdDSA keyshare from reading and writing to
+// prevent keygen or resharing being done in parallel with other
+// tss processes.
+func (ks *EdDSAKeyshareStore) LockKeyshare() {
+	ks.mu.Lock()
+}
+
+// UnlockKeyshare unlocks EdDSA keyshare to allow for tss processes to continue
+func (ks *EdDSAKeyshareStore) UnlockKeyshare() {
+	ks.mu.Unlock()
+}
+
+// StoreKeyshare stores EdDSA keyshare generated by keygen or reshare of the session as
+// the new active EdDSA keyshare version.
+func (ks *EdDSAKeyshareStore) StoreKeyshare(keyshare EdDSAKeyshare, sessionID string) error {
+	return ks.store.storeKey(eddsaKey, keyshare, sessionID)
+}
+
+// GetKeyshare fetches current EdDSA keyshare from file.
+func (ks *EdDSAKeyshareStore) GetKeyshare() (EdDSAKeyshare, error) {
+	k := EdDSAKeyshare{}
+	err := ks.store.key(eddsaKey, curve.Edwards25519, &k)
 	return k, err
 }
+
+// Versions returns all stored EdDSA keyshare versions ordered by generation.
+func (ks *EdDSAKeyshareStore) Versions() ([]Version, error) {
+	return ks.store.versions(eddsaKey, curve.Edwards25519, func() addressable { return &EdDSAKeyshare{} })
+}
+
+// ActivateVersion makes EdDSA keyshare of the provided generation the current EdDSA keyshare.
+func (ks *EdDSAKeyshareStore) ActivateVersion(generation int) error {
+	return ks.store.updateHistory(eddsaKey, func(h *history) error { return h.activate(generation) })
+}
+
+// PruneVersions removes all but the newest keep EdDSA keyshare versions, the active
+// version is always kept. Returns generations of removed versions.
+func (ks *EdDSAKeyshareStore) PruneVersions(keep int) ([]int, error) {
+	var pruned []int
+	err := ks.store.updateHistory(eddsaKey, func(h *history) error {
+		var err error
+		pruned, err = h.prune(keep)
+		return err
+	})
+	return pruned, err
+}
@@ -4,11 +4,13 @@
 package keyshare_test
 
 import (
+	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	eddsaKeygen "github.com/binance-chain/tss-lib/eddsa/keygen"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/suite"
 )
@@ -45,7 +47,7 @@ func (s *KeyshareStoreTestSuite) Test_StoreAndRetrieveShare() {
 	peers := []peer.ID{peer1, peer2}
 	keyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), threshold, peers)
 
-	err := s.keyshareStore.StoreKeyshare(keyshare)
+	err := s.keyshareStore.StoreKeyshare(keyshare, "keygen")
 	s.Nil(err)
 
 	storedKeyshare, err := s.keyshareStore.GetKeyshare()
@@ -53,3 +55,158 @@ func (s *KeyshareStoreTestSuite) Test_StoreAndRetrieveShare() {
 
 	s.Equal(keyshare, storedKeyshare)
 }
+
+func (s *KeyshareStoreTestSuite) Test_RetrieveMissingEdDSAShare() {
+	keyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 3, []peer.ID{})
+	err := s.keyshareStore.StoreKeyshare(keyshare, "keygen")
+	s.Nil(err)
+
+	_, err = s.keyshareStore.EdDSAStore().GetKeyshare()
+	s.NotNil(err)
+}
+
+func (s *KeyshareStoreTestSuite) Test_StoreAndRetrieveSharesForBothCurves() {
+	peer1, _ := peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	peer2, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	peers := []peer.ID{peer1, peer2}
+	ecdsaKeyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 3, peers)
+	eddsaKeyshare := keyshare.NewEdDSAKeyshare(eddsaKeygen.NewLocalPartySaveData(5), 2, peers)
+
+	err := s.keyshareStore.StoreKeyshare(ecdsaKeyshare, "keygen")
+	s.Nil(err)
+	err = s.keyshareStore.EdDSAStore().StoreKeyshare(eddsaKeyshare, "keygen")
+	s.Nil(err)
+
+	storedKeyshare, err := s.keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(ecdsaKeyshare, storedKeyshare)
+	storedEdDSAKeyshare, err := s.keyshareStore.EdDSAStore().GetKeyshare()
+	s.Nil(err)
+	s.Equal(eddsaKeyshare, storedEdDSAKeyshare)
+}
+
+func (s *KeyshareStoreTestSuite) Test_RetrieveLegacyShare() {
+	kb, err := ioutil.ReadFile("../tss/test/keyshares/0.keyshare")
+	s.Nil(err)
+	err = ioutil.WriteFile(s.path, kb, 0644)
+	s.Nil(err)
+
+	storedKeyshare, err := s.keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(1, storedKeyshare.Threshold)
+
+	eddsaKeyshare := keyshare.NewEdDSAKeyshare(eddsaKeygen.NewLocalPartySaveData(5), 2, []peer.ID{})
+	err = s.keyshareStore.EdDSAStore().StoreKeyshare(eddsaKeyshare, "keygen")
+	s.Nil(err)
+
+	keyshareAfterUpdate, err := s.keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(storedKeyshare, keyshareAfterUpdate)
+}
+
+func (s *KeyshareStoreTestSuite) Test_StoreKeepsHistory() {
+	peer1, _ := peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	peer2, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	firstKeyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 1, []peer.ID{peer1})
+	secondKeyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 2, []peer.ID{peer1, peer2})
+
+	err := s.keyshareStore.StoreKeyshare(firstKeyshare, "keygen")
+	s.Nil(err)
+	err = s.keyshareStore.StoreKeyshare(secondKeyshare, "resharing")
+	s.Nil(err)
+
+	storedKeyshare, err := s.keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(secondKeyshare, storedKeyshare)
+	versions, err := s.keyshareStore.Versions()
+	s.Nil(err)
+	s.Equal(2, len(versions))
+	s.Equal(1, versions[0].Generation)
+	s.Equal("keygen", versions[0].SessionID)
+	s.Equal(1, versions[0].Threshold)
+	s.False(versions[0].Active)
+	s.Equal(2, versions[1].Generation)
+	s.Equal("resharing", versions[1].SessionID)
+	s.Equal([]peer.ID{peer1, peer2}, versions[1].Peers)
+	s.True(versions[1].Active)
+	s.False(versions[1].CreatedAt.IsZero())
+}
+
+func (s *KeyshareStoreTestSuite) Test_ActivateVersion() {
+	firstKeyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 1, []peer.ID{})
+	secondKeyshare := keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 2, []peer.ID{})
+	_ = s.keyshareStore.StoreKeyshare(firstKeyshare, "keygen")
+	_ = s.keyshareStore.StoreKeyshare(secondKeyshare, "resharing")
+
+	err := s.keyshareStore.ActivateVersion(1)
+	s.Nil(err)
+
+	storedKeyshare, err := s.keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(firstKeyshare, storedKeyshare)
+	err = s.keyshareStore.ActivateVersion(3)
+	s.NotNil(err)
+}
+
+func (s *KeyshareStoreTestSuite) Test_StoreAfterRollback_NewGeneration() {
+	_ = s.keyshareStore.StoreKeyshare(keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 1, []peer.ID{}), "keygen")
+	_ = s.keyshareStore.StoreKeyshare(keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 2, []peer.ID{}), "resharing1")
+	_ = s.keyshareStore.ActivateVersion(1)
+
+	err := s.keyshareStore.StoreKeyshare(keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 3, []peer.ID{}), "resharing2")
+	s.Nil(err)
+
+	versions, _ := s.keyshareStore.Versions()
+	s.Equal(3, versions[2].Generation)
+	s.True(versions[2].Active)
+}
+
+func (s *KeyshareStoreTestSuite) Test_PruneVersions_KeepsActive() {
+	for i := 1; i <= 4; i++ {
+		_ = s.keyshareStore.StoreKeyshare(keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), i, []peer.ID{}), "keygen")
+	}
+	_ = s.keyshareStore.ActivateVersion(1)
+
+	pruned, err := s.keyshareStore.PruneVersions(2)
+	s.Nil(err)
+
+	s.Equal([]int{2}, pruned)
+	versions, _ := s.keyshareStore.Versions()
+	s.Equal(3, len(versions))
+	s.Equal(1, versions[0].Generation)
+	s.True(versions[0].Active)
+	storedKeyshare, _ := s.keyshareStore.GetKeyshare()
+	s.Equal(1, storedKeyshare.Threshold)
+}
+
+func (s *KeyshareStoreTestSuite) Test_PruneVersions_InvalidKeep() {
+	_ = s.keyshareStore.StoreKeyshare(keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 1, []peer.ID{}), "keygen")
+
+	_, err := s.keyshareStore.PruneVersions(0)
+
+	s.NotNil(err)
+}
+
+func (s *KeyshareStoreTestSuite) Test_LegacyShareVersions() {
+	kb, err := ioutil.ReadFile("../tss/test/keyshares/0.keyshare")
+	s.Nil(err)
+	err = ioutil.WriteFile(s.path, kb, 0644)
+	s.Nil(err)
+
+	versions, err := s.keyshareStore.Versions()
+	s.Nil(err)
+
+	s.Equal(1, len(versions))
+	s.Equal(1, versions[0].Generation)
+	s.Equal(1, versions[0].Threshold)
+	s.True(versions[0].Active)
+	s.NotEqual("", versions[0].Address)
+
+	err = s.keyshareStore.StoreKeyshare(keyshare.NewKeyshare(keygen.NewLocalPartySaveData(5), 2, []peer.ID{}), "resharing")
+	s.Nil(err)
+	err = s.keyshareStore.ActivateVersion(1)
+	s.Nil(err)
+	storedKeyshare, err := s.keyshareStore.GetKeyshare()
+	s.Nil(err)
+	s.Equal(versions[0].Address, storedKeyshare.Address())
+}
@@ -0,0 +1,40 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare
+
+import (
+	"errors"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const lvldbKeyshareKey = "tss:keyshare"
+
+type KeyValueReaderWriter interface {
+	GetByKey(key []byte) ([]byte, error)
+	SetByKey(key []byte, value []byte) error
+}
+
+// LvlDBBackend stores keyshares into the relayer database.
+type LvlDBBackend struct {
+	db KeyValueReaderWriter
+}
+
+func NewLvlDBBackend(db KeyValueReaderWriter) *LvlDBBackend {
+	return &LvlDBBackend{
+		db: db,
+	}
+}
+
+func (b *LvlDBBackend) Read() ([]byte, error) {
+	kb, err := b.db.GetByKey([]byte(lvldbKeyshareKey))
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return kb, err
+}
+
+func (b *LvlDBBackend) Write(data []byte) error {
+	return b.db.SetByKey([]byte(lvldbKeyshareKey), data)
+}
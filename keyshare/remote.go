@@ -0,0 +1,82 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keyshare
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const remoteTimeout = 30 * time.Second
+
+// RemoteBackend stores keyshares in a remote secret store. Keyshare is read with a GET
+// and written with a PUT request to the secret URL, requests are authorized with the
+// bearer token if it is provided.
+type RemoteBackend struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewRemoteBackend(url string, token string) *RemoteBackend {
+	return &RemoteBackend{
+		url:   url,
+		token: token,
+		client: &http.Client{
+			Timeout: remoteTimeout,
+		},
+	}
+}
+
+func (b *RemoteBackend) Read() ([]byte, error) {
+	resp, err := b.do(http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("secret store responded with status %d: %s", resp.StatusCode, body)
+	}
+}
+
+func (b *RemoteBackend) Write(data []byte) error {
+	resp, err := b.do(http.MethodPut, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("secret store responded with status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (b *RemoteBackend) do(method string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, b.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if b.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.token))
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	return b.client.Do(req)
+}
@@ -0,0 +1,94 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// TopologyDiff contains changes between the current and the new network topology.
+type TopologyDiff struct {
+	Added        []peer.ID `json:"added"`
+	Removed      []peer.ID `json:"removed"`
+	Readdressed  []peer.ID `json:"readdressed"`
+	Retained     []peer.ID `json:"retained"`
+	OldThreshold int       `json:"oldThreshold"`
+	NewThreshold int       `json:"newThreshold"`
+}
+
+// NewTopologyDiff compares peers of the current and the new topology. Peers
+// present in both topologies with different addresses are reported as readdressed.
+func NewTopologyDiff(current NetworkTopology, new NetworkTopology) TopologyDiff {
+	diff := TopologyDiff{
+		Added:        []peer.ID{},
+		Removed:      []peer.ID{},
+		Readdressed:  []peer.ID{},
+		Retained:     []peer.ID{},
+		OldThreshold: current.Threshold,
+		NewThreshold: new.Threshold,
+	}
+
+	currentPeers := make(map[peer.ID]*peer.AddrInfo)
+	for _, p := range current.Peers {
+		currentPeers[p.ID] = p
+	}
+	for _, p := range new.Peers {
+		currentPeer, ok := currentPeers[p.ID]
+		if !ok {
+			diff.Added = append(diff.Added, p.ID)
+			continue
+		}
+
+		diff.Retained = append(diff.Retained, p.ID)
+		if !sameAddrs(currentPeer.Addrs, p.Addrs) {
+			diff.Readdressed = append(diff.Readdressed, p.ID)
+		}
+		delete(currentPeers, p.ID)
+	}
+	for _, p := range current.Peers {
+		if _, ok := currentPeers[p.ID]; ok {
+			diff.Removed = append(diff.Removed, p.ID)
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty returns true if the new topology contains the same peers and threshold.
+func (d TopologyDiff) IsEmpty() bool {
+	return len(d.Added) == 0 &&
+		len(d.Removed) == 0 &&
+		len(d.Readdressed) == 0 &&
+		d.OldThreshold == d.NewThreshold
+}
+
+// Union returns topology that contains peers of both topologies with the threshold of
+// the new topology. Addresses from the new topology are used for peers present in both.
+func Union(current NetworkTopology, new NetworkTopology) NetworkTopology {
+	union := NetworkTopology{
+		Peers:     append([]*peer.AddrInfo{}, new.Peers...),
+		Threshold: new.Threshold,
+	}
+	for _, p := range current.Peers {
+		if !new.IsAllowedPeer(p.ID) {
+			union.Peers = append(union.Peers, p)
+		}
+	}
+
+	return union
+}
+
+func sameAddrs(a []ma.Multiaddr, b []ma.Multiaddr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
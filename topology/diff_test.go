@@ -0,0 +1,80 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology_test
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type TopologyDiffTestSuite struct {
+	suite.Suite
+	peers []*peer.AddrInfo
+}
+
+func TestRunTopologyDiffTestSuite(t *testing.T) {
+	suite.Run(t, new(TopologyDiffTestSuite))
+}
+
+func (s *TopologyDiffTestSuite) SetupTest() {
+	s.peers = []*peer.AddrInfo{}
+	for _, address := range []string{
+		"/dns4/relayer1/tcp/9000/p2p/QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX",
+		"/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT",
+		"/dns4/relayer3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK",
+		"/dns4/relayer3-new/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK",
+	} {
+		p, _ := peer.AddrInfoFromString(address)
+		s.peers = append(s.peers, p)
+	}
+}
+
+func (s *TopologyDiffTestSuite) Test_NewTopologyDiff_SameTopology() {
+	current := topology.NetworkTopology{Peers: s.peers[:3], Threshold: 2}
+
+	diff := topology.NewTopologyDiff(current, current)
+
+	s.True(diff.IsEmpty())
+	s.Equal(3, len(diff.Retained))
+}
+
+func (s *TopologyDiffTestSuite) Test_NewTopologyDiff_ChangedPeers() {
+	current := topology.NetworkTopology{Peers: s.peers[:2], Threshold: 1}
+	new := topology.NetworkTopology{Peers: []*peer.AddrInfo{s.peers[1], s.peers[2]}, Threshold: 2}
+
+	diff := topology.NewTopologyDiff(current, new)
+
+	s.False(diff.IsEmpty())
+	s.Equal([]peer.ID{s.peers[2].ID}, diff.Added)
+	s.Equal([]peer.ID{s.peers[0].ID}, diff.Removed)
+	s.Equal([]peer.ID{s.peers[1].ID}, diff.Retained)
+	s.Equal([]peer.ID{}, diff.Readdressed)
+	s.Equal(1, diff.OldThreshold)
+	s.Equal(2, diff.NewThreshold)
+}
+
+func (s *TopologyDiffTestSuite) Test_NewTopologyDiff_ReaddressedPeer() {
+	current := topology.NetworkTopology{Peers: s.peers[:3], Threshold: 2}
+	new := topology.NetworkTopology{Peers: []*peer.AddrInfo{s.peers[0], s.peers[1], s.peers[3]}, Threshold: 2}
+
+	diff := topology.NewTopologyDiff(current, new)
+
+	s.False(diff.IsEmpty())
+	s.Equal([]peer.ID{}, diff.Added)
+	s.Equal([]peer.ID{}, diff.Removed)
+	s.Equal([]peer.ID{s.peers[3].ID}, diff.Readdressed)
+}
+
+func (s *TopologyDiffTestSuite) Test_Union() {
+	current := topology.NetworkTopology{Peers: s.peers[:3], Threshold: 1}
+	new := topology.NetworkTopology{Peers: []*peer.AddrInfo{s.peers[1], s.peers[3]}, Threshold: 2}
+
+	union := topology.Union(current, new)
+
+	s.Equal(2, union.Threshold)
+	s.Equal([]*peer.AddrInfo{s.peers[1], s.peers[3], s.peers[0]}, union.Peers)
+}
@@ -6,30 +6,99 @@ package topology
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
 )
 
+// gcmPrefix marks topology encrypted with AES-GCM. Topology without
+// the prefix is decrypted with AES-CTR used by previous versions.
+const gcmPrefix = "gcm:"
+
+var ErrInvalidEncryptedTopology = errors.New("invalid encrypted topology")
+
+// ErrUnauthenticatedTopology is returned for AES-CTR encrypted topology
+// when authenticated encryption is required.
+var ErrUnauthenticatedTopology = errors.New("topology is not encrypted with authenticated encryption")
+
 type AESEncryption struct {
-	block cipher.Block
+	block                cipher.Block
+	gcm                  cipher.AEAD
+	requireAuthenticated bool
 }
 
 func NewAESEncryption(key []byte) (*AESEncryption, error) {
+	return newAESEncryption(key, false)
+}
+
+// NewAuthenticatedAESEncryption creates encryption that only decrypts AES-GCM encrypted
+// topology and rejects AES-CTR encrypted topology of previous versions.
+func NewAuthenticatedAESEncryption(key []byte) (*AESEncryption, error) {
+	return newAESEncryption(key, true)
+}
+
+func newAESEncryption(key []byte, requireAuthenticated bool) (*AESEncryption, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
 
 	return &AESEncryption{
-		block: block,
+		block:                block,
+		gcm:                  gcm,
+		requireAuthenticated: requireAuthenticated,
 	}, nil
 }
 
-func (ae *AESEncryption) Decrypt(data string) []byte {
-	bytes, _ := hex.DecodeString(data)
+// Encrypt encrypts data with AES-GCM and returns it hex encoded with the gcm prefix.
+func (ae *AESEncryption) Encrypt(data []byte) (string, error) {
+	nonce := make([]byte, ae.gcm.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	return gcmPrefix + hex.EncodeToString(ae.gcm.Seal(nonce, nonce, data, nil)), nil
+}
+
+// Decrypt decrypts hex encoded AES-GCM encrypted data. Data without the gcm
+// prefix is decrypted as AES-CTR encrypted data without integrity checks
+// unless authenticated encryption is required.
+func (ae *AESEncryption) Decrypt(data string) ([]byte, error) {
+	isGCM := strings.HasPrefix(data, gcmPrefix)
+	bytes, err := hex.DecodeString(strings.TrimPrefix(data, gcmPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEncryptedTopology, err)
+	}
+
+	if isGCM {
+		if len(bytes) < ae.gcm.NonceSize() {
+			return nil, ErrInvalidEncryptedTopology
+		}
+		nonce := bytes[:ae.gcm.NonceSize()]
+		plaintext, err := ae.gcm.Open(nil, nonce, bytes[ae.gcm.NonceSize():], nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEncryptedTopology, err)
+		}
+		return plaintext, nil
+	}
+
+	if ae.requireAuthenticated {
+		return nil, ErrUnauthenticatedTopology
+	}
+	if len(bytes) < aes.BlockSize {
+		return nil, ErrInvalidEncryptedTopology
+	}
 	iv := bytes[:aes.BlockSize]
 	bytes = bytes[aes.BlockSize:]
 	stream := cipher.NewCTR(ae.block, iv)
 	dst := make([]byte, len(bytes))
 	stream.XORKeyStream(dst, bytes)
-	return dst
+	return dst, nil
 }
@@ -5,6 +5,8 @@ package topology_test
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/ChainSafe/sygma-relayer/topology"
@@ -35,10 +37,71 @@ func (s *AESEncryptionTestSuite) Test_Decryption() {
 	}
 
 	encryptedData := "313233343536373831323334353637389343bf912f861f9fd58c1bb5ef6236a5e41a4a3f5698c7f01859a38f90c2d943092158bb485f5cc3653aa8dbf254fea4a0d15bc48c70565aed8057d5a1e2f4de999163b035ec30ce989295535197b6fed02b9584962793058b"
-	decryptedData := s.aesEncryption.Decrypt(encryptedData)
+	decryptedData, err := s.aesEncryption.Decrypt(encryptedData)
+	s.Nil(err)
 
 	decryptedTopology := topology.RawTopology{}
 	_ = json.Unmarshal(decryptedData, &decryptedTopology)
 
 	s.Equal(expectedTopology, decryptedTopology)
 }
+
+func (s *AESEncryptionTestSuite) Test_EncryptDecrypt() {
+	encryptedData, err := s.aesEncryption.Encrypt([]byte("topology"))
+	s.Nil(err)
+	s.True(strings.HasPrefix(encryptedData, "gcm:"))
+
+	decryptedData, err := s.aesEncryption.Decrypt(encryptedData)
+
+	s.Nil(err)
+	s.Equal([]byte("topology"), decryptedData)
+}
+
+func (s *AESEncryptionTestSuite) Test_Decrypt_TamperedData() {
+	encryptedData, _ := s.aesEncryption.Encrypt([]byte("topology"))
+	tamperedData := encryptedData[:len(encryptedData)-2] + "00"
+	if tamperedData == encryptedData {
+		tamperedData = encryptedData[:len(encryptedData)-2] + "01"
+	}
+
+	_, err := s.aesEncryption.Decrypt(tamperedData)
+
+	s.True(errors.Is(err, topology.ErrInvalidEncryptedTopology))
+}
+
+func (s *AESEncryptionTestSuite) Test_Decrypt_InvalidKey() {
+	encryptedData, _ := s.aesEncryption.Encrypt([]byte("topology"))
+	aesEncryption, _ := topology.NewAESEncryption([]byte("anotherstrong32bitpasswordgohere"))
+
+	_, err := aesEncryption.Decrypt(encryptedData)
+
+	s.True(errors.Is(err, topology.ErrInvalidEncryptedTopology))
+}
+
+func (s *AESEncryptionTestSuite) Test_Decrypt_InvalidHex() {
+	_, err := s.aesEncryption.Decrypt("invalid")
+
+	s.True(errors.Is(err, topology.ErrInvalidEncryptedTopology))
+}
+
+func (s *AESEncryptionTestSuite) Test_Decrypt_ShortInput() {
+	_, err := s.aesEncryption.Decrypt("1234")
+	s.True(errors.Is(err, topology.ErrInvalidEncryptedTopology))
+
+	_, err = s.aesEncryption.Decrypt("gcm:1234")
+	s.True(errors.Is(err, topology.ErrInvalidEncryptedTopology))
+}
+
+func (s *AESEncryptionTestSuite) Test_Decrypt_AuthenticatedEncryptionRequired() {
+	encryption, _ := topology.NewAuthenticatedAESEncryption([]byte("asuperstrong32bitpasswordgohere!"))
+	encryptedData, err := encryption.Encrypt([]byte("topology"))
+	s.Nil(err)
+
+	decryptedData, err := encryption.Decrypt(encryptedData)
+	s.Nil(err)
+	s.Equal([]byte("topology"), decryptedData)
+
+	legacyData := "313233343536373831323334353637389343bf912f861f9fd58c1bb5ef6236a5e41a4a3f5698c7f01859a38f90c2d943092158bb485f5cc3653aa8dbf254fea4a0d15bc48c70565aed8057d5a1e2f4de999163b035ec30ce989295535197b6fed02b9584962793058b"
+	_, err = encryption.Decrypt(legacyData)
+	s.True(errors.Is(err, topology.ErrUnauthenticatedTopology))
+}
@@ -0,0 +1,81 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+)
+
+// Canonical returns the canonical topology serialization used for the canonical hash.
+// First line is "threshold:" followed by the decimal threshold. It is followed by one
+// line per peer address, containing the multiaddr string with the "/p2p/<peer ID>"
+// component, sorted in ascending byte order. Lines are separated with "\n" and there
+// is no trailing newline. Only the main PeerAddress of each peer is included, additional
+// PeerAddresses don't change the hash.
+func (nt NetworkTopology) Canonical() (string, error) {
+	addrs := []string{}
+	for _, p := range nt.committeeTopology().Peers {
+		p2pAddrs, err := peer.AddrInfoToP2pAddrs(p)
+		if err != nil {
+			return "", err
+		}
+		for _, addr := range p2pAddrs {
+			addrs = append(addrs, addr.String())
+		}
+	}
+	sort.Strings(addrs)
+
+	lines := append([]string{"threshold:" + strconv.Itoa(nt.Threshold)}, addrs...)
+	return strings.Join(lines, "\n"), nil
+}
+
+// CanonicalHash returns the 0x prefixed hex encoded keccak256 hash of the
+// canonical topology serialization.
+func (nt NetworkTopology) CanonicalHash() (string, error) {
+	canonical, err := nt.Canonical()
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.Encode(crypto.Keccak256([]byte(canonical))), nil
+}
+
+// VerifyHash checks that the topology matches the expected hash from the refresh event.
+// Legacy mode only accepts the legacy hash, canonical mode only the canonical hash and
+// transition mode accepts either of them.
+func (nt NetworkTopology) VerifyHash(expected string, mode string) error {
+	hashes := []func() (string, error){}
+	switch mode {
+	case relayer.LegacyTopologyHash:
+		hashes = append(hashes, nt.Hash)
+	case relayer.CanonicalTopologyHash:
+		hashes = append(hashes, nt.CanonicalHash)
+	case relayer.TransitionTopologyHash, "":
+		hashes = append(hashes, nt.CanonicalHash, nt.Hash)
+	default:
+		return fmt.Errorf("unknown topology hash mode %s", mode)
+	}
+
+	computed := []string{}
+	for _, hashFn := range hashes {
+		hash, err := hashFn()
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(hash, expected) {
+			return nil
+		}
+		computed = append(computed, hash)
+	}
+	return fmt.Errorf("expected hash %s doesn't match %s", expected, strings.Join(computed, " or "))
+}
@@ -0,0 +1,137 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type hashVector struct {
+	Name      string   `json:"name"`
+	Threshold string   `json:"threshold"`
+	Peers     []string `json:"peers"`
+	Canonical string   `json:"canonical"`
+	Hash      string   `json:"hash"`
+}
+
+type TopologyHashTestSuite struct {
+	suite.Suite
+	vectors []hashVector
+}
+
+func TestRunTopologyHashTestSuite(t *testing.T) {
+	suite.Run(t, new(TopologyHashTestSuite))
+}
+
+func (s *TopologyHashTestSuite) SetupSuite() {
+	data, err := ioutil.ReadFile("testdata/hash_vectors.json")
+	s.Nil(err)
+	err = json.Unmarshal(data, &s.vectors)
+	s.Nil(err)
+}
+
+func (s *TopologyHashTestSuite) networkTopology(vector hashVector) topology.NetworkTopology {
+	rawTopology := &topology.RawTopology{Threshold: vector.Threshold}
+	for _, p := range vector.Peers {
+		rawTopology.Peers = append(rawTopology.Peers, topology.RawPeer{PeerAddress: p})
+	}
+	nt, err := topology.ProcessRawTopology(rawTopology)
+	s.Nil(err)
+	return nt
+}
+
+func (s *TopologyHashTestSuite) Test_CanonicalHash_Vectors() {
+	for _, vector := range s.vectors {
+		nt := s.networkTopology(vector)
+
+		canonical, err := nt.Canonical()
+		s.Nil(err)
+		hash, err := nt.CanonicalHash()
+		s.Nil(err)
+
+		s.Equal(vector.Canonical, canonical, vector.Name)
+		s.Equal(vector.Hash, hash, vector.Name)
+	}
+}
+
+func (s *TopologyHashTestSuite) Test_CanonicalHash_IndependentOfPeerOrder() {
+	vector := s.vectors[1]
+	reversed := vector
+	reversed.Peers = []string{}
+	for i := len(vector.Peers) - 1; i >= 0; i-- {
+		reversed.Peers = append(reversed.Peers, vector.Peers[i])
+	}
+
+	hash, err := s.networkTopology(reversed).CanonicalHash()
+
+	s.Nil(err)
+	s.Equal(vector.Hash, hash)
+}
+
+func (s *TopologyHashTestSuite) Test_VerifyHash_CanonicalMode() {
+	nt := s.networkTopology(s.vectors[0])
+	legacyHash, _ := nt.Hash()
+
+	s.Nil(nt.VerifyHash(s.vectors[0].Hash, relayer.CanonicalTopologyHash))
+	s.NotNil(nt.VerifyHash(legacyHash, relayer.CanonicalTopologyHash))
+}
+
+func (s *TopologyHashTestSuite) Test_VerifyHash_LegacyMode() {
+	nt := s.networkTopology(s.vectors[0])
+	legacyHash, _ := nt.Hash()
+
+	s.Nil(nt.VerifyHash(legacyHash, relayer.LegacyTopologyHash))
+	s.NotNil(nt.VerifyHash(s.vectors[0].Hash, relayer.LegacyTopologyHash))
+}
+
+func (s *TopologyHashTestSuite) Test_VerifyHash_TransitionMode() {
+	nt := s.networkTopology(s.vectors[0])
+	legacyHash, _ := nt.Hash()
+
+	s.Nil(nt.VerifyHash(legacyHash, relayer.TransitionTopologyHash))
+	s.Nil(nt.VerifyHash(s.vectors[0].Hash, relayer.TransitionTopologyHash))
+	s.NotNil(nt.VerifyHash("0x1234", relayer.TransitionTopologyHash))
+}
+
+func (s *TopologyHashTestSuite) Test_VerifyHash_UnknownMode() {
+	nt := s.networkTopology(s.vectors[0])
+
+	s.NotNil(nt.VerifyHash(s.vectors[0].Hash, "sha"))
+}
+
+func (s *TopologyHashTestSuite) Test_Hashes_IndependentOfAdditionalPeerAddresses() {
+	vector := s.vectors[0]
+	rawTopology := &topology.RawTopology{Threshold: vector.Threshold}
+	legacyTopology := topology.NetworkTopology{Threshold: 2}
+	for i, p := range vector.Peers {
+		rawTopology.Peers = append(rawTopology.Peers, topology.RawPeer{
+			PeerAddress:   p,
+			PeerAddresses: []string{fmt.Sprintf("/ip4/10.0.0.%d/tcp/9000/p2p/%s", i, strings.Split(p, "/p2p/")[1])},
+		})
+		addrInfo, err := peer.AddrInfoFromString(p)
+		s.Nil(err)
+		legacyTopology.Peers = append(legacyTopology.Peers, addrInfo)
+	}
+	nt, err := topology.ProcessRawTopology(rawTopology)
+	s.Nil(err)
+	s.Len(nt.Peers[0].Addrs, 2)
+
+	hash, err := nt.CanonicalHash()
+	s.Nil(err)
+	s.Equal(vector.Hash, hash)
+	legacyHash, err := nt.Hash()
+	s.Nil(err)
+	expectedLegacyHash, err := legacyTopology.Hash()
+	s.Nil(err)
+	s.Equal(expectedLegacyHash, legacyHash)
+}
@@ -0,0 +1,121 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+)
+
+const httpTimeout = 30 * time.Second
+
+// NewS3TopologyProvider creates topology provider which fetches topology
+// from the configured S3 bucket.
+func NewS3TopologyProvider(config relayer.TopologyConfiguration, decrypter Decrypter, verifier Verifier) (NetworkTopologyProvider, error) {
+	client, err := minio.New(config.ServiceAddress, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecKey, ""),
+		Secure: true,
+		Region: config.BucketRegion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &topologyProvider{
+		fetcher: &s3Fetcher{
+			client:     *client,
+			bucketName: config.BucketName,
+		},
+		document:  config.DocumentName,
+		decrypter: decrypter,
+		verifier:  verifier,
+	}, nil
+}
+
+type s3Fetcher struct {
+	client     minio.Client
+	bucketName string
+}
+
+func (f *s3Fetcher) fetch(name string) ([]byte, error) {
+	obj, err := f.client.GetObject(context.Background(), f.bucketName, name, minio.GetObjectOptions{})
+	if err != nil {
+		log.Err(err).Msg("unable to get topology object")
+		return nil, err
+	}
+	defer obj.Close()
+
+	data, err := ioutil.ReadAll(obj)
+	if err != nil {
+		log.Err(err).Msg("error on reading topology data")
+		return nil, err
+	}
+	return data, nil
+}
+
+// NewFileTopologyProvider creates topology provider which reads topology
+// from a local file.
+func NewFileTopologyProvider(path string, decrypter Decrypter, verifier Verifier) NetworkTopologyProvider {
+	return &topologyProvider{
+		fetcher:   &fileFetcher{},
+		document:  path,
+		decrypter: decrypter,
+		verifier:  verifier,
+	}
+}
+
+type fileFetcher struct{}
+
+func (f *fileFetcher) fetch(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read topology file: %w", err)
+	}
+	return data, nil
+}
+
+// NewHTTPTopologyProvider creates topology provider which fetches topology
+// from the URL with a GET request.
+func NewHTTPTopologyProvider(url string, decrypter Decrypter, verifier Verifier) NetworkTopologyProvider {
+	return &topologyProvider{
+		fetcher: &httpFetcher{
+			client: &http.Client{
+				Timeout: httpTimeout,
+			},
+		},
+		document:  url,
+		decrypter: decrypter,
+		verifier:  verifier,
+	}
+}
+
+type httpFetcher struct {
+	client *http.Client
+}
+
+func (f *httpFetcher) fetch(url string) ([]byte, error) {
+	resp, err := f.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch topology: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("topology server responded with status %d", resp.StatusCode)
+	}
+	return data, nil
+}
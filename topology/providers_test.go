@@ -0,0 +1,224 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	encryptionKey = "asuperstrong32bitpasswordgohere!"
+	rawTopology   = `{
+		"peers": [
+			{"peerAddress": "/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT"},
+			{"peerAddress": "/dns4/relayer3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK"},
+			{"peerAddress": "/dns4/relayer1/tcp/9000/p2p/QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX"}
+		],
+		"threshold": "2"
+	}`
+)
+
+func encrypt(data string) string {
+	block, _ := aes.NewCipher([]byte(encryptionKey))
+	iv := []byte("1234567812345678")
+	dst := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(dst, []byte(data))
+	return hex.EncodeToString(append(iv, dst...))
+}
+
+type FileTopologyProviderTestSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestRunFileTopologyProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(FileTopologyProviderTestSuite))
+}
+
+func (s *FileTopologyProviderTestSuite) SetupTest() {
+	s.path = fmt.Sprintf("%s/topology.json", s.T().TempDir())
+}
+
+func (s *FileTopologyProviderTestSuite) Test_NetworkTopology_MissingFile() {
+	provider := topology.NewFileTopologyProvider(s.path, nil, nil)
+
+	_, err := provider.NetworkTopology()
+
+	s.NotNil(err)
+}
+
+func (s *FileTopologyProviderTestSuite) Test_NetworkTopology_Plaintext() {
+	_ = ioutil.WriteFile(s.path, []byte(rawTopology), 0600)
+	provider, err := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type:     relayer.FileTopologyProvider,
+		FilePath: s.path,
+	})
+	s.Nil(err)
+
+	topology, err := provider.NetworkTopology()
+
+	s.Nil(err)
+	s.Equal(2, topology.Threshold)
+	s.Equal(3, len(topology.Peers))
+}
+
+func (s *FileTopologyProviderTestSuite) Test_NetworkTopology_Encrypted() {
+	_ = ioutil.WriteFile(s.path, []byte(encrypt(rawTopology)+"\n"), 0600)
+	provider, err := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type:          relayer.FileTopologyProvider,
+		FilePath:      s.path,
+		EncryptionKey: encryptionKey,
+	})
+	s.Nil(err)
+
+	topology, err := provider.NetworkTopology()
+
+	s.Nil(err)
+	s.Equal(2, topology.Threshold)
+	s.Equal("QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT", topology.Peers[0].ID.Pretty())
+}
+
+func (s *FileTopologyProviderTestSuite) Test_NetworkTopology_InvalidEncryptedData() {
+	_ = ioutil.WriteFile(s.path, []byte("invalid"), 0600)
+	provider, _ := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type:          relayer.FileTopologyProvider,
+		FilePath:      s.path,
+		EncryptionKey: encryptionKey,
+	})
+
+	_, err := provider.NetworkTopology()
+
+	s.NotNil(err)
+}
+
+type HTTPTopologyProviderTestSuite struct {
+	suite.Suite
+	status int
+	data   string
+	server *httptest.Server
+}
+
+func TestRunHTTPTopologyProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPTopologyProviderTestSuite))
+}
+
+func (s *HTTPTopologyProviderTestSuite) SetupTest() {
+	s.status = http.StatusOK
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(s.data))
+	}))
+}
+func (s *HTTPTopologyProviderTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *HTTPTopologyProviderTestSuite) Test_NetworkTopology_Plaintext() {
+	s.data = rawTopology
+	provider, err := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type: relayer.HTTPTopologyProvider,
+		URL:  s.server.URL,
+	})
+	s.Nil(err)
+
+	topology, err := provider.NetworkTopology()
+
+	s.Nil(err)
+	s.Equal(2, topology.Threshold)
+	s.Equal(3, len(topology.Peers))
+}
+
+func (s *HTTPTopologyProviderTestSuite) Test_NetworkTopology_Encrypted() {
+	s.data = encrypt(rawTopology)
+	provider, err := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type:          relayer.HTTPTopologyProvider,
+		URL:           s.server.URL,
+		EncryptionKey: encryptionKey,
+	})
+	s.Nil(err)
+
+	topology, err := provider.NetworkTopology()
+
+	s.Nil(err)
+	s.Equal(2, topology.Threshold)
+}
+
+func (s *HTTPTopologyProviderTestSuite) Test_NetworkTopology_ErrorStatus() {
+	s.status = http.StatusNotFound
+	provider := topology.NewHTTPTopologyProvider(s.server.URL, nil, nil)
+
+	_, err := provider.NetworkTopology()
+
+	s.NotNil(err)
+}
+
+func (s *HTTPTopologyProviderTestSuite) Test_NewNetworkTopologyProvider_UnknownType() {
+	_, err := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type: "ipfs",
+	})
+
+	s.NotNil(err)
+}
+
+func (s *FileTopologyProviderTestSuite) Test_NetworkTopology_Signed() {
+	key, _ := crypto.GenerateKey()
+	signature, _ := topology.Sign([]byte(rawTopology), key)
+	_ = ioutil.WriteFile(s.path, []byte(encrypt(rawTopology)), 0600)
+	_ = ioutil.WriteFile(s.path+topology.SignatureSuffix, []byte(fmt.Sprintf(`["%s"]`, signature)), 0600)
+	provider, err := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type:           relayer.FileTopologyProvider,
+		FilePath:       s.path,
+		EncryptionKey:  encryptionKey,
+		AdminAddresses: []string{crypto.PubkeyToAddress(key.PublicKey).Hex()},
+	})
+	s.Nil(err)
+
+	topology, err := provider.NetworkTopology()
+
+	s.Nil(err)
+	s.Equal(2, topology.Threshold)
+}
+
+func (s *FileTopologyProviderTestSuite) Test_NetworkTopology_MissingSignatures() {
+	key, _ := crypto.GenerateKey()
+	_ = ioutil.WriteFile(s.path, []byte(rawTopology), 0600)
+	provider, _ := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type:           relayer.FileTopologyProvider,
+		FilePath:       s.path,
+		AdminAddresses: []string{crypto.PubkeyToAddress(key.PublicKey).Hex()},
+	})
+
+	_, err := provider.NetworkTopology()
+
+	s.NotNil(err)
+}
+
+func (s *FileTopologyProviderTestSuite) Test_NetworkTopology_SignedByUnknownKey() {
+	key, _ := crypto.GenerateKey()
+	adminKey, _ := crypto.GenerateKey()
+	signature, _ := topology.Sign([]byte(rawTopology), key)
+	_ = ioutil.WriteFile(s.path, []byte(rawTopology), 0600)
+	_ = ioutil.WriteFile(s.path+topology.SignatureSuffix, []byte(fmt.Sprintf(`["%s"]`, signature)), 0600)
+	provider, _ := topology.NewNetworkTopologyProvider(relayer.TopologyConfiguration{
+		Type:           relayer.FileTopologyProvider,
+		FilePath:       s.path,
+		AdminAddresses: []string{crypto.PubkeyToAddress(adminKey.PublicKey).Hex()},
+	})
+
+	_, err := provider.NetworkTopology()
+
+	s.NotNil(err)
+}
@@ -0,0 +1,96 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignatureSuffix is appended to the topology document name to get the
+// name of the document containing topology signatures.
+const SignatureSuffix = ".sig"
+
+// SignatureVerifier verifies detached topology signatures. Signatures document is
+// a JSON list of hex encoded ethereum signatures of the keccak256 hash of the
+// plaintext topology document.
+type SignatureVerifier struct {
+	admins             map[common.Address]bool
+	requiredSignatures int
+}
+
+// NewSignatureVerifier creates verifier that requires topology to be signed by at least
+// required signatures number of different admins. At least one signature is required.
+func NewSignatureVerifier(adminAddresses []string, requiredSignatures int) (*SignatureVerifier, error) {
+	admins := make(map[common.Address]bool)
+	for _, address := range adminAddresses {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid topology admin address %s", address)
+		}
+		admins[common.HexToAddress(address)] = true
+	}
+
+	if requiredSignatures < 1 {
+		requiredSignatures = 1
+	}
+	if requiredSignatures > len(admins) {
+		return nil, fmt.Errorf("required topology signatures %d exceed number of admins %d", requiredSignatures, len(admins))
+	}
+
+	return &SignatureVerifier{
+		admins:             admins,
+		requiredSignatures: requiredSignatures,
+	}, nil
+}
+
+// Verify checks that the topology document is signed by enough admins.
+func (v *SignatureVerifier) Verify(document []byte, signatures []byte) error {
+	encodedSignatures := []string{}
+	err := json.Unmarshal(signatures, &encodedSignatures)
+	if err != nil {
+		return fmt.Errorf("invalid topology signatures: %w", err)
+	}
+
+	hash := crypto.Keccak256(document)
+	signers := make(map[common.Address]bool)
+	for _, encodedSignature := range encodedSignatures {
+		signature, err := hexutil.Decode(encodedSignature)
+		if err != nil || len(signature) != crypto.SignatureLength {
+			continue
+		}
+		if signature[crypto.RecoveryIDOffset] >= 27 {
+			signature[crypto.RecoveryIDOffset] -= 27
+		}
+
+		pubKey, err := crypto.SigToPub(hash, signature)
+		if err != nil {
+			continue
+		}
+		signer := crypto.PubkeyToAddress(*pubKey)
+		if v.admins[signer] {
+			signers[signer] = true
+		}
+	}
+
+	if len(signers) < v.requiredSignatures {
+		return fmt.Errorf("topology signed by %d admins, %d required", len(signers), v.requiredSignatures)
+	}
+	return nil
+}
+
+// Sign signs the plaintext topology document with the admin key and returns
+// the hex encoded signature.
+func Sign(document []byte, key *ecdsa.PrivateKey) (string, error) {
+	signature, err := crypto.Sign(crypto.Keccak256(document), key)
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.Encode(signature), nil
+}
@@ -0,0 +1,92 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type SignatureVerifierTestSuite struct {
+	suite.Suite
+	keys      []*ecdsa.PrivateKey
+	addresses []string
+	document  []byte
+}
+
+func TestRunSignatureVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(SignatureVerifierTestSuite))
+}
+
+func (s *SignatureVerifierTestSuite) SetupTest() {
+	s.keys = []*ecdsa.PrivateKey{}
+	s.addresses = []string{}
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		s.keys = append(s.keys, key)
+		s.addresses = append(s.addresses, crypto.PubkeyToAddress(key.PublicKey).Hex())
+	}
+	s.document = []byte(rawTopology)
+}
+
+func (s *SignatureVerifierTestSuite) signatures(document []byte, keys ...*ecdsa.PrivateKey) []byte {
+	signatures := []string{}
+	for _, key := range keys {
+		signature, _ := topology.Sign(document, key)
+		signatures = append(signatures, signature)
+	}
+	sb, _ := json.Marshal(signatures)
+	return sb
+}
+
+func (s *SignatureVerifierTestSuite) Test_NewSignatureVerifier_InvalidAddress() {
+	_, err := topology.NewSignatureVerifier([]string{"invalid"}, 1)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_NewSignatureVerifier_TooManyRequiredSignatures() {
+	_, err := topology.NewSignatureVerifier(s.addresses, 4)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_ValidSignatures() {
+	verifier, _ := topology.NewSignatureVerifier(s.addresses, 2)
+
+	err := verifier.Verify(s.document, s.signatures(s.document, s.keys[0], s.keys[2]))
+
+	s.Nil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_DuplicateSignatures() {
+	verifier, _ := topology.NewSignatureVerifier(s.addresses, 2)
+
+	err := verifier.Verify(s.document, s.signatures(s.document, s.keys[0], s.keys[0]))
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_ModifiedDocument() {
+	verifier, _ := topology.NewSignatureVerifier(s.addresses, 1)
+
+	err := verifier.Verify([]byte(`{"peers":[],"threshold":"2"}`), s.signatures(s.document, s.keys[0]))
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_InvalidSignatures() {
+	verifier, _ := topology.NewSignatureVerifier(s.addresses, 1)
+
+	err := verifier.Verify(s.document, []byte(`["0x1234", "invalid"]`))
+	s.NotNil(err)
+
+	err = verifier.Verify(s.document, []byte("invalid"))
+	s.NotNil(err)
+}
@@ -4,26 +4,30 @@
 package topology
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ChainSafe/sygma-relayer/config/relayer"
 	"github.com/libp2p/go-libp2p-core/peer"
-	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/mitchellh/hashstructure/v2"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/rs/zerolog/log"
 )
 
 type NetworkTopology struct {
 	Peers     []*peer.AddrInfo
 	Threshold int
+	// committee contains a peer for each topology peer with only its main PeerAddress.
+	// Topology hashes are calculated from the committee so additional PeerAddresses
+	// used for dialing don't change hashes submitted in refresh events.
+	committee []*peer.AddrInfo
 }
 
+// Hash returns the legacy hashstructure hash of the topology committee.
 func (nt NetworkTopology) Hash() (string, error) {
-	hash, err := hashstructure.Hash(nt, hashstructure.FormatV2, nil)
+	hash, err := hashstructure.Hash(nt.committeeTopology(), hashstructure.FormatV2, nil)
 	if err != nil {
 		return "", err
 	}
@@ -31,6 +35,17 @@ func (nt NetworkTopology) Hash() (string, error) {
 	return strconv.FormatUint(hash, 16), nil
 }
 
+// committeeTopology returns the topology with peer addresses that are part of topology hashes.
+// Topologies not parsed from a raw topology have no separate committee and are hashed with
+// all peer addresses.
+func (nt NetworkTopology) committeeTopology() NetworkTopology {
+	if nt.committee == nil {
+		return NetworkTopology{Peers: nt.Peers, Threshold: nt.Threshold}
+	}
+
+	return NetworkTopology{Peers: nt.committee, Threshold: nt.Threshold}
+}
+
 func (nt NetworkTopology) IsAllowedPeer(peer peer.ID) bool {
 	for _, p := range nt.Peers {
 		if p.ID == peer {
@@ -45,27 +60,42 @@ type NetworkTopologyProvider interface {
 	NetworkTopology() (NetworkTopology, error)
 }
 
+// NewNetworkTopologyProvider creates topology provider of the configured type.
+// Topology is decrypted only if the encryption key is configured and its signatures
+// are verified only if admin addresses are configured.
 func NewNetworkTopologyProvider(config relayer.TopologyConfiguration) (NetworkTopologyProvider, error) {
-	client, err := minio.New(config.ServiceAddress, &minio.Options{
-		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecKey, ""),
-		Secure: true,
-		Region: config.BucketRegion,
-	})
-	if err != nil {
-		return nil, err
+	var decrypter Decrypter
+	if config.EncryptionKey != "" {
+		newEncryption := NewAESEncryption
+		if config.RequireAuthenticatedEncryption {
+			newEncryption = NewAuthenticatedAESEncryption
+		}
+		aesEncryption, err := newEncryption([]byte(config.EncryptionKey))
+		if err != nil {
+			return nil, err
+		}
+		decrypter = aesEncryption
 	}
 
-	decrypter, err := NewAESEncryption([]byte(config.EncryptionKey))
-	if err != nil {
-		return nil, err
+	var verifier Verifier
+	if len(config.AdminAddresses) != 0 {
+		signatureVerifier, err := NewSignatureVerifier(config.AdminAddresses, config.RequiredSignatures)
+		if err != nil {
+			return nil, err
+		}
+		verifier = signatureVerifier
 	}
 
-	return &topologyProvider{
-		client:       *client,
-		documentName: config.DocumentName,
-		bucketName:   config.BucketName,
-		decrypter:    decrypter,
-	}, nil
+	switch config.Type {
+	case relayer.S3TopologyProvider, "":
+		return NewS3TopologyProvider(config, decrypter, verifier)
+	case relayer.FileTopologyProvider:
+		return NewFileTopologyProvider(config.FilePath, decrypter, verifier), nil
+	case relayer.HTTPTopologyProvider:
+		return NewHTTPTopologyProvider(config.URL, decrypter, verifier), nil
+	default:
+		return nil, fmt.Errorf("unknown topology provider type %s", config.Type)
+	}
 }
 
 type RawTopology struct {
@@ -73,45 +103,67 @@ type RawTopology struct {
 	Threshold string    `mapstructure:"Threshold" json:"threshold"`
 }
 
+// RawPeer contains peer multiaddr with the peer ID. Additional addresses of the same
+// peer, dialed if the main address is unreachable, can be set in PeerAddresses.
 type RawPeer struct {
-	PeerAddress string `mapstructure:"PeerAddress" json:"peerAddress"`
+	PeerAddress   string   `mapstructure:"PeerAddress" json:"peerAddress"`
+	PeerAddresses []string `mapstructure:"PeerAddresses" json:"peerAddresses,omitempty"`
 }
 
 type Decrypter interface {
-	Decrypt(data string) []byte
+	Decrypt(data string) ([]byte, error)
+}
+
+type Verifier interface {
+	Verify(document []byte, signatures []byte) error
+}
+
+// documentFetcher fetches documents from the topology source
+type documentFetcher interface {
+	fetch(name string) ([]byte, error)
 }
 
 type topologyProvider struct {
-	client       minio.Client
-	documentName string
-	bucketName   string
-	decrypter    Decrypter
+	fetcher   documentFetcher
+	document  string
+	decrypter Decrypter
+	verifier  Verifier
 }
 
+// NetworkTopology fetches the topology document and decrypts it if decrypter is provided.
+// If verifier is provided topology signatures are fetched from the document with the
+// signature suffix and verified before topology is returned.
 func (t *topologyProvider) NetworkTopology() (NetworkTopology, error) {
-	ctx := context.Background()
-
-	obj, err := t.client.GetObject(ctx, t.bucketName, t.documentName, minio.GetObjectOptions{})
+	data, err := t.fetcher.fetch(t.document)
 	if err != nil {
-		log.Err(err).Msg("unable to get topology object")
 		return NetworkTopology{}, err
 	}
 
-	stat, err := obj.Stat()
-	if err != nil {
-		log.Err(err).Msg("unable to get topology object information")
-		return NetworkTopology{}, err
+	if t.decrypter != nil {
+		data, err = t.decrypter.Decrypt(strings.TrimSpace(string(data)))
+		if err != nil {
+			return NetworkTopology{}, err
+		}
 	}
 
-	eData := make([]byte, stat.Size)
-	_, err = obj.Read(eData)
-	if err != nil {
-		log.Err(err).Msg("error on reading topology data")
+	if t.verifier != nil {
+		signatures, err := t.fetcher.fetch(t.document + SignatureSuffix)
+		if err != nil {
+			return NetworkTopology{}, fmt.Errorf("unable to fetch topology signatures: %w", err)
+		}
+		err = t.verifier.Verify(data, signatures)
+		if err != nil {
+			return NetworkTopology{}, err
+		}
 	}
 
-	data := t.decrypter.Decrypt(string(eData))
+	return ParseTopology(data)
+}
+
+// ParseTopology parses plaintext topology document into network topology
+func ParseTopology(data []byte) (NetworkTopology, error) {
 	rawTopology := &RawTopology{}
-	err = json.Unmarshal(data, rawTopology)
+	err := json.Unmarshal(data, rawTopology)
 	if err != nil {
 		log.Err(err).Msg("unable to unmarshal topology data")
 		return NetworkTopology{}, err
@@ -122,12 +174,31 @@ func (t *topologyProvider) NetworkTopology() (NetworkTopology, error) {
 
 func ProcessRawTopology(rawTopology *RawTopology) (NetworkTopology, error) {
 	var peers []*peer.AddrInfo
+	var committee []*peer.AddrInfo
+	peersByID := make(map[peer.ID]*peer.AddrInfo)
 	for _, p := range rawTopology.Peers {
-		addrInfo, err := peer.AddrInfoFromString(p.PeerAddress)
-		if err != nil {
-			return NetworkTopology{}, fmt.Errorf("invalid peer address %s: %w", p.PeerAddress, err)
+		for i, address := range append([]string{p.PeerAddress}, p.PeerAddresses...) {
+			addrInfo, err := peer.AddrInfoFromString(address)
+			if err != nil {
+				return NetworkTopology{}, fmt.Errorf("invalid peer address %s: %w", address, err)
+			}
+			if i == 0 {
+				committee = append(committee, &peer.AddrInfo{ID: addrInfo.ID, Addrs: append([]ma.Multiaddr{}, addrInfo.Addrs...)})
+			}
+
+			// addresses of the same peer are merged into a single peer
+			existingPeer, ok := peersByID[addrInfo.ID]
+			if !ok {
+				peersByID[addrInfo.ID] = addrInfo
+				peers = append(peers, addrInfo)
+				continue
+			}
+			for _, addr := range addrInfo.Addrs {
+				if !containsAddr(existingPeer.Addrs, addr) {
+					existingPeer.Addrs = append(existingPeer.Addrs, addr)
+				}
+			}
 		}
-		peers = append(peers, addrInfo)
 	}
 
 	threshold, err := strconv.ParseInt(rawTopology.Threshold, 0, 0)
@@ -137,5 +208,15 @@ func ProcessRawTopology(rawTopology *RawTopology) (NetworkTopology, error) {
 	if threshold <= 1 {
 		return NetworkTopology{}, fmt.Errorf("mpc threshold must be bigger then 1 %v", err)
 	}
-	return NetworkTopology{Peers: peers, Threshold: int(threshold)}, nil
+	return NetworkTopology{Peers: peers, Threshold: int(threshold), committee: committee}, nil
+}
+
+func containsAddr(addrs []ma.Multiaddr, addr ma.Multiaddr) bool {
+	for _, a := range addrs {
+		if a.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
 }
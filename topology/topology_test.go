@@ -52,6 +52,44 @@ func (s *TopologyTestSuite) Test_ProcessRawTopology_InvalidPeerAddress() {
 	s.NotNil(err)
 }
 
+func (s *TopologyTestSuite) Test_ProcessRawTopology_MultiplePeerAddresses() {
+	topology, err := topology.ProcessRawTopology(&topology.RawTopology{
+		Peers: []topology.RawPeer{
+			{
+				PeerAddress: "/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT",
+				PeerAddresses: []string{
+					"/ip4/10.0.0.2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT",
+					"/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT",
+				},
+			},
+			{PeerAddress: "/dns4/relayer3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK"},
+			{PeerAddress: "/ip4/10.0.0.3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK"},
+		},
+		Threshold: "2",
+	})
+	s.Nil(err)
+	s.Equal(2, len(topology.Peers))
+	s.Equal("QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT", topology.Peers[0].ID.Pretty())
+	s.Equal(2, len(topology.Peers[0].Addrs))
+	s.Equal("/dns4/relayer2/tcp/9001", topology.Peers[0].Addrs[0].String())
+	s.Equal("/ip4/10.0.0.2/tcp/9001", topology.Peers[0].Addrs[1].String())
+	s.Equal("QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK", topology.Peers[1].ID.Pretty())
+	s.Equal(2, len(topology.Peers[1].Addrs))
+}
+
+func (s *TopologyTestSuite) Test_ProcessRawTopology_InvalidAdditionalPeerAddress() {
+	_, err := topology.ProcessRawTopology(&topology.RawTopology{
+		Peers: []topology.RawPeer{
+			{
+				PeerAddress:   "/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT",
+				PeerAddresses: []string{"/ip4/10.0.0.2/tcp/9001/p2p/"},
+			},
+		},
+		Threshold: "2",
+	})
+	s.NotNil(err)
+}
+
 func (s *TopologyTestSuite) Test_ProcessRawTopology_InvalidThreshold() {
 	rt := &topology.RawTopology{
 		Peers: []topology.RawPeer{
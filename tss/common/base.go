@@ -6,8 +6,10 @@ package common
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
 	"github.com/binance-chain/tss-lib/tss"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -16,6 +18,7 @@ import (
 
 type Party interface {
 	UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error)
+	Update(msg tss.ParsedMessage) (bool, *tss.Error)
 	Start() *tss.Error
 	WaitingFor() []*tss.PartyID
 }
@@ -30,9 +33,46 @@ type BaseTss struct {
 	Communication comm.Communication
 	Peers         []peer.ID
 	Log           zerolog.Logger
+	// MessageTypes are tss-lib messages of the process protocol used to parse
+	// inbound messages, if empty messages are resolved from the protobuf registry.
+	MessageTypes []tss.MessageContent
 
 	ErrChn chan error
 	Cancel context.CancelFunc
+
+	curveMu   sync.Mutex
+	curveHeld bool
+}
+
+// AcquireCurve sets the curve used by the tss process and waits until processes
+// using other curves end. Error is returned if context is canceled while waiting.
+func (b *BaseTss) AcquireCurve(ctx context.Context, c curve.Curve) error {
+	err := curve.Acquire(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	b.curveMu.Lock()
+	defer b.curveMu.Unlock()
+	if b.curveHeld || ctx.Err() != nil {
+		curve.Release()
+		return ctx.Err()
+	}
+
+	b.curveHeld = true
+	return nil
+}
+
+// ReleaseCurve allows processes using other curves to start.
+func (b *BaseTss) ReleaseCurve() {
+	b.curveMu.Lock()
+	defer b.curveMu.Unlock()
+	if !b.curveHeld {
+		return
+	}
+
+	b.curveHeld = false
+	curve.Release()
 }
 
 // PopulatePartyStore populates party store map with sorted parties for
@@ -70,9 +110,23 @@ func (b *BaseTss) ProcessInboundMessages(ctx context.Context, msgChan chan *comm
 						return
 					}
 
-					ok, err := b.Party.UpdateFromBytes(msg.MsgBytes, b.PartyStore[wMsg.From.Pretty()], msg.IsBroadcast)
-					if !ok {
+					if len(b.MessageTypes) == 0 {
+						ok, err := b.Party.UpdateFromBytes(msg.MsgBytes, b.PartyStore[wMsg.From.Pretty()], msg.IsBroadcast)
+						if !ok {
+							b.ErrChn <- err
+						}
+						return
+					}
+
+					parsedMsg, err := ParseWireMessage(msg.MsgBytes, b.PartyStore[wMsg.From.Pretty()], msg.IsBroadcast, b.MessageTypes)
+					if err != nil {
 						b.ErrChn <- err
+						return
+					}
+
+					ok, tssErr := b.Party.Update(parsedMsg)
+					if !ok {
+						b.ErrChn <- tssErr
 					}
 				}()
 			}
@@ -4,7 +4,15 @@
 package common
 
 import (
-	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/binance-chain/tss-lib/tss"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 type TssMessage struct {
@@ -12,53 +20,84 @@ type TssMessage struct {
 	IsBroadcast bool   `json:"isBroadcast"`
 }
 
+// MarshalTssMessage encodes tss-lib wire message bytes as a protobuf tss payload.
 func MarshalTssMessage(msgBytes []byte, isBroadcast bool) ([]byte, error) {
-	tssMsg := &TssMessage{
-		IsBroadcast: isBroadcast,
-		MsgBytes:    msgBytes,
-	}
-
-	msgBytes, err := json.Marshal(tssMsg)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	return msgBytes, nil
+	return comm.MarshalTssPayload(msgBytes, isBroadcast), nil
 }
 
+// UnmarshalTssMessage decodes tss payload received from peers using either
+// the protobuf or the legacy JSON payload encoding.
 func UnmarshalTssMessage(msgBytes []byte) (*TssMessage, error) {
-	msg := &TssMessage{}
-	err := json.Unmarshal(msgBytes, msg)
+	wireBytes, isBroadcast, err := comm.UnmarshalTssPayload(msgBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	return msg, nil
+	return &TssMessage{
+		MsgBytes:    wireBytes,
+		IsBroadcast: isBroadcast,
+	}, nil
 }
 
 type StartMessage struct {
 	Params []byte `json:"params"`
 }
 
+// MarshalStartMessage encodes tss process start params as a protobuf start payload.
 func MarshalStartMessage(params []byte) ([]byte, error) {
-	startSignMessage := &StartMessage{
-		Params: params,
-	}
+	return comm.MarshalStartPayload(params), nil
+}
 
-	msgBytes, err := json.Marshal(startSignMessage)
+// UnmarshalStartMessage decodes start payload received from the coordinator using
+// either the protobuf or the legacy JSON payload encoding.
+func UnmarshalStartMessage(msgBytes []byte) (*StartMessage, error) {
+	params, err := comm.UnmarshalStartPayload(msgBytes)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 
-	return msgBytes, nil
+	return &StartMessage{
+		Params: params,
+	}, nil
 }
 
-func UnmarshalStartMessage(msgBytes []byte) (*StartMessage, error) {
-	msg := &StartMessage{}
-	err := json.Unmarshal(msgBytes, msg)
+// ParseWireMessage parses tss-lib wire message into one of the provided message types.
+// ECDSA and EdDSA tss-lib messages share names without a protobuf package so they
+// can't be resolved from the global protobuf registry when both are used.
+func ParseWireMessage(
+	wireBytes []byte,
+	from *tss.PartyID,
+	isBroadcast bool,
+	messageTypes []tss.MessageContent,
+) (tss.ParsedMessage, error) {
+	wire := &tss.MessageWrapper{
+		Message:     &anypb.Any{},
+		From:        from.MessageWrapper_PartyID,
+		IsBroadcast: isBroadcast,
+	}
+	err := proto.Unmarshal(wireBytes, wire.Message)
 	if err != nil {
 		return nil, err
 	}
 
-	return msg, nil
+	name := wire.Message.TypeUrl[strings.LastIndex(wire.Message.TypeUrl, "/")+1:]
+	for _, messageType := range messageTypes {
+		if string(messageType.(protoreflect.ProtoMessage).ProtoReflect().Descriptor().FullName()) != name {
+			continue
+		}
+
+		content := reflect.New(reflect.TypeOf(messageType).Elem()).Interface().(tss.MessageContent)
+		err = proto.Unmarshal(wire.Message.Value, content.(protoreflect.ProtoMessage))
+		if err != nil {
+			return nil, err
+		}
+
+		routing := tss.MessageRouting{
+			From:        from,
+			IsBroadcast: isBroadcast,
+		}
+		return tss.NewMessage(routing, content, wire), nil
+	}
+
+	return nil, fmt.Errorf("unknown tss message type %s", name)
 }
@@ -4,9 +4,15 @@
 package common_test
 
 import (
+	"encoding/json"
+	"math/big"
 	"testing"
 
 	"github.com/ChainSafe/sygma-relayer/tss/common"
+	cmt "github.com/binance-chain/tss-lib/crypto/commitments"
+	ecdsaKeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	eddsaKeygen "github.com/binance-chain/tss-lib/eddsa/keygen"
+	"github.com/binance-chain/tss-lib/tss"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -32,6 +38,19 @@ func (s *TssMessageTestSuite) Test_UnmarshaledMessageShouldBeEqual() {
 	s.Equal(originalMsg, unmarshaledMsg)
 }
 
+func (s *TssMessageTestSuite) Test_UnmarshalLegacyJSONMessage() {
+	msgBytes, _ := json.Marshal(&common.TssMessage{
+		MsgBytes:    []byte{1},
+		IsBroadcast: true,
+	})
+
+	unmarshaledMsg, err := common.UnmarshalTssMessage(msgBytes)
+	s.Nil(err)
+
+	s.Equal([]byte{1}, unmarshaledMsg.MsgBytes)
+	s.True(unmarshaledMsg.IsBroadcast)
+}
+
 type StartMessageTestSuite struct {
 	suite.Suite
 }
@@ -52,3 +71,59 @@ func (s *StartMessageTestSuite) Test_UnmarshaledMessageShouldBeEqual() {
 
 	s.Equal(originalMsg, unmarshaledMsg)
 }
+
+func (s *StartMessageTestSuite) Test_UnmarshalLegacyJSONMessage() {
+	msgBytes, _ := json.Marshal(&common.StartMessage{
+		Params: []byte("test"),
+	})
+
+	unmarshaledMsg, err := common.UnmarshalStartMessage(msgBytes)
+	s.Nil(err)
+
+	s.Equal([]byte("test"), unmarshaledMsg.Params)
+}
+
+type ParseWireMessageTestSuite struct {
+	suite.Suite
+	from *tss.PartyID
+}
+
+func TestRunParseWireMessageTestSuite(t *testing.T) {
+	suite.Run(t, new(ParseWireMessageTestSuite))
+}
+
+func (s *ParseWireMessageTestSuite) SetupTest() {
+	s.from = common.CreatePartyID("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+}
+
+func (s *ParseWireMessageTestSuite) Test_InvalidWireBytes() {
+	_, err := common.ParseWireMessage([]byte{1, 2, 3}, s.from, true, []tss.MessageContent{&eddsaKeygen.KGRound1Message{}})
+
+	s.NotNil(err)
+}
+
+func (s *ParseWireMessageTestSuite) Test_UnknownMessageType() {
+	msg := eddsaKeygen.NewKGRound1Message(s.from, cmt.HashCommitment(big.NewInt(1)))
+	wireBytes, _, _ := msg.WireBytes()
+
+	_, err := common.ParseWireMessage(wireBytes, s.from, true, []tss.MessageContent{&eddsaKeygen.KGRound2Message1{}})
+
+	s.NotNil(err)
+}
+
+func (s *ParseWireMessageTestSuite) Test_ValidMessage() {
+	msg := eddsaKeygen.NewKGRound1Message(s.from, cmt.HashCommitment(big.NewInt(1)))
+	wireBytes, _, _ := msg.WireBytes()
+
+	parsedMsg, err := common.ParseWireMessage(wireBytes, s.from, true, []tss.MessageContent{
+		&ecdsaKeygen.KGRound2Message1{},
+		&eddsaKeygen.KGRound1Message{},
+	})
+
+	s.Nil(err)
+	s.Equal(s.from, parsedMsg.GetFrom())
+	s.True(parsedMsg.IsBroadcast())
+	content, ok := parsedMsg.Content().(*eddsaKeygen.KGRound1Message)
+	s.True(ok)
+	s.True(content.ValidateBasic())
+}
@@ -48,6 +48,21 @@ func (mr *MockPartyMockRecorder) Start() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockParty)(nil).Start))
 }
 
+// Update mocks base method.
+func (m *MockParty) Update(msg tss.ParsedMessage) (bool, *tss.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", msg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*tss.Error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPartyMockRecorder) Update(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockParty)(nil).Update), msg)
+}
+
 // UpdateFromBytes mocks base method.
 func (m *MockParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
 	m.ctrl.T.Helper()
@@ -11,6 +11,8 @@ import (
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/elector"
 	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/journal"
+	"github.com/ChainSafe/sygma-relayer/tss/reputation"
 	"github.com/binance-chain/tss-lib/tss"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -34,14 +36,25 @@ type TssProcess interface {
 	ValidCoordinators() []peer.ID
 }
 
+type SessionJournal interface {
+	UpdatePhase(sessionID string, phase journal.Phase) error
+	Finish(sessionID string, err error) error
+}
+
+type PeerReputation interface {
+	Record(offense reputation.Offense, peers ...peer.ID)
+}
+
 type Coordinator struct {
 	host           host.Host
 	communication  comm.Communication
 	electorFactory *elector.CoordinatorElectorFactory
+	journal        SessionJournal
+	registry       *SessionRegistry
+	reputation     PeerReputation
 
-	pendingProcesses map[string]bool
-	retriedProcesses map[string]bool
-
+	// Clock schedules coordinator and tss process timeouts.
+	Clock              comm.Clock
 	CoordinatorTimeout time.Duration
 	TssTimeout         time.Duration
 	InitiatePeriod     time.Duration
@@ -51,15 +64,18 @@ func NewCoordinator(
 	host host.Host,
 	communication comm.Communication,
 	electorFactory *elector.CoordinatorElectorFactory,
+	journal SessionJournal,
+	reputation PeerReputation,
 ) *Coordinator {
 	return &Coordinator{
 		host:           host,
 		communication:  communication,
 		electorFactory: electorFactory,
+		journal:        journal,
+		registry:       NewSessionRegistry(),
+		reputation:     reputation,
 
-		pendingProcesses: make(map[string]bool),
-		retriedProcesses: make(map[string]bool),
-
+		Clock:              comm.RealClock{},
 		CoordinatorTimeout: coordinatorTimeout,
 		TssTimeout:         tssTimeout,
 		InitiatePeriod:     initiatePeriod,
@@ -69,36 +85,33 @@ func NewCoordinator(
 // Execute calculates process leader and coordinates party readiness and start the tss processes.
 func (c *Coordinator) Execute(ctx context.Context, tssProcess TssProcess, resultChn chan interface{}, statusChn chan error) {
 	sessionID := tssProcess.SessionID()
-	value, ok := c.pendingProcesses[sessionID]
-	if ok && value {
+	err := c.registry.Register(sessionID)
+	if err != nil {
 		log.Warn().Str("SessionID", sessionID).Msgf("Process already pending")
 		statusChn <- nil
 		return
 	}
 
-	c.pendingProcesses[sessionID] = true
-	defer func() { c.pendingProcesses[sessionID] = false }()
-	defer func() { c.retriedProcesses[sessionID] = false }()
+	defer c.registry.Release(sessionID)
 	coordinatorElector := c.electorFactory.CoordinatorElector(sessionID, elector.Static)
 	coordinator, _ := coordinatorElector.Coordinator(ctx, tssProcess.ValidCoordinators())
 	log.Info().Msgf("Starting process %s with coordinator %s", tssProcess.SessionID(), coordinator.Pretty())
 	errChn := make(chan error)
+	timeout := c.Clock.After(c.TssTimeout)
 	go c.start(ctx, tssProcess, coordinator, resultChn, errChn, []peer.ID{})
 
-	ticker := time.NewTicker(c.TssTimeout)
 	failChn := make(chan *comm.WrappedMessage)
 	subscriptionID := c.communication.Subscribe(tssProcess.SessionID(), comm.TssFailMsg, failChn)
 	defer c.communication.UnSubscribe(subscriptionID)
-	defer ticker.Stop()
 	defer tssProcess.Stop()
 	for {
 		select {
-		case <-ticker.C:
+		case <-timeout:
 			{
 				err := fmt.Errorf("tss process timed out after %v", c.TssTimeout)
 				log.Err(err).Str("SessionID", sessionID).Msgf("Tss process timed out")
 				ctx.Done()
-				statusChn <- err
+				c.finish(sessionID, statusChn, err)
 				return
 			}
 		case <-ctx.Done():
@@ -115,29 +128,33 @@ func (c *Coordinator) Execute(ctx context.Context, tssProcess TssProcess, result
 
 				err := fmt.Errorf("tss fail message received for process %s", sessionID)
 				log.Err(err).Msgf("Tss process fail message received")
-				statusChn <- err
+				c.finish(sessionID, statusChn, err)
 				return
 			}
 		case err := <-errChn:
 			{
 				if err == nil {
-					statusChn <- nil
+					c.finish(sessionID, statusChn, nil)
 					return
 				}
 				log.Err(err).Str("SessionID", sessionID).Msgf("Tss process failed with error %+v", err)
 
 				if !tssProcess.Retryable() {
-					statusChn <- fmt.Errorf("process failed with error: %+v", err)
+					c.finish(sessionID, statusChn, fmt.Errorf("process failed with error: %+v", err))
 					return
 				}
 
-				retryError := c.lockRetry(sessionID)
+				retryError := c.registry.LockRetry(sessionID)
 				if retryError != nil {
 					// retry is already pending
+					log.Err(retryError).Msg("retry already locked")
 					continue
 				}
 
+				c.recordOffense(err)
+
 				tssProcess.Stop()
+				c.updatePhase(sessionID, journal.PhaseRetrying)
 				switch err := err.(type) {
 				case *CoordinatorError:
 					{
@@ -152,7 +169,7 @@ func (c *Coordinator) Execute(ctx context.Context, tssProcess TssProcess, result
 					{
 						excludedPeers, err := common.PeersFromParties(err.Culprits())
 						if err != nil {
-							statusChn <- err
+							c.finish(sessionID, statusChn, err)
 							return
 						}
 						go c.retry(ctx, tssProcess, resultChn, errChn, excludedPeers)
@@ -164,7 +181,7 @@ func (c *Coordinator) Execute(ctx context.Context, tssProcess TssProcess, result
 					}
 				default:
 					{
-						statusChn <- err
+						c.finish(sessionID, statusChn, err)
 						return
 					}
 				}
@@ -173,32 +190,75 @@ func (c *Coordinator) Execute(ctx context.Context, tssProcess TssProcess, result
 	}
 }
 
+// Sessions returns snapshots of live and recently finished tss sessions
+// executed by this coordinator.
+func (c *Coordinator) Sessions() []SessionInfo {
+	return c.registry.Sessions()
+}
+
+// Session returns snapshot of the tss session with the provided ID.
+func (c *Coordinator) Session(sessionID string) (SessionInfo, bool) {
+	return c.registry.Session(sessionID)
+}
+
+// LiveSessions returns snapshots of tss sessions currently executed by this coordinator.
+func (c *Coordinator) LiveSessions() []SessionInfo {
+	return c.registry.LiveSessions()
+}
+
 // start initiates listeners for coordinator and participants with static calculated coordinator
 func (c *Coordinator) start(ctx context.Context, tssProcess TssProcess, coordinator peer.ID, resultChn chan interface{}, errChn chan error, excludedPeers []peer.ID) {
+	c.updatePhase(tssProcess.SessionID(), journal.PhaseRunning)
+	c.registry.SetCoordinator(tssProcess.SessionID(), coordinator)
+	c.registry.SetExcludedPeers(tssProcess.SessionID(), excludedPeers)
 	if coordinator.Pretty() == c.host.ID().Pretty() {
+		c.registry.UpdateState(tssProcess.SessionID(), SessionInitiating)
 		c.initiate(ctx, tssProcess, resultChn, errChn, excludedPeers)
 	} else {
 		c.waitForStart(ctx, tssProcess, resultChn, errChn, coordinator, c.CoordinatorTimeout)
 	}
 }
 
-// lockRetry checks if a retry already happened and prevents multiple retries happening
-// at the same time
-func (c *Coordinator) lockRetry(sessionID string) error {
-	retried := c.retriedProcesses[sessionID]
-	if retried {
-		err := fmt.Errorf("process %s has pending retry", sessionID)
-		log.Err(err).Msg("retry already locked")
-		return err
+// finish records session outcome into the journal and sends it to the status channel.
+func (c *Coordinator) finish(sessionID string, statusChn chan error, err error) {
+	c.registry.Finish(sessionID, err)
+	journalErr := c.journal.Finish(sessionID, err)
+	if journalErr != nil {
+		log.Debug().Err(journalErr).Str("SessionID", sessionID).Msg("unable to record session outcome")
+	}
+
+	statusChn <- err
+}
+
+// recordOffense updates reputation of peers responsible for the tss process error.
+func (c *Coordinator) recordOffense(err error) {
+	if c.reputation == nil {
+		return
+	}
+
+	switch err := err.(type) {
+	case *CoordinatorError:
+		c.reputation.Record(reputation.CoordinatorTimeout, err.Peer)
+	case *comm.CommunicationError:
+		c.reputation.Record(reputation.CommunicationFailure, err.Peer)
+	case *tss.Error:
+		culprits, _ := common.PeersFromParties(err.Culprits())
+		c.reputation.Record(reputation.Culprit, culprits...)
 	}
+}
 
-	c.retriedProcesses[sessionID] = true
-	return nil
+// updatePhase records session phase change into the journal.
+func (c *Coordinator) updatePhase(sessionID string, phase journal.Phase) {
+	err := c.journal.UpdatePhase(sessionID, phase)
+	if err != nil {
+		log.Debug().Err(err).Str("SessionID", sessionID).Msgf("unable to record session phase %s", phase)
+	}
 }
 
 // retry initiates full bully process to calculate coordinator and starts a new tss process after
 // an expected error ocurred during regular tss execution
 func (c *Coordinator) retry(ctx context.Context, tssProcess TssProcess, resultChn chan interface{}, errChn chan error, excludedPeers []peer.ID) {
+	c.registry.UpdateState(tssProcess.SessionID(), SessionElecting)
 	coordinatorElector := c.electorFactory.CoordinatorElector(tssProcess.SessionID(), elector.Bully)
 	coordinator, err := coordinatorElector.Coordinator(ctx, common.ExcludePeers(tssProcess.ValidCoordinators(), excludedPeers))
 	if err != nil {
@@ -228,8 +288,7 @@ func (c *Coordinator) initiate(ctx context.Context, tssProcess TssProcess, resul
 	subID := c.communication.Subscribe(tssProcess.SessionID(), comm.TssReadyMsg, readyChan)
 	defer c.communication.UnSubscribe(subID)
 
-	ticker := time.NewTicker(c.InitiatePeriod)
-	defer ticker.Stop()
+	ticker := c.Clock.After(c.InitiatePeriod)
 	c.broadcastInitiateMsg(tssProcess.SessionID())
 	for {
 		select {
@@ -256,11 +315,13 @@ func (c *Coordinator) initiate(ctx context.Context, tssProcess TssProcess, resul
 				}
 
 				go c.communication.Broadcast(c.host.Peerstore().Peers(), startMsgBytes, comm.TssStartMsg, tssProcess.SessionID(), nil)
+				c.registry.UpdateState(tssProcess.SessionID(), SessionRunning)
 				go tssProcess.Start(ctx, true, resultChn, errChn, startParams)
 				return
 			}
-		case <-ticker.C:
+		case <-ticker:
 			{
+				ticker = c.Clock.After(c.InitiatePeriod)
 				c.broadcastInitiateMsg(tssProcess.SessionID())
 			}
 		case <-ctx.Done():
@@ -289,13 +350,13 @@ func (c *Coordinator) waitForStart(
 	startSubID := c.communication.Subscribe(tssProcess.SessionID(), comm.TssStartMsg, startMsgChn)
 	defer c.communication.UnSubscribe(startSubID)
 
-	coordinatorTimeoutTicker := time.NewTicker(timeout)
-	defer coordinatorTimeoutTicker.Stop()
+	coordinatorTimeout := c.Clock.After(timeout)
+	c.registry.UpdateState(tssProcess.SessionID(), SessionWaitingForStart)
 	for {
 		select {
 		case wMsg := <-msgChan:
 			{
-				coordinatorTimeoutTicker.Reset(timeout)
+				coordinatorTimeout = c.Clock.After(timeout)
 
 				log.Debug().Str("SessionID", tssProcess.SessionID()).Msgf("sent ready message to %s", wMsg.From)
 				go c.communication.Broadcast(
@@ -322,10 +383,11 @@ func (c *Coordinator) waitForStart(
 					return
 				}
 
+				c.registry.UpdateState(tssProcess.SessionID(), SessionRunning)
 				go tssProcess.Start(ctx, false, resultChn, errChn, msg.Params)
 				return
 			}
-		case <-coordinatorTimeoutTicker.C:
+		case <-coordinatorTimeout:
 			{
 				errChn <- &CoordinatorError{Peer: coordinator}
 				return
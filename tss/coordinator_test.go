@@ -0,0 +1,226 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package tss_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/signing"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/exp/slices"
+)
+
+type CoordinatorFaultsTestSuite struct {
+	tsstest.CoordinatorTestSuite
+	faults  *memory.Faults
+	network *memory.Network
+}
+
+func TestRunCoordinatorFaultsTestSuite(t *testing.T) {
+	suite.Run(t, new(CoordinatorFaultsTestSuite))
+}
+
+func (s *CoordinatorFaultsTestSuite) SetupTest() {
+	s.CoordinatorTestSuite.SetupTest()
+	s.faults = memory.NewFaults(1)
+	s.BullyConfig.PingWaitTime = 100 * time.Millisecond
+	s.BullyConfig.PingBackOff = 100 * time.Millisecond
+	s.BullyConfig.PingInterval = 100 * time.Millisecond
+	s.BullyConfig.ElectionWaitTime = 200 * time.Millisecond
+	s.BullyConfig.BullyWaitTime = time.Second
+}
+
+func (s *CoordinatorFaultsTestSuite) TearDownTest() {
+	for _, h := range s.Hosts {
+		_ = h.Close()
+	}
+}
+
+func (s *CoordinatorFaultsTestSuite) setupSigning(sessionID string, clock *memory.VirtualClock) ([]*tss.Coordinator, []tss.TssProcess) {
+	network := memory.NewSimulatedNetwork(s.faults, clock)
+	s.network = network
+	electorNetwork := memory.NewSimulatedNetwork(s.faults, clock)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+	for i, host := range s.Hosts {
+		communication := memory.NewCommunication(network, host.ID())
+		fetcher := keyshare.NewKeyshareStore(fmt.Sprintf("./test/keyshares/%d.keyshare", i))
+		signing, err := signing.NewSigning(big.NewInt(1), sessionID, host, communication, fetcher, nil, nil, nil)
+		s.Nil(err)
+
+		electorFactory := elector.NewCoordinatorElectorFactoryWithClock(
+			host, memory.NewCommunication(electorNetwork, host.ID()), s.BullyConfig, clock,
+		)
+		coordinator := tss.NewCoordinator(host, communication, electorFactory, s.MockJournal, nil)
+		coordinator.Clock = clock
+		coordinator.CoordinatorTimeout = time.Minute
+		coordinator.InitiatePeriod = time.Millisecond * 200
+		coordinator.TssTimeout = time.Hour
+		coordinators = append(coordinators, coordinator)
+		processes = append(processes, signing)
+	}
+	return coordinators, processes
+}
+
+func (s *CoordinatorFaultsTestSuite) peers() peer.IDSlice {
+	peers := peer.IDSlice{}
+	for _, h := range s.Hosts {
+		peers = append(peers, h.ID())
+	}
+	return peers
+}
+
+// awaitStatuses advances the clock by step until n session statuses are received.
+func (s *CoordinatorFaultsTestSuite) awaitStatuses(clock *memory.VirtualClock, step time.Duration, statusChn chan error, n int) []error {
+	statuses := []error{}
+	for len(statuses) < n {
+		select {
+		case err := <-statusChn:
+			statuses = append(statuses, err)
+		default:
+			clock.Advance(step)
+			time.Sleep(time.Millisecond)
+		}
+	}
+	return statuses
+}
+
+// awaitState delivers pending messages without moving the clock until all provided
+// coordinators reach one of the session states.
+func (s *CoordinatorFaultsTestSuite) awaitState(
+	clock *memory.VirtualClock, coordinators []*tss.Coordinator, sessionID string, states ...tss.SessionState,
+) {
+	for _, coordinator := range coordinators {
+		for {
+			session, _ := coordinator.Session(sessionID)
+			if slices.Contains(states, session.State) {
+				break
+			}
+			clock.Advance(0)
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func (s *CoordinatorFaultsTestSuite) Test_Signing_DelayedReorderedMessages() {
+	clock := memory.NewVirtualClock(time.Now())
+	s.faults.SetLatency(time.Millisecond*100, time.Millisecond*200)
+	coordinators, processes := s.setupSigning("signing1", clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statusChn := make(chan error, s.PartyNumber)
+	resultChn := make(chan interface{}, s.PartyNumber)
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], resultChn, statusChn)
+	}
+
+	statuses := s.awaitStatuses(clock, time.Millisecond*10, statusChn, 2)
+	s.Equal([]error{nil, nil}, statuses)
+	s.Equal(1, len(resultChn))
+}
+
+func (s *CoordinatorFaultsTestSuite) Test_Signing_RetriedAfterCoordinatorPartitioned() {
+	clock := memory.NewVirtualClock(time.Now())
+	sessionID := "signing2"
+	staticCoordinator := common.SortPeersForSession(s.peers(), sessionID)[0].ID
+	s.faults.Partition(peer.IDSlice{staticCoordinator}, common.ExcludePeers(s.peers(), peer.IDSlice{staticCoordinator}))
+	coordinators, processes := s.setupSigning(sessionID, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statusChn := make(chan error, s.PartyNumber)
+	resultChn := make(chan interface{}, s.PartyNumber)
+	participants := []*tss.Coordinator{}
+	for i, coordinator := range coordinators {
+		if s.Hosts[i].ID() != staticCoordinator {
+			participants = append(participants, coordinator)
+		}
+		go coordinator.Execute(ctx, processes[i], resultChn, statusChn)
+	}
+
+	s.awaitState(clock, participants, sessionID, tss.SessionWaitingForStart)
+	clock.Advance(coordinators[0].CoordinatorTimeout)
+
+	statuses := s.awaitStatuses(clock, time.Millisecond*10, statusChn, 2)
+	s.Equal([]error{nil, nil}, statuses)
+	s.Equal(1, len(resultChn))
+	for i, h := range s.Hosts {
+		session, _ := coordinators[i].Session(sessionID)
+		if h.ID() == staticCoordinator {
+			continue
+		}
+		s.NotEqual(staticCoordinator, session.Coordinator)
+		s.Equal(1, session.Retries)
+	}
+}
+
+func (s *CoordinatorFaultsTestSuite) Test_Signing_TimeoutWhenSigningMessagesDropped() {
+	clock := memory.NewVirtualClock(time.Now())
+	sessionID := "signing3"
+	s.faults.AddRule(memory.FaultRule{
+		MessageTypes: []comm.MessageType{comm.TssKeySignMsg},
+		Fault:        memory.Fault{Drop: true},
+	})
+	coordinators, processes := s.setupSigning(sessionID, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statusChn := make(chan error, s.PartyNumber)
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], make(chan interface{}, 1), statusChn)
+	}
+
+	// peer left out of the signing subset keeps waiting for start
+	s.awaitState(clock, coordinators, sessionID, tss.SessionRunning, tss.SessionWaitingForStart)
+	clock.Advance(coordinators[0].TssTimeout)
+
+	for i := 0; i < s.PartyNumber; i++ {
+		err := <-statusChn
+		s.NotNil(err)
+	}
+}
+
+func (s *CoordinatorFaultsTestSuite) Test_Signing_StaleFailMessageFromEarlierRunIgnored() {
+	clock := memory.NewVirtualClock(time.Now())
+	sessionID := "signing4"
+	staticCoordinator := common.SortPeersForSession(s.peers(), sessionID)[0].ID
+	coordinators, processes := s.setupSigning(sessionID, clock)
+	// fail message captured from an earlier run of the same session
+	for _, p := range common.ExcludePeers(s.peers(), peer.IDSlice{staticCoordinator}) {
+		err := s.network.Inject(p, &comm.WrappedMessage{
+			MessageType: comm.TssFailMsg,
+			SessionID:   sessionID,
+			Payload:     []byte{},
+			Timestamp:   time.Now().Add(-time.Hour),
+			Sequence:    1,
+			From:        staticCoordinator,
+		})
+		s.Nil(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statusChn := make(chan error, s.PartyNumber)
+	resultChn := make(chan interface{}, s.PartyNumber)
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], resultChn, statusChn)
+	}
+
+	statuses := s.awaitStatuses(clock, time.Millisecond*10, statusChn, 2)
+	s.Equal([]error{nil, nil}, statuses)
+	s.Equal(1, len(resultChn))
+}
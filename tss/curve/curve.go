@@ -0,0 +1,94 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package curve
+
+import (
+	"context"
+	"crypto/elliptic"
+	"sync"
+
+	"github.com/binance-chain/tss-lib/tss"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+type Curve string
+
+const (
+	Secp256k1    Curve = "secp256k1"
+	Edwards25519 Curve = "edwards25519"
+)
+
+// EC returns elliptic curve implementation used by tss-lib
+func (c Curve) EC() elliptic.Curve {
+	switch c {
+	case Edwards25519:
+		return edwards.Edwards()
+	default:
+		return btcec.S256()
+	}
+}
+
+// tss-lib keeps the curve used by tss processes and key serialization in
+// a global variable so processes on different curves can't run at the same time.
+var guard = &curveGuard{
+	active:   Secp256k1,
+	released: make(chan struct{}),
+}
+
+type curveGuard struct {
+	mu       sync.Mutex
+	active   Curve
+	users    int
+	released chan struct{}
+}
+
+// Acquire sets the tss-lib curve and blocks until all users of a different
+// curve release it. Each successful Acquire has to be followed by Release.
+func Acquire(ctx context.Context, c Curve) error {
+	for {
+		guard.mu.Lock()
+		if guard.users == 0 || guard.active == c {
+			if guard.active != c {
+				tss.SetCurve(c.EC())
+				guard.active = c
+			}
+			guard.users++
+			guard.mu.Unlock()
+			return nil
+		}
+		released := guard.released
+		guard.mu.Unlock()
+
+		select {
+		case <-released:
+			continue
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release marks the end of the curve usage.
+func Release() {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	if guard.users == 0 {
+		return
+	}
+	guard.users--
+	if guard.users == 0 {
+		close(guard.released)
+		guard.released = make(chan struct{})
+	}
+}
+
+// Active returns the curve currently used by tss-lib
+func Active() Curve {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	return guard.active
+}
@@ -0,0 +1,68 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package curve_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
+	"github.com/binance-chain/tss-lib/tss"
+	"github.com/stretchr/testify/suite"
+)
+
+type CurveTestSuite struct {
+	suite.Suite
+}
+
+func TestRunCurveTestSuite(t *testing.T) {
+	suite.Run(t, new(CurveTestSuite))
+}
+
+func (s *CurveTestSuite) Test_AcquireSameCurve() {
+	err := curve.Acquire(context.Background(), curve.Edwards25519)
+	s.Nil(err)
+	defer curve.Release()
+	err = curve.Acquire(context.Background(), curve.Edwards25519)
+	s.Nil(err)
+	defer curve.Release()
+
+	s.Equal(curve.Edwards25519, curve.Active())
+	s.Equal(curve.Edwards25519.EC(), tss.EC())
+}
+
+func (s *CurveTestSuite) Test_AcquireDifferentCurve_ContextCanceled() {
+	err := curve.Acquire(context.Background(), curve.Edwards25519)
+	s.Nil(err)
+	defer curve.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	err = curve.Acquire(ctx, curve.Secp256k1)
+
+	s.NotNil(err)
+	s.Equal(curve.Edwards25519, curve.Active())
+}
+
+func (s *CurveTestSuite) Test_AcquireDifferentCurve_WaitsForRelease() {
+	err := curve.Acquire(context.Background(), curve.Edwards25519)
+	s.Nil(err)
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- curve.Acquire(context.Background(), curve.Secp256k1)
+	}()
+	select {
+	case <-acquired:
+		s.Fail("curve acquired before release")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	curve.Release()
+	s.Nil(<-acquired)
+	s.Equal(curve.Secp256k1, curve.Active())
+	s.Equal(curve.Secp256k1.EC(), tss.EC())
+	curve.Release()
+}
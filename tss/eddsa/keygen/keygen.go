@@ -0,0 +1,161 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package keygen
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
+	"github.com/binance-chain/tss-lib/eddsa/keygen"
+	"github.com/binance-chain/tss-lib/tss"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog/log"
+)
+
+// messageTypes are tss-lib messages exchanged during the process
+var messageTypes = []tss.MessageContent{
+	&keygen.KGRound1Message{},
+	&keygen.KGRound2Message1{},
+	&keygen.KGRound2Message2{},
+}
+
+type SaveDataStorer interface {
+	StoreKeyshare(keyshare keyshare.EdDSAKeyshare, sessionID string) error
+	LockKeyshare()
+	UnlockKeyshare()
+	GetKeyshare() (keyshare.EdDSAKeyshare, error)
+}
+
+type Keygen struct {
+	common.BaseTss
+	storer         SaveDataStorer
+	threshold      int
+	subscriptionID comm.SubscriptionID
+}
+
+func NewKeygen(
+	sessionID string,
+	threshold int,
+	host host.Host,
+	comm comm.Communication,
+	storer SaveDataStorer,
+) *Keygen {
+	partyStore := make(map[string]*tss.PartyID)
+	return &Keygen{
+		BaseTss: common.BaseTss{
+			PartyStore:    partyStore,
+			Host:          host,
+			Communication: comm,
+			Peers:         host.Peerstore().Peers(),
+			SID:           sessionID,
+			Log:           log.With().Str("SessionID", sessionID).Str("Process", "eddsa-keygen").Logger(),
+			MessageTypes:  messageTypes,
+			Cancel:        func() {},
+		},
+		storer:    storer,
+		threshold: threshold,
+	}
+}
+
+// Start initializes the EdDSA keygen party and starts the keygen tss process.
+//
+// Should be run only after all the participating parties are ready.
+func (k *Keygen) Start(
+	ctx context.Context,
+	coordinator bool,
+	resultChn chan interface{},
+	errChn chan error,
+	params []byte,
+) {
+	k.ErrChn = errChn
+	ctx, k.Cancel = context.WithCancel(ctx)
+	k.storer.LockKeyshare()
+	if err := k.AcquireCurve(ctx, curve.Edwards25519); err != nil {
+		return
+	}
+
+	parties := common.PartiesFromPeers(k.Host.Peerstore().Peers())
+	k.PopulatePartyStore(parties)
+
+	pCtx := tss.NewPeerContext(parties)
+	tssParams := tss.NewParameters(pCtx, k.PartyStore[k.Host.ID().Pretty()], len(parties), k.threshold)
+
+	outChn := make(chan tss.Message)
+	msgChn := make(chan *comm.WrappedMessage)
+	endChn := make(chan keygen.LocalPartySaveData)
+
+	k.subscriptionID = k.Communication.Subscribe(k.SessionID(), comm.TssEdDSAKeyGenMsg, msgChn)
+
+	go k.ProcessOutboundMessages(ctx, outChn, comm.TssEdDSAKeyGenMsg)
+	go k.ProcessInboundMessages(ctx, msgChn)
+	go k.processEndMessage(ctx, endChn)
+
+	k.Party = keygen.NewLocalParty(tssParams, outChn, endChn)
+
+	k.Log.Info().Msgf("Started EdDSA keygen process")
+	go func() {
+		err := k.Party.Start()
+		if err != nil {
+			k.ErrChn <- err
+		}
+	}()
+}
+
+// Stop ends all subscriptions created when starting the tss process and unlocks keyshare.
+func (k *Keygen) Stop() {
+	k.Communication.UnSubscribe(k.subscriptionID)
+	k.storer.UnlockKeyshare()
+	k.Cancel()
+	k.ReleaseCurve()
+}
+
+// Ready returns true if all parties from the peerstore are ready.
+// Error is returned if excluded peers exist as we need all peers to participate
+// in keygen process.
+func (k *Keygen) Ready(readyMap map[peer.ID]bool, excludedPeers []peer.ID) (bool, error) {
+	if len(excludedPeers) > 0 {
+		return false, errors.New("error")
+	}
+
+	return len(readyMap) == len(k.Host.Peerstore().Peers()), nil
+}
+
+// ValidCoordinators returns all peers in peerstore
+func (k *Keygen) ValidCoordinators() []peer.ID {
+	return k.Host.Peerstore().Peers()
+}
+
+func (k *Keygen) StartParams(readyMap map[peer.ID]bool) []byte {
+	return []byte{}
+}
+
+// processEndMessage waits for the final message with generated key share and stores it locally.
+func (k *Keygen) processEndMessage(ctx context.Context, endChn chan keygen.LocalPartySaveData) {
+	for {
+		select {
+		case key := <-endChn:
+			{
+				k.Log.Info().Msg("Generated EdDSA key share")
+
+				keyshare := keyshare.NewEdDSAKeyshare(key, k.threshold, k.Peers)
+				err := k.storer.StoreKeyshare(keyshare, k.SessionID())
+				k.ErrChn <- err
+				return
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+func (k *Keygen) Retryable() bool {
+	return false
+}
@@ -0,0 +1,111 @@
+package keygen_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/eddsa/keygen"
+	mock_keygen "github.com/ChainSafe/sygma-relayer/tss/eddsa/keygen/mock"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeygenTestSuite struct {
+	tsstest.CoordinatorTestSuite
+}
+
+func TestRunKeygenTestSuite(t *testing.T) {
+	suite.Run(t, new(KeygenTestSuite))
+}
+
+func (s *KeygenTestSuite) Test_ValidKeygenProcess() {
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+	stores := []*keyshare.EdDSAKeyshareStore{}
+
+	dir := s.T().TempDir()
+	for i, host := range s.CoordinatorTestSuite.Hosts {
+		communication := tsstest.TestCommunication{
+			Host:          host,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+		communicationMap[host.ID()] = &communication
+		store := keyshare.NewKeyshareStore(filepath.Join(dir, fmt.Sprintf("%d.keyshare", i))).EdDSAStore()
+		keygen := keygen.NewKeygen("eddsa-keygen", s.Threshold, host, &communication, store)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
+		processes = append(processes, keygen)
+		stores = append(stores, store)
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	status := make(chan error, s.PartyNumber)
+	ctx, cancel := context.WithCancel(context.Background())
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], nil, status)
+	}
+
+	for i := 0; i < s.PartyNumber; i++ {
+		err := <-status
+		s.Nil(err)
+	}
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+
+	key, err := stores[0].GetKeyshare()
+	s.Nil(err)
+	s.Equal(s.Threshold, key.Threshold)
+	for _, store := range stores[1:] {
+		otherKey, err := store.GetKeyshare()
+		s.Nil(err)
+		s.True(key.Key.EDDSAPub.Equals(otherKey.Key.EDDSAPub))
+	}
+}
+
+func (s *KeygenTestSuite) Test_KeygenTimeout() {
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+
+	mockStorer := mock_keygen.NewMockSaveDataStorer(s.GomockController)
+	for _, host := range s.CoordinatorTestSuite.Hosts {
+		communication := tsstest.TestCommunication{
+			Host:          host,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+		communicationMap[host.ID()] = &communication
+		keygen := keygen.NewKeygen("eddsa-keygen2", s.Threshold, host, &communication, mockStorer)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		coordinator := tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil)
+		coordinator.TssTimeout = time.Millisecond
+		coordinators = append(coordinators, coordinator)
+		processes = append(processes, keygen)
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	mockStorer.EXPECT().LockKeyshare().AnyTimes()
+	mockStorer.EXPECT().UnlockKeyshare().AnyTimes()
+	mockStorer.EXPECT().StoreKeyshare(gomock.Any(), gomock.Any()).Times(0)
+	status := make(chan error, s.PartyNumber)
+	ctx, cancel := context.WithCancel(context.Background())
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], nil, status)
+	}
+
+	for i := 0; i < s.PartyNumber; i++ {
+		err := <-status
+		s.NotNil(err)
+	}
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
@@ -0,0 +1,411 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package resharing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
+	"github.com/binance-chain/tss-lib/eddsa/keygen"
+	"github.com/binance-chain/tss-lib/eddsa/resharing"
+	"github.com/binance-chain/tss-lib/tss"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+// messageTypes are tss-lib messages exchanged during the process
+var messageTypes = []tss.MessageContent{
+	&resharing.DGRound1Message{},
+	&resharing.DGRound2Message{},
+	&resharing.DGRound3Message1{},
+	&resharing.DGRound3Message2{},
+	&resharing.DGRound4Message{},
+}
+
+type startParams struct {
+	OldThreshold int       `json:"oldThreshold"`
+	OldSubset    []peer.ID `json:"oldSubset"`
+}
+
+type SaveDataStorer interface {
+	GetKeyshare() (keyshare.EdDSAKeyshare, error)
+	StoreKeyshare(keyshare keyshare.EdDSAKeyshare, sessionID string) error
+	LockKeyshare()
+	UnlockKeyshare()
+}
+
+// Resharing reshares the EdDSA key to all peers from the peerstore.
+//
+// Unlike the ECDSA resharing, tss-lib EdDSA resharing doesn't support a party being
+// in the old and the new committee at the same time, so peers from both committees run
+// a separate old and new committee party and messages are routed between them by type.
+type Resharing struct {
+	common.BaseTss
+	key            keyshare.EdDSAKeyshare
+	subscriptionID comm.SubscriptionID
+	storer         SaveDataStorer
+	newThreshold   int
+
+	oldParty      tss.Party
+	newParty      tss.Party
+	oldPartyStore map[string]*tss.PartyID
+	newPartyStore map[string]*tss.PartyID
+}
+
+func NewResharing(
+	sessionID string,
+	threshold int,
+	host host.Host,
+	comm comm.Communication,
+	storer SaveDataStorer,
+) *Resharing {
+	storer.LockKeyshare()
+	var key keyshare.EdDSAKeyshare
+	key, err := storer.GetKeyshare()
+	if err != nil {
+		// empty key for parties that don't have one
+		key = keyshare.EdDSAKeyshare{}
+	}
+
+	return &Resharing{
+		BaseTss: common.BaseTss{
+			PartyStore:    make(map[string]*tss.PartyID),
+			Host:          host,
+			Communication: comm,
+			Peers:         host.Peerstore().Peers(),
+			SID:           sessionID,
+			Log:           log.With().Str("SessionID", sessionID).Str("Process", "eddsa-resharing").Logger(),
+			MessageTypes:  messageTypes,
+			Cancel:        func() {},
+		},
+		key:           key,
+		storer:        storer,
+		newThreshold:  threshold,
+		oldPartyStore: make(map[string]*tss.PartyID),
+		newPartyStore: make(map[string]*tss.PartyID),
+	}
+}
+
+// Start initializes the EdDSA resharing parties and starts the resharing tss process.
+// Params contains peer subset that leaders sends with start message.
+func (r *Resharing) Start(
+	ctx context.Context,
+	coordinator bool,
+	resultChn chan interface{},
+	errChn chan error,
+	params []byte,
+) {
+	r.ErrChn = errChn
+	ctx, r.Cancel = context.WithCancel(ctx)
+
+	startParams, err := r.unmarshallStartParams(params)
+	if err != nil {
+		r.ErrChn <- err
+		return
+	}
+
+	if err := r.AcquireCurve(ctx, curve.Edwards25519); err != nil {
+		return
+	}
+
+	oldParties := common.PartiesFromPeers(startParams.OldSubset)
+	newParties := newCommitteePartiesFromPeers(r.Host.Peerstore().Peers())
+	for _, party := range oldParties {
+		r.oldPartyStore[party.Id] = party
+	}
+	for _, party := range newParties {
+		r.newPartyStore[party.Id] = party
+	}
+	oldCtx := tss.NewPeerContext(oldParties)
+	newCtx := tss.NewPeerContext(newParties)
+
+	outChn := make(chan tss.Message)
+	msgChn := make(chan *comm.WrappedMessage)
+	oldEndChn := make(chan keygen.LocalPartySaveData, 1)
+	endChn := make(chan keygen.LocalPartySaveData)
+	hostID := r.Host.ID().Pretty()
+	if oldPartyID, ok := r.oldPartyStore[hostID]; ok {
+		tssParams := tss.NewReSharingParameters(
+			oldCtx, newCtx, oldPartyID, len(oldParties), startParams.OldThreshold, len(newParties), r.newThreshold,
+		)
+		r.oldParty = resharing.NewLocalParty(tssParams, r.key.Key, outChn, oldEndChn)
+	}
+	if newPartyID, ok := r.newPartyStore[hostID]; ok {
+		tssParams := tss.NewReSharingParameters(
+			oldCtx, newCtx, newPartyID, len(oldParties), startParams.OldThreshold, len(newParties), r.newThreshold,
+		)
+		r.newParty = resharing.NewLocalParty(tssParams, keygen.NewLocalPartySaveData(len(newParties)), outChn, endChn)
+	}
+
+	r.subscriptionID = r.Communication.Subscribe(r.SessionID(), comm.TssEdDSAReshareMsg, msgChn)
+	go r.processOutboundMessages(ctx, outChn)
+	go r.processInboundMessages(ctx, msgChn)
+	go r.processEndMessage(ctx, endChn)
+
+	r.Log.Info().Msgf("Started EdDSA resharing process")
+	for _, party := range []tss.Party{r.newParty, r.oldParty} {
+		if party == nil {
+			continue
+		}
+
+		go func(party tss.Party) {
+			err := party.Start()
+			if err != nil {
+				r.ErrChn <- err
+			}
+		}(party)
+	}
+}
+
+// Stop ends all subscriptions created when starting the tss process and unlocks keyshare.
+func (r *Resharing) Stop() {
+	log.Info().Str("sessionID", r.SessionID()).Msgf("Stopping tss process.")
+	r.Communication.UnSubscribe(r.subscriptionID)
+	r.storer.UnlockKeyshare()
+	r.Cancel()
+	r.ReleaseCurve()
+}
+
+// Ready returns true if all parties from peerstore are ready
+func (r *Resharing) Ready(readyMap map[peer.ID]bool, excludedPeers []peer.ID) (bool, error) {
+	return len(readyMap) == len(r.Host.Peerstore().Peers()), nil
+}
+
+// ValidCoordinators returns only peers that have a valid keyshare from the previous resharing
+func (r *Resharing) ValidCoordinators() []peer.ID {
+	return r.key.Peers
+}
+
+// StartParams returns threshold and peer subset from the old key to share with new parties.
+func (r *Resharing) StartParams(readyMap map[peer.ID]bool) []byte {
+	startParams := &startParams{
+		OldThreshold: r.key.Threshold,
+		OldSubset:    r.key.Peers,
+	}
+	paramBytes, _ := json.Marshal(startParams)
+	return paramBytes
+}
+
+func (r *Resharing) unmarshallStartParams(paramBytes []byte) (startParams, error) {
+	var startParams startParams
+	err := json.Unmarshal(paramBytes, &startParams)
+	if err != nil {
+		return startParams, err
+	}
+
+	err = r.validateStartParams(startParams)
+	if err != nil {
+		return startParams, err
+	}
+
+	return startParams, nil
+}
+
+func (r *Resharing) validateStartParams(params startParams) error {
+	if params.OldThreshold <= 0 {
+		return errors.New("threshold too small")
+	}
+	if len(params.OldSubset) < params.OldThreshold {
+		return errors.New("threshold bigger then subset")
+	}
+
+	slices.Sort(params.OldSubset)
+	slices.Sort(r.key.Peers)
+	// if relayer is already part of the active subset, check if peer subset
+	// in starting params is same as one saved in keyshare
+	if len(r.key.Peers) != 0 && !slices.Equal(params.OldSubset, r.key.Peers) {
+		return errors.New("invalid peers subset in start params")
+	}
+
+	return nil
+}
+
+// processOutboundMessages sends messages from both local parties to the peers of the
+// receiving parties. Messages to the other local party are delivered directly.
+func (r *Resharing) processOutboundMessages(ctx context.Context, outChn chan tss.Message) {
+	for {
+		select {
+		case msg := <-outChn:
+			{
+				r.Log.Debug().Msg(msg.String())
+				wireBytes, routing, err := msg.WireBytes()
+				if err != nil {
+					r.ErrChn <- err
+					return
+				}
+
+				msgBytes, err := common.MarshalTssMessage(wireBytes, routing.IsBroadcast)
+				if err != nil {
+					r.ErrChn <- err
+					return
+				}
+
+				peers, err := common.PeersFromParties(msg.GetTo())
+				if err != nil {
+					r.ErrChn <- err
+					return
+				}
+
+				peers = uniquePeers(peers)
+				if slices.Contains(peers, r.Host.ID()) {
+					go r.processMessage(&comm.WrappedMessage{
+						MessageType: comm.TssEdDSAReshareMsg,
+						SessionID:   r.SessionID(),
+						Payload:     msgBytes,
+						From:        r.Host.ID(),
+					})
+				}
+
+				peers = common.ExcludePeers(peers, []peer.ID{r.Host.ID()})
+				r.Log.Debug().Msgf("sending message to %s", peers)
+				go r.Communication.Broadcast(peers, msgBytes, comm.TssEdDSAReshareMsg, r.SessionID(), r.ErrChn)
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+// processInboundMessages updates local parties with messages received from peers.
+func (r *Resharing) processInboundMessages(ctx context.Context, msgChn chan *comm.WrappedMessage) {
+	for {
+		select {
+		case wMsg := <-msgChn:
+			{
+				go r.processMessage(wMsg)
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+// processMessage routes the message to the committee party it was sent to
+// from the committee party of the sender.
+func (r *Resharing) processMessage(wMsg *comm.WrappedMessage) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.ErrChn <- errors.New("invalid resharing message")
+		}
+	}()
+	r.Log.Debug().Msgf("processed inbound message from %s", wMsg.From)
+
+	msg, err := common.UnmarshalTssMessage(wMsg.Payload)
+	if err != nil {
+		r.ErrChn <- err
+		return
+	}
+
+	oldFrom := r.oldPartyStore[wMsg.From.Pretty()]
+	newFrom := r.newPartyStore[wMsg.From.Pretty()]
+	from := newFrom
+	if from == nil {
+		from = oldFrom
+	}
+	if from == nil {
+		r.ErrChn <- fmt.Errorf("message from unknown peer %s", wMsg.From)
+		return
+	}
+
+	content, err := common.ParseWireMessage(msg.MsgBytes, from, msg.IsBroadcast, r.MessageTypes)
+	if err != nil {
+		r.ErrChn <- err
+		return
+	}
+
+	var parties []tss.Party
+	switch content.Content().(type) {
+	case *resharing.DGRound2Message:
+		parties = []tss.Party{r.oldParty}
+	case *resharing.DGRound4Message:
+		parties = []tss.Party{r.oldParty, r.newParty}
+	default:
+		// remaining messages are sent from the old committee party
+		parties = []tss.Party{r.newParty}
+		if from != oldFrom {
+			if oldFrom == nil {
+				r.ErrChn <- fmt.Errorf("message from peer %s not in old committee", wMsg.From)
+				return
+			}
+
+			content, err = common.ParseWireMessage(msg.MsgBytes, oldFrom, msg.IsBroadcast, r.MessageTypes)
+			if err != nil {
+				r.ErrChn <- err
+				return
+			}
+		}
+	}
+
+	for _, party := range parties {
+		if party == nil {
+			continue
+		}
+
+		ok, tssErr := party.Update(content)
+		if !ok {
+			r.ErrChn <- tssErr
+		}
+	}
+}
+
+// processEndMessage stores reshared key from the new committee party.
+func (r *Resharing) processEndMessage(ctx context.Context, endChn chan keygen.LocalPartySaveData) {
+	for {
+		select {
+		case key := <-endChn:
+			{
+				r.Log.Info().Msg("Successfully reshared EdDSA key")
+
+				keyshare := keyshare.NewEdDSAKeyshare(key, r.newThreshold, r.Peers)
+				err := r.storer.StoreKeyshare(keyshare, r.SessionID())
+				r.ErrChn <- err
+				return
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+func (r *Resharing) Retryable() bool {
+	return false
+}
+
+// newCommitteePartiesFromPeers creates new committee parties with keys different from
+// old committee parties of the same peers
+func newCommitteePartiesFromPeers(peers peer.IDSlice) tss.SortedPartyIDs {
+	unsortedParties := make(tss.UnSortedPartyIDs, len(peers))
+	for i, peer := range peers {
+		key := new(big.Int).SetBytes(append([]byte(peer.String()), 1))
+		unsortedParties[i] = tss.NewPartyID(peer.String(), peer.String(), key)
+	}
+
+	return tss.SortPartyIDs(unsortedParties)
+}
+
+func uniquePeers(peers []peer.ID) []peer.ID {
+	unique := []peer.ID{}
+	for _, peer := range peers {
+		if !slices.Contains(unique, peer) {
+			unique = append(unique, peer)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,93 @@
+package resharing_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/eddsa/resharing"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResharingTestSuite struct {
+	tsstest.CoordinatorTestSuite
+}
+
+func TestRunResharingTestSuite(t *testing.T) {
+	suite.Run(t, new(ResharingTestSuite))
+}
+
+func (s *ResharingTestSuite) Test_ValidResharingProcess_OldAndNewSubset() {
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+	stores := []*keyshare.EdDSAKeyshareStore{}
+
+	hosts := []host.Host{}
+	for i := 0; i < s.PartyNumber+1; i++ {
+		host, _ := tsstest.NewHost(i)
+		hosts = append(hosts, host)
+	}
+	for _, host := range hosts {
+		for _, peer := range hosts {
+			host.Peerstore().AddAddr(peer.ID(), peer.Addrs()[0], peerstore.PermanentAddrTTL)
+		}
+	}
+
+	dir := s.T().TempDir()
+	for i, host := range hosts {
+		communication := tsstest.TestCommunication{
+			Host:          host,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+		communicationMap[host.ID()] = &communication
+		path := filepath.Join(dir, fmt.Sprintf("%d.keyshare", i))
+		if i < s.PartyNumber {
+			kb, err := ioutil.ReadFile(fmt.Sprintf("../../test/keyshares/eddsa/%d.keyshare", i))
+			s.Nil(err)
+			s.Nil(ioutil.WriteFile(path, kb, 0644))
+		}
+		store := keyshare.NewKeyshareStore(path).EdDSAStore()
+		resharing := resharing.NewResharing("eddsa-resharing", 2, host, &communication, store)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
+		processes = append(processes, resharing)
+		stores = append(stores, store)
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	statusChn := make(chan error, s.PartyNumber+1)
+	resultChn := make(chan interface{})
+	ctx, cancel := context.WithCancel(context.Background())
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], resultChn, statusChn)
+	}
+
+	for i := 0; i < s.PartyNumber+1; i++ {
+		err := <-statusChn
+		s.Nil(err)
+	}
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+
+	oldKey, err := keyshare.NewKeyshareStore("../../test/keyshares/eddsa/0.keyshare").EdDSAStore().GetKeyshare()
+	s.Nil(err)
+	for _, store := range stores {
+		key, err := store.GetKeyshare()
+		s.Nil(err)
+		s.Equal(2, key.Threshold)
+		s.Equal(len(hosts), len(key.Peers))
+		s.True(oldKey.Key.EDDSAPub.Equals(key.Key.EDDSAPub))
+	}
+}
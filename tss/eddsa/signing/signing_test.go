@@ -0,0 +1,117 @@
+package signing_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/eddsa/signing"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
+	eddsaSigning "github.com/binance-chain/tss-lib/eddsa/signing"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type SigningTestSuite struct {
+	tsstest.CoordinatorTestSuite
+}
+
+func TestRunSigningTestSuite(t *testing.T) {
+	suite.Run(t, new(SigningTestSuite))
+}
+
+func (s *SigningTestSuite) Test_ValidSigningProcess() {
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+
+	msg := []byte("Message")
+	var key keyshare.EdDSAKeyshare
+	for i, host := range s.Hosts {
+		communication := tsstest.TestCommunication{
+			Host:          host,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+		communicationMap[host.ID()] = &communication
+		fetcher := keyshare.NewKeyshareStore(fmt.Sprintf("../../test/keyshares/eddsa/%d.keyshare", i)).EdDSAStore()
+		key, _ = fetcher.GetKeyshare()
+
+		signing, err := signing.NewSigning(msg, "eddsa-signing1", host, &communication, fetcher, nil)
+		if err != nil {
+			panic(err)
+		}
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
+		processes = append(processes, signing)
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	statusChn := make(chan error, s.PartyNumber)
+	resultChn := make(chan interface{})
+	ctx, cancel := context.WithCancel(context.Background())
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], resultChn, statusChn)
+	}
+
+	err := <-statusChn
+	s.Nil(err)
+	sig := (<-resultChn).(*eddsaSigning.SignatureData)
+	pk := edwards.PublicKey{
+		Curve: edwards.Edwards(),
+		X:     key.Key.EDDSAPub.X(),
+		Y:     key.Key.EDDSAPub.Y(),
+	}
+	s.True(edwards.Verify(
+		&pk,
+		msg,
+		new(big.Int).SetBytes(sig.Signature.R),
+		new(big.Int).SetBytes(sig.Signature.S),
+	))
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *SigningTestSuite) Test_SigningTimeout() {
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+
+	for i, host := range s.Hosts {
+		communication := tsstest.TestCommunication{
+			Host:          host,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+		communicationMap[host.ID()] = &communication
+		fetcher := keyshare.NewKeyshareStore(fmt.Sprintf("../../test/keyshares/eddsa/%d.keyshare", i)).EdDSAStore()
+
+		signing, err := signing.NewSigning([]byte("Message"), "eddsa-signing2", host, &communication, fetcher, nil)
+		if err != nil {
+			panic(err)
+		}
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		coordinator := tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil)
+		coordinator.TssTimeout = time.Nanosecond
+		coordinators = append(coordinators, coordinator)
+		processes = append(processes, signing)
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	statusChn := make(chan error, s.PartyNumber)
+	resultChn := make(chan interface{})
+	ctx, cancel := context.WithCancel(context.Background())
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], resultChn, statusChn)
+	}
+
+	err := <-statusChn
+	s.NotNil(err)
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
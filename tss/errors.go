@@ -24,3 +24,11 @@ type SubsetError struct {
 func (se *SubsetError) Error() string {
 	return fmt.Sprintf("party %s not in signing subset", se.Peer)
 }
+
+type ProposalMismatchError struct {
+	Err error
+}
+
+func (pe *ProposalMismatchError) Error() string {
+	return fmt.Sprintf("proposals from start message refused: %s", pe.Err)
+}
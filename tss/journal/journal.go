@@ -0,0 +1,366 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
+	"github.com/syndtr/goleveldb/leveldb"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	unfinishedSessionsKey = "tss:sessions:unfinished"
+	finishedSessionsKey   = "tss:sessions:finished"
+
+	// DefaultFinishedSessionRetention is the period finished sessions are kept in the journal
+	DefaultFinishedSessionRetention = 24 * time.Hour
+)
+
+type ProcessType string
+
+const (
+	KeygenProcess    ProcessType = "keygen"
+	ResharingProcess ProcessType = "resharing"
+	SigningProcess   ProcessType = "signing"
+)
+
+type Phase string
+
+const (
+	// PhasePending session was created but coordinator has not started it yet
+	PhasePending Phase = "pending"
+	// PhaseRunning tss process is running
+	PhaseRunning Phase = "running"
+	// PhaseRetrying tss process failed and is waiting to be restarted
+	PhaseRetrying Phase = "retrying"
+	// PhaseFinished tss process ended, result is stored as session outcome
+	PhaseFinished Phase = "finished"
+)
+
+type Outcome string
+
+const (
+	OutcomeUnknown Outcome = ""
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Session is a journal entry describing a single tss session
+type Session struct {
+	SessionID string               `json:"sessionID"`
+	Process   ProcessType          `json:"process"`
+	Phase     Phase                `json:"phase"`
+	Outcome   Outcome              `json:"outcome"`
+	Error     string               `json:"error,omitempty"`
+	Proposals []*proposal.Proposal `json:"proposals,omitempty"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+type KeyValueReaderWriter interface {
+	GetByKey(key []byte) ([]byte, error)
+	SetByKey(key []byte, value []byte) error
+}
+
+// SessionJournal durably records tss sessions so that unfinished sessions
+// can be restarted after the relayer restarts.
+type SessionJournal struct {
+	// FinishedSessionRetention is the period after which finished sessions
+	// are removed from the journal
+	FinishedSessionRetention time.Duration
+
+	mu sync.Mutex
+	db KeyValueReaderWriter
+}
+
+func NewSessionJournal(db KeyValueReaderWriter) *SessionJournal {
+	return &SessionJournal{
+		FinishedSessionRetention: DefaultFinishedSessionRetention,
+		db:                       db,
+	}
+}
+
+// RecordSession stores a new session into the journal in pending phase.
+// If the session already exists, proposals are updated and the session is
+// moved back to pending phase.
+func (j *SessionJournal) RecordSession(sessionID string, process ProcessType, proposals []*proposal.Proposal) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	session, err := j.session(sessionID)
+	if err != nil {
+		if !errors.Is(err, leveldb.ErrNotFound) {
+			return err
+		}
+
+		session = Session{
+			SessionID: sessionID,
+			Process:   process,
+			CreatedAt: now,
+		}
+	}
+	session.Phase = PhasePending
+	session.Outcome = OutcomeUnknown
+	session.Error = ""
+	session.Proposals = proposals
+	session.UpdatedAt = now
+
+	err = j.storeSession(session)
+	if err != nil {
+		return err
+	}
+	err = j.removeFinished(sessionID)
+	if err != nil {
+		return err
+	}
+
+	return j.addUnfinished(sessionID)
+}
+
+// UpdatePhase moves an existing session to the provided phase.
+func (j *SessionJournal) UpdatePhase(sessionID string, phase Phase) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	session, err := j.session(sessionID)
+	if err != nil {
+		return err
+	}
+
+	session.Phase = phase
+	session.UpdatedAt = time.Now()
+	return j.storeSession(session)
+}
+
+// Finish marks session as finished with outcome based on the provided error.
+// Sessions finished longer than the retention period ago are removed from the journal.
+func (j *SessionJournal) Finish(sessionID string, sessionErr error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	session, err := j.session(sessionID)
+	if err != nil {
+		return err
+	}
+
+	session.Phase = PhaseFinished
+	session.UpdatedAt = time.Now()
+	if sessionErr != nil {
+		session.Outcome = OutcomeFailure
+		session.Error = sessionErr.Error()
+	} else {
+		session.Outcome = OutcomeSuccess
+		session.Error = ""
+	}
+
+	err = j.storeSession(session)
+	if err != nil {
+		return err
+	}
+	err = j.removeUnfinished(sessionID)
+	if err != nil {
+		return err
+	}
+
+	err = j.prune()
+	if err != nil {
+		return err
+	}
+	return j.addFinished(sessionID)
+}
+
+// Session fetches session from the journal by session ID.
+func (j *SessionJournal) Session(sessionID string) (Session, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.session(sessionID)
+}
+
+// UnfinishedSessions returns all sessions of the provided process type that
+// have not reached the finished phase.
+func (j *SessionJournal) UnfinishedSessions(process ProcessType) ([]Session, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	sessionIDs, err := j.unfinished()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0)
+	for _, sessionID := range sessionIDs {
+		session, err := j.session(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session.Process != process {
+			continue
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func (j *SessionJournal) session(sessionID string) (Session, error) {
+	s := Session{}
+	sb, err := j.db.GetByKey(sessionKey(sessionID))
+	if err != nil {
+		return s, err
+	}
+	if len(sb) == 0 {
+		return s, leveldb.ErrNotFound
+	}
+
+	err = json.Unmarshal(sb, &s)
+	if err != nil {
+		return s, fmt.Errorf("error on unmarshaling session %s: %w", sessionID, err)
+	}
+
+	return s, nil
+}
+
+func (j *SessionJournal) storeSession(session Session) error {
+	sb, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	return j.db.SetByKey(sessionKey(session.SessionID), sb)
+}
+
+// removeSession overwrites session data as the key value store
+// does not support deletion
+func (j *SessionJournal) removeSession(sessionID string) error {
+	return j.db.SetByKey(sessionKey(sessionID), []byte{})
+}
+
+// prune removes finished sessions older than the retention period
+func (j *SessionJournal) prune() error {
+	sessionIDs, err := j.sessionIDs(finishedSessionsKey)
+	if err != nil {
+		return err
+	}
+
+	kept := []string{}
+	for _, sessionID := range sessionIDs {
+		session, err := j.session(sessionID)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		if time.Since(session.UpdatedAt) <= j.FinishedSessionRetention {
+			kept = append(kept, sessionID)
+			continue
+		}
+
+		err = j.removeSession(sessionID)
+		if err != nil {
+			return err
+		}
+	}
+	if len(kept) == len(sessionIDs) {
+		return nil
+	}
+
+	return j.storeSessionIDs(finishedSessionsKey, kept)
+}
+
+func (j *SessionJournal) unfinished() ([]string, error) {
+	return j.sessionIDs(unfinishedSessionsKey)
+}
+
+func (j *SessionJournal) storeUnfinished(sessionIDs []string) error {
+	return j.storeSessionIDs(unfinishedSessionsKey, sessionIDs)
+}
+
+func (j *SessionJournal) addFinished(sessionID string) error {
+	sessionIDs, err := j.sessionIDs(finishedSessionsKey)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(sessionIDs, sessionID) {
+		return nil
+	}
+
+	return j.storeSessionIDs(finishedSessionsKey, append(sessionIDs, sessionID))
+}
+
+func (j *SessionJournal) removeFinished(sessionID string) error {
+	sessionIDs, err := j.sessionIDs(finishedSessionsKey)
+	if err != nil {
+		return err
+	}
+
+	index := slices.Index(sessionIDs, sessionID)
+	if index == -1 {
+		return nil
+	}
+
+	return j.storeSessionIDs(finishedSessionsKey, slices.Delete(sessionIDs, index, index+1))
+}
+
+func (j *SessionJournal) sessionIDs(key string) ([]string, error) {
+	sessionIDs := []string{}
+	b, err := j.db.GetByKey([]byte(key))
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return sessionIDs, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &sessionIDs)
+	return sessionIDs, err
+}
+
+func (j *SessionJournal) storeSessionIDs(key string, sessionIDs []string) error {
+	b, err := json.Marshal(sessionIDs)
+	if err != nil {
+		return err
+	}
+
+	return j.db.SetByKey([]byte(key), b)
+}
+
+func (j *SessionJournal) addUnfinished(sessionID string) error {
+	sessionIDs, err := j.unfinished()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(sessionIDs, sessionID) {
+		return nil
+	}
+
+	return j.storeUnfinished(append(sessionIDs, sessionID))
+}
+
+func (j *SessionJournal) removeUnfinished(sessionID string) error {
+	sessionIDs, err := j.unfinished()
+	if err != nil {
+		return err
+	}
+
+	index := slices.Index(sessionIDs, sessionID)
+	if index == -1 {
+		return nil
+	}
+
+	return j.storeUnfinished(slices.Delete(sessionIDs, index, index+1))
+}
+
+func sessionKey(sessionID string) []byte {
+	return []byte(fmt.Sprintf("tss:session:%s", sessionID))
+}
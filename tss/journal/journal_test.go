@@ -0,0 +1,160 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package journal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
+	"github.com/ChainSafe/chainbridge-core/lvldb"
+	"github.com/ChainSafe/sygma-relayer/tss/journal"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionJournalTestSuite struct {
+	suite.Suite
+	db      *lvldb.LVLDB
+	journal *journal.SessionJournal
+}
+
+func TestRunSessionJournalTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionJournalTestSuite))
+}
+
+func (s *SessionJournalTestSuite) SetupTest() {
+	db, err := lvldb.NewLvlDB(s.T().TempDir())
+	if err != nil {
+		panic(err)
+	}
+	s.db = db
+	s.journal = journal.NewSessionJournal(db)
+}
+func (s *SessionJournalTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *SessionJournalTestSuite) Test_MissingSession() {
+	_, err := s.journal.Session("signing-1")
+	s.NotNil(err)
+
+	err = s.journal.UpdatePhase("signing-1", journal.PhaseRunning)
+	s.NotNil(err)
+}
+
+func (s *SessionJournalTestSuite) Test_RecordSession() {
+	proposals := []*proposal.Proposal{
+		{Source: 1, Destination: 2, DepositNonce: 3, Data: []byte{1}},
+	}
+
+	err := s.journal.RecordSession("signing-1", journal.SigningProcess, proposals)
+	s.Nil(err)
+
+	session, err := s.journal.Session("signing-1")
+	s.Nil(err)
+	s.Equal(journal.SigningProcess, session.Process)
+	s.Equal(journal.PhasePending, session.Phase)
+	s.Equal(journal.OutcomeUnknown, session.Outcome)
+	s.Equal(proposals, session.Proposals)
+}
+
+func (s *SessionJournalTestSuite) Test_UpdatePhase() {
+	_ = s.journal.RecordSession("keygen-1", journal.KeygenProcess, nil)
+
+	err := s.journal.UpdatePhase("keygen-1", journal.PhaseRetrying)
+	s.Nil(err)
+
+	session, _ := s.journal.Session("keygen-1")
+	s.Equal(journal.PhaseRetrying, session.Phase)
+}
+
+func (s *SessionJournalTestSuite) Test_UnfinishedSessions() {
+	_ = s.journal.RecordSession("signing-1", journal.SigningProcess, nil)
+	_ = s.journal.RecordSession("signing-2", journal.SigningProcess, nil)
+	_ = s.journal.RecordSession("keygen-1", journal.KeygenProcess, nil)
+	_ = s.journal.UpdatePhase("signing-2", journal.PhaseRunning)
+
+	sessions, err := s.journal.UnfinishedSessions(journal.SigningProcess)
+	s.Nil(err)
+	s.Equal(2, len(sessions))
+	s.Equal("signing-1", sessions[0].SessionID)
+	s.Equal("signing-2", sessions[1].SessionID)
+
+	sessions, err = s.journal.UnfinishedSessions(journal.ResharingProcess)
+	s.Nil(err)
+	s.Equal(0, len(sessions))
+}
+
+func (s *SessionJournalTestSuite) Test_FinishSession() {
+	_ = s.journal.RecordSession("signing-1", journal.SigningProcess, nil)
+	_ = s.journal.RecordSession("signing-2", journal.SigningProcess, nil)
+
+	err := s.journal.Finish("signing-1", nil)
+	s.Nil(err)
+	err = s.journal.Finish("signing-2", errors.New("timeout"))
+	s.Nil(err)
+
+	sessions, _ := s.journal.UnfinishedSessions(journal.SigningProcess)
+	s.Equal(0, len(sessions))
+
+	session, _ := s.journal.Session("signing-1")
+	s.Equal(journal.PhaseFinished, session.Phase)
+	s.Equal(journal.OutcomeSuccess, session.Outcome)
+
+	session, _ = s.journal.Session("signing-2")
+	s.Equal(journal.PhaseFinished, session.Phase)
+	s.Equal(journal.OutcomeFailure, session.Outcome)
+	s.Equal("timeout", session.Error)
+}
+
+func (s *SessionJournalTestSuite) Test_FinishedSessionsPrunedAfterRetention() {
+	s.journal.FinishedSessionRetention = 0
+	_ = s.journal.RecordSession("signing-1", journal.SigningProcess, nil)
+	_ = s.journal.RecordSession("signing-2", journal.SigningProcess, nil)
+	_ = s.journal.RecordSession("signing-3", journal.SigningProcess, nil)
+	_ = s.journal.Finish("signing-1", nil)
+	_ = s.journal.Finish("signing-2", nil)
+	_ = s.journal.RecordSession("signing-2", journal.SigningProcess, nil)
+
+	err := s.journal.Finish("signing-3", nil)
+	s.Nil(err)
+
+	_, err = s.journal.Session("signing-1")
+	s.NotNil(err)
+	session, err := s.journal.Session("signing-2")
+	s.Nil(err)
+	s.Equal(journal.PhasePending, session.Phase)
+	session, err = s.journal.Session("signing-3")
+	s.Nil(err)
+	s.Equal(journal.PhaseFinished, session.Phase)
+}
+
+func (s *SessionJournalTestSuite) Test_PrunedSessionCanBeRecordedAgain() {
+	s.journal.FinishedSessionRetention = 0
+	_ = s.journal.RecordSession("signing-1", journal.SigningProcess, nil)
+	_ = s.journal.Finish("signing-1", nil)
+	_ = s.journal.RecordSession("signing-2", journal.SigningProcess, nil)
+	_ = s.journal.Finish("signing-2", nil)
+
+	err := s.journal.RecordSession("signing-1", journal.SigningProcess, nil)
+	s.Nil(err)
+
+	sessions, err := s.journal.UnfinishedSessions(journal.SigningProcess)
+	s.Nil(err)
+	s.Equal(1, len(sessions))
+	s.Equal("signing-1", sessions[0].SessionID)
+}
+
+func (s *SessionJournalTestSuite) Test_SessionsSurviveReopen() {
+	path := s.T().TempDir()
+	db, _ := lvldb.NewLvlDB(path)
+	_ = journal.NewSessionJournal(db).RecordSession("signing-1", journal.SigningProcess, nil)
+	db.Close()
+
+	db, _ = lvldb.NewLvlDB(path)
+	defer db.Close()
+	sessions, err := journal.NewSessionJournal(db).UnfinishedSessions(journal.SigningProcess)
+	s.Nil(err)
+	s.Equal(1, len(sessions))
+}
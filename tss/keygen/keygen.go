@@ -10,6 +10,7 @@ import (
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
 	"github.com/binance-chain/tss-lib/tss"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -18,8 +19,16 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// messageTypes are tss-lib messages exchanged during the process
+var messageTypes = []tss.MessageContent{
+	&keygen.KGRound1Message{},
+	&keygen.KGRound2Message1{},
+	&keygen.KGRound2Message2{},
+	&keygen.KGRound3Message{},
+}
+
 type SaveDataStorer interface {
-	StoreKeyshare(keyshare keyshare.Keyshare) error
+	StoreKeyshare(keyshare keyshare.Keyshare, sessionID string) error
 	LockKeyshare()
 	UnlockKeyshare()
 	GetKeyshare() (keyshare.Keyshare, error)
@@ -48,6 +57,7 @@ func NewKeygen(
 			Peers:         host.Peerstore().Peers(),
 			SID:           sessionID,
 			Log:           log.With().Str("SessionID", sessionID).Str("Process", "keygen").Logger(),
+			MessageTypes:  messageTypes,
 			Cancel:        func() {},
 		},
 		storer:    storer,
@@ -68,6 +78,9 @@ func (k *Keygen) Start(
 	k.ErrChn = errChn
 	ctx, k.Cancel = context.WithCancel(ctx)
 	k.storer.LockKeyshare()
+	if err := k.AcquireCurve(ctx, curve.Secp256k1); err != nil {
+		return
+	}
 
 	parties := common.PartiesFromPeers(k.Host.Peerstore().Peers())
 	k.PopulatePartyStore(parties)
@@ -101,6 +114,7 @@ func (k *Keygen) Stop() {
 	k.Communication.UnSubscribe(k.subscriptionID)
 	k.storer.UnlockKeyshare()
 	k.Cancel()
+	k.ReleaseCurve()
 }
 
 // Ready returns true if all parties from the peerstore are ready.
@@ -132,7 +146,7 @@ func (k *Keygen) processEndMessage(ctx context.Context, endChn chan keygen.Local
 				k.Log.Info().Msgf("Generated key share for address: %s", crypto.PubkeyToAddress(*key.ECDSAPub.ToECDSAPubKey()))
 
 				keyshare := keyshare.NewKeyshare(key, k.threshold, k.Peers)
-				err := k.storer.StoreKeyshare(keyshare)
+				err := k.storer.StoreKeyshare(keyshare, k.SessionID())
 				if err != nil {
 					k.ErrChn <- err
 				}
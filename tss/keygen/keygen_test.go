@@ -36,14 +36,14 @@ func (s *KeygenTestSuite) Test_ValidKeygenProcess() {
 		communicationMap[host.ID()] = &communication
 		keygen := keygen.NewKeygen("keygen", s.Threshold, host, &communication, s.MockStorer)
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
 		processes = append(processes, keygen)
 	}
 	tsstest.SetupCommunication(communicationMap)
 
 	s.MockStorer.EXPECT().LockKeyshare().Times(3)
 	s.MockStorer.EXPECT().UnlockKeyshare().Times(3)
-	s.MockStorer.EXPECT().StoreKeyshare(gomock.Any()).Times(3)
+	s.MockStorer.EXPECT().StoreKeyshare(gomock.Any(), gomock.Any()).Times(3)
 	status := make(chan error, s.PartyNumber)
 	ctx, cancel := context.WithCancel(context.Background())
 	for i, coordinator := range coordinators {
@@ -70,7 +70,7 @@ func (s *KeygenTestSuite) Test_KeygenTimeout() {
 		communicationMap[host.ID()] = &communication
 		keygen := keygen.NewKeygen("keygen2", s.Threshold, host, &communication, s.MockStorer)
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinator := tss.NewCoordinator(host, &communication, electorFactory)
+		coordinator := tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil)
 		coordinator.TssTimeout = time.Millisecond
 		coordinators = append(coordinators, coordinator)
 		processes = append(processes, keygen)
@@ -79,7 +79,7 @@ func (s *KeygenTestSuite) Test_KeygenTimeout() {
 
 	s.MockStorer.EXPECT().LockKeyshare().AnyTimes()
 	s.MockStorer.EXPECT().UnlockKeyshare().AnyTimes()
-	s.MockStorer.EXPECT().StoreKeyshare(gomock.Any()).Times(0)
+	s.MockStorer.EXPECT().StoreKeyshare(gomock.Any(), gomock.Any()).Times(0)
 	status := make(chan error, s.PartyNumber)
 	ctx, cancel := context.WithCancel(context.Background())
 	for i, coordinator := range coordinators {
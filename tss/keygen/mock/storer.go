@@ -62,17 +62,17 @@ func (mr *MockSaveDataStorerMockRecorder) LockKeyshare() *gomock.Call {
 }
 
 // StoreKeyshare mocks base method.
-func (m *MockSaveDataStorer) StoreKeyshare(keyshare keyshare.Keyshare) error {
+func (m *MockSaveDataStorer) StoreKeyshare(keyshare keyshare.Keyshare, sessionID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreKeyshare", keyshare)
+	ret := m.ctrl.Call(m, "StoreKeyshare", keyshare, sessionID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreKeyshare indicates an expected call of StoreKeyshare.
-func (mr *MockSaveDataStorerMockRecorder) StoreKeyshare(keyshare interface{}) *gomock.Call {
+func (mr *MockSaveDataStorerMockRecorder) StoreKeyshare(keyshare, sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreKeyshare", reflect.TypeOf((*MockSaveDataStorer)(nil).StoreKeyshare), keyshare)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreKeyshare", reflect.TypeOf((*MockSaveDataStorer)(nil).StoreKeyshare), keyshare, sessionID)
 }
 
 // UnlockKeyshare mocks base method.
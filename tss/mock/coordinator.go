@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	journal "github.com/ChainSafe/sygma-relayer/tss/journal"
 	gomock "github.com/golang/mock/gomock"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 )
@@ -129,3 +130,54 @@ func (mr *MockTssProcessMockRecorder) ValidCoordinators() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidCoordinators", reflect.TypeOf((*MockTssProcess)(nil).ValidCoordinators))
 }
+
+// MockSessionJournal is a mock of SessionJournal interface.
+type MockSessionJournal struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionJournalMockRecorder
+}
+
+// MockSessionJournalMockRecorder is the mock recorder for MockSessionJournal.
+type MockSessionJournalMockRecorder struct {
+	mock *MockSessionJournal
+}
+
+// NewMockSessionJournal creates a new mock instance.
+func NewMockSessionJournal(ctrl *gomock.Controller) *MockSessionJournal {
+	mock := &MockSessionJournal{ctrl: ctrl}
+	mock.recorder = &MockSessionJournalMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionJournal) EXPECT() *MockSessionJournalMockRecorder {
+	return m.recorder
+}
+
+// Finish mocks base method.
+func (m *MockSessionJournal) Finish(sessionID string, err error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", sessionID, err)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockSessionJournalMockRecorder) Finish(sessionID, err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockSessionJournal)(nil).Finish), sessionID, err)
+}
+
+// UpdatePhase mocks base method.
+func (m *MockSessionJournal) UpdatePhase(sessionID string, phase journal.Phase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhase", sessionID, phase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhase indicates an expected call of UpdatePhase.
+func (mr *MockSessionJournalMockRecorder) UpdatePhase(sessionID, phase interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhase", reflect.TypeOf((*MockSessionJournal)(nil).UpdatePhase), sessionID, phase)
+}
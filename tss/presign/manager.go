@@ -0,0 +1,160 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package presign
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/tss"
+)
+
+var (
+	presigningTimeout = 5 * time.Minute
+	queueSize         = 100
+)
+
+// Manager keeps the presignature pool filled by running presigning sessions
+// in the background.
+//
+// Presigning sessions have to be started by all relayers with the same session ID
+// so they are only triggered by events every relayer observes: a key generation or resharing
+// fills the pool up to the pool size and every consumed presignature triggers a single refill
+// by all of its holders. Presignatures missing after a restart are filled by relayers
+// restarted during the same hour, for example during an upgrade.
+type Manager struct {
+	*Pool
+	host          host.Host
+	communication comm.Communication
+	coordinator   *tss.Coordinator
+	fetcher       SaveDataFetcher
+	poolSize      int
+	queue         chan string
+}
+
+func NewManager(
+	pool *Pool,
+	host host.Host,
+	communication comm.Communication,
+	coordinator *tss.Coordinator,
+	fetcher SaveDataFetcher,
+	poolSize int,
+) *Manager {
+	return &Manager{
+		Pool:          pool,
+		host:          host,
+		communication: communication,
+		coordinator:   coordinator,
+		fetcher:       fetcher,
+		poolSize:      poolSize,
+		queue:         make(chan string, queueSize),
+	}
+}
+
+// Start fills the pool if it is below the pool size and then runs queued presigning
+// sessions one by one until the context is canceled.
+func (m *Manager) Start(ctx context.Context) {
+	m.fillOnStartup(ctx)
+	for {
+		select {
+		case sessionID := <-m.queue:
+			{
+				err := m.presign(ctx, sessionID)
+				if err != nil {
+					log.Warn().Err(err).Str("SessionID", sessionID).Msgf("Failed generating presignature")
+				}
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+// Fill schedules presigning sessions to fill the pool after the keyshare
+// was generated or changed.
+func (m *Manager) Fill(sessionID string) {
+	for i := 0; i < m.poolSize; i++ {
+		m.enqueue(fmt.Sprintf("presign-%s-%d", sessionID, i))
+	}
+}
+
+// fillOnStartup runs presigning sessions for presignatures missing from the pool. Session IDs
+// are derived from the MPC address and the hour of the start so relayers started together run
+// the same sessions. Filling stops at the first failed session as other relayers are
+// probably not filling their pools.
+func (m *Manager) fillOnStartup(ctx context.Context) {
+	if m.poolSize == 0 {
+		return
+	}
+	size, err := m.Pool.Size()
+	if err != nil {
+		log.Warn().Err(err).Msgf("Unable to read presignature pool size")
+		return
+	}
+	key, err := m.fetcher.GetKeyshare()
+	if err != nil {
+		// pool is filled after key generation
+		return
+	}
+
+	startHour := time.Now().UTC().Truncate(time.Hour).Unix()
+	for i := 0; i < m.poolSize-size && ctx.Err() == nil; i++ {
+		sessionID := fmt.Sprintf("presign-%s-%d-%d", key.Address(), startHour, i)
+		err := m.presign(ctx, sessionID)
+		if err != nil {
+			log.Warn().Err(err).Str("SessionID", sessionID).Msgf("Stopped filling presignature pool on startup")
+			return
+		}
+	}
+}
+
+// Take removes the presignature from the pool and schedules a presigning session
+// to replace it. Presignature holders take the same presignature in the one round
+// signing session so they start the replacing session together.
+func (m *Manager) Take(id string) (Presignature, error) {
+	presignature, err := m.Pool.Take(id)
+	if err != nil {
+		return presignature, err
+	}
+
+	m.refill(id)
+	return presignature, nil
+}
+
+// refill schedules a single presigning session to replace the consumed presignature.
+func (m *Manager) refill(presignatureID string) {
+	if m.poolSize == 0 {
+		return
+	}
+
+	m.enqueue(fmt.Sprintf("presign-%s", presignatureID))
+}
+
+func (m *Manager) enqueue(sessionID string) {
+	select {
+	case m.queue <- sessionID:
+	default:
+		log.Warn().Str("SessionID", sessionID).Msgf("Presigning queue full, skipping session")
+	}
+}
+
+func (m *Manager) presign(ctx context.Context, sessionID string) error {
+	presigning, err := NewPresigning(sessionID, m.host, m.communication, m.fetcher, m.Pool)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, presigningTimeout)
+	defer cancel()
+	statusChn := make(chan error, 1)
+	m.coordinator.Execute(ctx, presigning, make(chan interface{}, 1), statusChn)
+	return <-statusChn
+}
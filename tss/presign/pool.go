@@ -0,0 +1,382 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package presign
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/binance-chain/tss-lib/ecdsa/signing"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/syndtr/goleveldb/leveldb"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+)
+
+const presignaturesKey = "tss:presignatures"
+
+var ErrNoPresignature = errors.New("no presignature available")
+
+// errUnreadablePresignature is returned for stored presignatures that can't be decrypted,
+// like plaintext presignatures stored before encryption was configured.
+var errUnreadablePresignature = errors.New("unable to read presignature")
+
+// Presignature is the result of the offline signing phase that can be
+// used to sign exactly one message in a single communication round.
+type Presignature struct {
+	ID        string    `json:"id"`
+	Peers     []peer.ID `json:"peers"`
+	KeyID     string    `json:"keyID"`
+	Data      []byte    `json:"data"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewPresignature encodes one round data generated by the offline signing phase
+// into a presignature.
+func NewPresignature(id string, peers []peer.ID, key keyshare.Keyshare, data *signing.SignatureData_OneRoundData) (Presignature, error) {
+	dataBytes, err := proto.Marshal(data)
+	if err != nil {
+		return Presignature{}, err
+	}
+
+	return Presignature{
+		ID:        id,
+		Peers:     peers,
+		KeyID:     KeyID(key),
+		Data:      dataBytes,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// OneRoundData decodes presignature data used to calculate the signature share.
+func (p Presignature) OneRoundData() (*signing.SignatureData_OneRoundData, error) {
+	data := &signing.SignatureData_OneRoundData{}
+	err := proto.Unmarshal(p.Data, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// KeyID calculates identifier of the keyshare a presignature was generated with.
+// Resharing changes the identifier which invalidates all existing presignatures.
+func KeyID(key keyshare.Keyshare) string {
+	hash := sha256.New()
+	if key.Key.Xi != nil {
+		hash.Write(key.Key.Xi.Bytes())
+	}
+	if key.Key.ECDSAPub != nil {
+		hash.Write(key.Key.ECDSAPub.X().Bytes())
+		hash.Write(key.Key.ECDSAPub.Y().Bytes())
+	}
+	hash.Write([]byte(fmt.Sprint(key.Threshold)))
+	for _, peer := range key.Peers {
+		hash.Write([]byte(peer))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type KeyValueReaderWriter interface {
+	GetByKey(key []byte) ([]byte, error)
+	SetByKey(key []byte, value []byte) error
+}
+
+type KeyshareFetcher interface {
+	GetKeyshare() (keyshare.Keyshare, error)
+}
+
+// Pool durably stores presignatures generated with the current keyshare.
+// Presignature is removed from the pool before it is used so it can never
+// be used to sign two different messages.
+// Selected presignatures are reserved in memory until they are taken or
+// released so concurrent signing sessions select different presignatures.
+type Pool struct {
+	mu         sync.Mutex
+	db         KeyValueReaderWriter
+	fetcher    KeyshareFetcher
+	encryption *keyshare.Encryption
+	maxSize    int
+	reserved   map[string]bool
+}
+
+func NewPool(db KeyValueReaderWriter, fetcher KeyshareFetcher, maxSize int) *Pool {
+	return NewPoolWithEncryption(db, fetcher, nil, maxSize)
+}
+
+// NewPoolWithEncryption creates a pool that encrypts stored presignatures as they
+// contain secret shares of the signing nonce. Presignatures are stored in plaintext
+// if encryption is nil, same as the keyshare.
+func NewPoolWithEncryption(db KeyValueReaderWriter, fetcher KeyshareFetcher, encryption *keyshare.Encryption, maxSize int) *Pool {
+	return &Pool{
+		db:         db,
+		fetcher:    fetcher,
+		encryption: encryption,
+		maxSize:    maxSize,
+		reserved:   make(map[string]bool),
+	}
+}
+
+// Add stores a new presignature into the pool. If the pool is full
+// oldest presignatures are discarded.
+func (p *Pool) Add(presignature Presignature) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keyID, err := p.keyID()
+	if err != nil {
+		return err
+	}
+	if presignature.KeyID != keyID {
+		return fmt.Errorf("presignature %s generated with outdated keyshare", presignature.ID)
+	}
+
+	ids, err := p.ids()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(ids, presignature.ID) {
+		return fmt.Errorf("presignature %s already exists", presignature.ID)
+	}
+
+	err = p.store(presignature)
+	if err != nil {
+		return err
+	}
+
+	ids = append(ids, presignature.ID)
+	for len(ids) > p.maxSize {
+		err = p.remove(ids[0])
+		if err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+
+	return p.storeIDs(ids)
+}
+
+// Select reserves and returns the oldest valid unreserved presignature that can be
+// completed by the provided peers without removing it from the pool. Presignatures
+// that can't be decrypted are skipped. Reservation has to be released with Release
+// if the presignature is not taken.
+func (p *Pool) Select(peers []peer.ID) (Presignature, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keyID, err := p.keyID()
+	if err != nil {
+		return Presignature{}, err
+	}
+	ids, err := p.ids()
+	if err != nil {
+		return Presignature{}, err
+	}
+
+	for _, id := range ids {
+		if p.reserved[id] {
+			continue
+		}
+		presignature, err := p.presignature(id)
+		if errors.Is(err, errUnreadablePresignature) {
+			continue
+		}
+		if err != nil {
+			return Presignature{}, err
+		}
+		if presignature.KeyID != keyID {
+			continue
+		}
+		if !containsAll(peers, presignature.Peers) {
+			continue
+		}
+
+		p.reserved[id] = true
+		return presignature, nil
+	}
+
+	return Presignature{}, ErrNoPresignature
+}
+
+// Release makes the selected presignature available for selection again.
+func (p *Pool) Release(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.reserved, id)
+}
+
+// Take removes the presignature from the pool and returns it if it was
+// generated with the current keyshare. Reservation of the presignature is removed.
+func (p *Pool) Take(id string) (Presignature, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.reserved, id)
+
+	ids, err := p.ids()
+	if err != nil {
+		return Presignature{}, err
+	}
+	index := slices.Index(ids, id)
+	if index == -1 {
+		return Presignature{}, fmt.Errorf("presignature %s: %w", id, ErrNoPresignature)
+	}
+
+	presignature, readErr := p.presignature(id)
+	if readErr != nil && !errors.Is(readErr, errUnreadablePresignature) {
+		return Presignature{}, readErr
+	}
+	err = p.storeIDs(slices.Delete(ids, index, index+1))
+	if err != nil {
+		return Presignature{}, err
+	}
+	err = p.remove(id)
+	if err != nil {
+		return Presignature{}, err
+	}
+	if readErr != nil {
+		return Presignature{}, readErr
+	}
+
+	keyID, err := p.keyID()
+	if err != nil {
+		return Presignature{}, err
+	}
+	if presignature.KeyID != keyID {
+		return Presignature{}, fmt.Errorf("presignature %s generated with outdated keyshare", id)
+	}
+
+	return presignature, nil
+}
+
+// Size returns number of presignatures stored in the pool.
+func (p *Pool) Size() (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids, err := p.ids()
+	return len(ids), err
+}
+
+// Invalidate discards all stored presignatures.
+func (p *Pool) Invalidate() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids, err := p.ids()
+	if err != nil {
+		return err
+	}
+
+	err = p.storeIDs([]string{})
+	if err != nil {
+		return err
+	}
+	p.reserved = make(map[string]bool)
+	for _, id := range ids {
+		err = p.remove(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Pool) keyID() (string, error) {
+	key, err := p.fetcher.GetKeyshare()
+	if err != nil {
+		return "", err
+	}
+
+	return KeyID(key), nil
+}
+
+func (p *Pool) presignature(id string) (Presignature, error) {
+	presignature := Presignature{}
+	b, err := p.db.GetByKey(presignatureKey(id))
+	if err != nil {
+		return presignature, err
+	}
+	if p.encryption != nil {
+		b, err = p.encryption.Decrypt(b)
+		if err != nil {
+			return presignature, fmt.Errorf("presignature %s: %w: %s", id, errUnreadablePresignature, err)
+		}
+	}
+
+	err = json.Unmarshal(b, &presignature)
+	if err != nil {
+		return presignature, fmt.Errorf("error on unmarshaling presignature %s: %w", id, err)
+	}
+
+	return presignature, nil
+}
+
+func (p *Pool) store(presignature Presignature) error {
+	b, err := json.Marshal(presignature)
+	if err != nil {
+		return err
+	}
+	if p.encryption != nil {
+		b, err = p.encryption.Encrypt(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.db.SetByKey(presignatureKey(presignature.ID), b)
+}
+
+// remove overwrites presignature data as the key value store
+// does not support deletion
+func (p *Pool) remove(id string) error {
+	return p.db.SetByKey(presignatureKey(id), []byte{})
+}
+
+func (p *Pool) ids() ([]string, error) {
+	ids := []string{}
+	b, err := p.db.GetByKey([]byte(presignaturesKey))
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return ids, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &ids)
+	return ids, err
+}
+
+func (p *Pool) storeIDs(ids []string) error {
+	b, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+
+	return p.db.SetByKey([]byte(presignaturesKey), b)
+}
+
+func presignatureKey(id string) []byte {
+	return []byte(fmt.Sprintf("tss:presignature:%s", id))
+}
+
+func containsAll(peers []peer.ID, subset []peer.ID) bool {
+	for _, peer := range subset {
+		if !slices.Contains(peers, peer) {
+			return false
+		}
+	}
+
+	return true
+}
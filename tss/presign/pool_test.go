@@ -0,0 +1,199 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package presign_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-core/lvldb"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss/presign"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type PoolTestSuite struct {
+	suite.Suite
+	db    *lvldb.LVLDB
+	key   keyshare.Keyshare
+	pool  *presign.Pool
+	peers []peer.ID
+}
+
+func TestRunPoolTestSuite(t *testing.T) {
+	suite.Run(t, new(PoolTestSuite))
+}
+
+func (s *PoolTestSuite) SetupTest() {
+	db, err := lvldb.NewLvlDB(s.T().TempDir())
+	if err != nil {
+		panic(err)
+	}
+	s.db = db
+
+	fetcher := keyshare.NewKeyshareStore("../test/keyshares/0.keyshare")
+	s.key, err = fetcher.GetKeyshare()
+	if err != nil {
+		panic(err)
+	}
+	s.peers = s.key.Peers
+	s.pool = presign.NewPool(db, fetcher, 2)
+}
+func (s *PoolTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *PoolTestSuite) presignature(id string, peers []peer.ID) presign.Presignature {
+	return presign.Presignature{
+		ID:        id,
+		Peers:     peers,
+		KeyID:     presign.KeyID(s.key),
+		Data:      []byte{},
+		CreatedAt: time.Now(),
+	}
+}
+
+func (s *PoolTestSuite) Test_Add_OutdatedKeyshare() {
+	presignature := s.presignature("presign-1", s.peers[:2])
+	presignature.KeyID = "outdated"
+
+	err := s.pool.Add(presignature)
+
+	s.NotNil(err)
+}
+
+func (s *PoolTestSuite) Test_Add_Duplicate() {
+	err := s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	s.Nil(err)
+
+	err = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	s.NotNil(err)
+}
+
+func (s *PoolTestSuite) Test_Add_FullPoolDiscardsOldest() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	_ = s.pool.Add(s.presignature("presign-2", s.peers[:2]))
+	_ = s.pool.Add(s.presignature("presign-3", s.peers[:2]))
+
+	size, err := s.pool.Size()
+	s.Nil(err)
+	s.Equal(2, size)
+
+	_, err = s.pool.Take("presign-1")
+	s.True(errors.Is(err, presign.ErrNoPresignature))
+}
+
+func (s *PoolTestSuite) Test_Select_NoPresignatureForPeers() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+
+	_, err := s.pool.Select(s.peers[1:])
+
+	s.True(errors.Is(err, presign.ErrNoPresignature))
+}
+
+func (s *PoolTestSuite) Test_Select_ReturnsOldestMatching() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	_ = s.pool.Add(s.presignature("presign-2", s.peers[1:]))
+
+	presignature, err := s.pool.Select(s.peers[1:])
+	s.Nil(err)
+	s.Equal("presign-2", presignature.ID)
+
+	presignature, err = s.pool.Select(s.peers)
+	s.Nil(err)
+	s.Equal("presign-1", presignature.ID)
+
+	size, _ := s.pool.Size()
+	s.Equal(2, size)
+}
+
+func (s *PoolTestSuite) Test_Select_SkipsReservedPresignature() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	_ = s.pool.Add(s.presignature("presign-2", s.peers[:2]))
+
+	first, err := s.pool.Select(s.peers)
+	s.Nil(err)
+	second, err := s.pool.Select(s.peers)
+	s.Nil(err)
+	s.NotEqual(first.ID, second.ID)
+
+	_, err = s.pool.Select(s.peers)
+	s.True(errors.Is(err, presign.ErrNoPresignature))
+}
+
+func (s *PoolTestSuite) Test_Release_PresignatureSelectableAgain() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	presignature, err := s.pool.Select(s.peers)
+	s.Nil(err)
+
+	s.pool.Release(presignature.ID)
+
+	released, err := s.pool.Select(s.peers)
+	s.Nil(err)
+	s.Equal(presignature.ID, released.ID)
+}
+
+func (s *PoolTestSuite) Test_Take_PresignatureUsedOnlyOnce() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+
+	presignature, err := s.pool.Take("presign-1")
+	s.Nil(err)
+	s.Equal("presign-1", presignature.ID)
+
+	_, err = s.pool.Take("presign-1")
+	s.NotNil(err)
+	_, err = s.pool.Select(s.peers)
+	s.NotNil(err)
+}
+
+func (s *PoolTestSuite) Test_Invalidate() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	_ = s.pool.Add(s.presignature("presign-2", s.peers[1:]))
+
+	err := s.pool.Invalidate()
+	s.Nil(err)
+
+	size, _ := s.pool.Size()
+	s.Equal(0, size)
+	_, err = s.pool.Take("presign-2")
+	s.NotNil(err)
+}
+
+func (s *PoolTestSuite) Test_Add_EncryptedPresignature() {
+	encryption, err := keyshare.NewPassphraseEncryption("passphrase")
+	s.Nil(err)
+	pool := presign.NewPoolWithEncryption(s.db, keyshare.NewKeyshareStore("../test/keyshares/0.keyshare"), encryption, 2)
+	presignature := s.presignature("presign-1", s.peers[:2])
+	presignature.Data = []byte("secret nonce share")
+
+	err = pool.Add(presignature)
+	s.Nil(err)
+
+	stored, err := s.db.GetByKey([]byte("tss:presignature:presign-1"))
+	s.Nil(err)
+	s.False(bytes.Contains(stored, presignature.Data))
+	s.False(bytes.Contains(stored, []byte(base64.StdEncoding.EncodeToString(presignature.Data))))
+	taken, err := pool.Take("presign-1")
+	s.Nil(err)
+	s.Equal(presignature.Data, taken.Data)
+}
+
+func (s *PoolTestSuite) Test_Select_SkipsPlaintextPresignatureWhenEncrypted() {
+	_ = s.pool.Add(s.presignature("presign-1", s.peers[:2]))
+	encryption, err := keyshare.NewPassphraseEncryption("passphrase")
+	s.Nil(err)
+	pool := presign.NewPoolWithEncryption(s.db, keyshare.NewKeyshareStore("../test/keyshares/0.keyshare"), encryption, 2)
+
+	_, err = pool.Select(s.peers)
+	s.True(errors.Is(err, presign.ErrNoPresignature))
+
+	_, err = pool.Take("presign-1")
+	s.NotNil(err)
+	size, _ := pool.Size()
+	s.Equal(0, size)
+}
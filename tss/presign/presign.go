@@ -0,0 +1,266 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package presign
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/binance-chain/tss-lib/ecdsa/signing"
+	"github.com/binance-chain/tss-lib/tss"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
+)
+
+// messageTypes are tss-lib messages exchanged during the process
+var messageTypes = []tss.MessageContent{
+	&signing.SignRound1Message1{},
+	&signing.SignRound1Message2{},
+	&signing.SignRound2Message{},
+	&signing.SignRound3Message{},
+	&signing.SignRound4Message{},
+	&signing.SignRound5Message{},
+	&signing.SignRound6Message{},
+	&signing.SignRound7Message{},
+}
+
+type SaveDataFetcher interface {
+	GetKeyshare() (keyshare.Keyshare, error)
+	LockKeyshare()
+	UnlockKeyshare()
+}
+
+type PresignatureStorer interface {
+	Add(presignature Presignature) error
+}
+
+// Presigning runs the offline phase of the signing process which does not
+// depend on the message being signed and stores the result into the presignature pool.
+type Presigning struct {
+	common.BaseTss
+	key            keyshare.Keyshare
+	pool           PresignatureStorer
+	subscriptionID comm.SubscriptionID
+}
+
+func NewPresigning(
+	sessionID string,
+	host host.Host,
+	comm comm.Communication,
+	fetcher SaveDataFetcher,
+	pool PresignatureStorer,
+) (*Presigning, error) {
+	fetcher.LockKeyshare()
+	defer fetcher.UnlockKeyshare()
+	key, err := fetcher.GetKeyshare()
+	if err != nil {
+		return nil, err
+	}
+
+	partyStore := make(map[string]*tss.PartyID)
+	return &Presigning{
+		BaseTss: common.BaseTss{
+			PartyStore:    partyStore,
+			Host:          host,
+			Communication: comm,
+			Peers:         key.Peers,
+			SID:           sessionID,
+			Log:           log.With().Str("SessionID", sessionID).Str("Process", "presigning").Logger(),
+			MessageTypes:  messageTypes,
+			Cancel:        func() {},
+		},
+		key:  key,
+		pool: pool,
+	}, nil
+}
+
+// Start initializes the one round signing party and starts the offline signing phase.
+// Params contains peer subset that leaders sends with start message.
+// Peers that are not part of the subset end the process successfully
+// as they will not hold the presignature.
+func (p *Presigning) Start(
+	ctx context.Context,
+	coordinator bool,
+	resultChn chan interface{},
+	errChn chan error,
+	params []byte,
+) {
+	p.ErrChn = errChn
+	ctx, p.Cancel = context.WithCancel(ctx)
+
+	var peerSubset []peer.ID
+	err := json.Unmarshal(params, &peerSubset)
+	if err != nil {
+		p.ErrChn <- err
+		return
+	}
+
+	if !common.IsParticipant(common.CreatePartyID(p.Host.ID().Pretty()), common.PartiesFromPeers(peerSubset)) {
+		p.Log.Debug().Msgf("Not selected for presignature generation")
+		p.ErrChn <- nil
+		return
+	}
+
+	if err := p.AcquireCurve(ctx, curve.Secp256k1); err != nil {
+		return
+	}
+
+	p.Peers = peerSubset
+	parties := common.PartiesFromPeers(p.Peers)
+	p.PopulatePartyStore(parties)
+	pCtx := tss.NewPeerContext(parties)
+	tssParams := tss.NewParameters(pCtx, p.PartyStore[p.Host.ID().Pretty()], len(parties), p.key.Threshold)
+
+	sigChn := make(chan *signing.SignatureData)
+	outChn := make(chan tss.Message)
+	msgChn := make(chan *comm.WrappedMessage)
+	p.subscriptionID = p.Communication.Subscribe(p.SessionID(), comm.TssPresignMsg, msgChn)
+	go p.ProcessOutboundMessages(ctx, outChn, comm.TssPresignMsg)
+	go p.ProcessInboundMessages(ctx, msgChn)
+	go p.processEndMessage(ctx, sigChn)
+
+	p.Log.Info().Msgf("Started presigning process")
+
+	p.Party = signing.NewLocalPartyWithOneRoundSign(tssParams, p.key.Key, outChn, sigChn)
+	go func() {
+		err := p.Party.Start()
+		if err != nil {
+			p.ErrChn <- err
+			return
+		}
+
+		p.monitorPresigning(ctx)
+	}()
+}
+
+// Stop ends all subscriptions created when starting the tss process.
+func (p *Presigning) Stop() {
+	log.Info().Str("sessionID", p.SessionID()).Msgf("Stopping tss process.")
+	p.Communication.UnSubscribe(p.subscriptionID)
+	p.Cancel()
+	p.ReleaseCurve()
+}
+
+// Ready returns true if threshold+1 parties are ready to start the presigning process.
+func (p *Presigning) Ready(readyMap map[peer.ID]bool, excludedPeers []peer.ID) (bool, error) {
+	readyMap = p.readyParticipants(readyMap)
+	return len(readyMap) == p.key.Threshold+1, nil
+}
+
+// ValidCoordinators returns only peers that have a valid keyshare
+func (p *Presigning) ValidCoordinators() []peer.ID {
+	return p.key.Peers
+}
+
+// StartParams returns peer subset that will hold the generated presignature.
+func (p *Presigning) StartParams(readyMap map[peer.ID]bool) []byte {
+	readyMap = p.readyParticipants(readyMap)
+	peers := []peer.ID{}
+	for peer := range readyMap {
+		peers = append(peers, peer)
+	}
+
+	sortedPeers := common.SortPeersForSession(peers, p.SessionID())
+	peerSubset := []peer.ID{}
+	for _, peer := range sortedPeers {
+		peerSubset = append(peerSubset, peer.ID)
+		if len(peerSubset) == p.key.Threshold+1 {
+			break
+		}
+	}
+
+	paramBytes, _ := json.Marshal(peerSubset)
+	return paramBytes
+}
+
+// Retryable returns false as failed presignature generation is replaced
+// by the next pool refill.
+func (p *Presigning) Retryable() bool {
+	return false
+}
+
+// processEndMessage stores generated presignature into the pool.
+func (p *Presigning) processEndMessage(ctx context.Context, endChn chan *signing.SignatureData) {
+	for {
+		select {
+		case sig := <-endChn:
+			{
+				presignature, err := NewPresignature(p.SessionID(), p.Peers, p.key, sig.OneRoundData)
+				if err != nil {
+					p.ErrChn <- err
+					return
+				}
+
+				err = p.pool.Add(presignature)
+				if err != nil {
+					p.ErrChn <- err
+					return
+				}
+
+				p.Log.Info().Msg("Successfully generated presignature")
+				p.ErrChn <- nil
+				return
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+// readyParticipants returns all ready peers that contain a valid key share
+func (p *Presigning) readyParticipants(readyMap map[peer.ID]bool) map[peer.ID]bool {
+	readyParticipants := make(map[peer.ID]bool)
+	for peer, ready := range readyMap {
+		if !ready {
+			continue
+		}
+
+		if !slices.Contains(p.key.Peers, peer) {
+			continue
+		}
+
+		readyParticipants[peer] = true
+	}
+
+	return readyParticipants
+}
+
+// monitorPresigning checks if the process is stuck and waiting for peers and sends an error
+// if it is
+func (p *Presigning) monitorPresigning(ctx context.Context) {
+	waitingFor := make([]*tss.PartyID, 0)
+	ticker := time.NewTicker(time.Minute * 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			{
+				if len(waitingFor) != 0 && reflect.DeepEqual(p.Party.WaitingFor(), waitingFor) {
+					p.ErrChn <- &comm.CommunicationError{
+						Err: fmt.Errorf("waiting for peers %s", waitingFor),
+					}
+				}
+
+				waitingFor = p.Party.WaitingFor()
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package presign_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-core/lvldb"
+	tssSigning "github.com/binance-chain/tss-lib/ecdsa/signing"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/ChainSafe/sygma-relayer/tss/presign"
+	"github.com/ChainSafe/sygma-relayer/tss/signing"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
+)
+
+type PresigningTestSuite struct {
+	tsstest.CoordinatorTestSuite
+}
+
+func TestRunPresigningTestSuite(t *testing.T) {
+	suite.Run(t, new(PresigningTestSuite))
+}
+
+func (s *PresigningTestSuite) Test_PresignatureUsedForOneRoundSigning() {
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+	pools := []*presign.Pool{}
+	fetchers := []*keyshare.KeyshareStore{}
+
+	for i, host := range s.Hosts {
+		communication := tsstest.TestCommunication{
+			Host:          host,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+		communicationMap[host.ID()] = &communication
+		fetcher := keyshare.NewKeyshareStore(fmt.Sprintf("../test/keyshares/%d.keyshare", i))
+		db, err := lvldb.NewLvlDB(s.T().TempDir())
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+		pool := presign.NewPool(db, fetcher, 10)
+
+		presigning, err := presign.NewPresigning("presign1", host, &communication, fetcher, pool)
+		if err != nil {
+			panic(err)
+		}
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
+		processes = append(processes, presigning)
+		pools = append(pools, pool)
+		fetchers = append(fetchers, fetcher)
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	statusChn := make(chan error, s.PartyNumber)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], make(chan interface{}, 1), statusChn)
+	}
+	for i := 0; i < s.PartyNumber; i++ {
+		err := <-statusChn
+		s.Nil(err)
+	}
+
+	holders := []peer.ID{}
+	for i, pool := range pools {
+		size, _ := pool.Size()
+		if size == 1 {
+			holders = append(holders, s.Hosts[i].ID())
+		}
+	}
+	s.Equal(s.Threshold+1, len(holders))
+
+	// pick signing session where the statically elected coordinator holds the presignature
+	sessionID := ""
+	for i := 0; sessionID == ""; i++ {
+		candidate := fmt.Sprintf("signing%d", i)
+		coordinator, _ := elector.NewCoordinatorElectorFactory(s.Hosts[0], s.BullyConfig).
+			CoordinatorElector(candidate, elector.Static).
+			Coordinator(ctx, fetcherPeers(fetchers[0]))
+		if coordinator == holders[0] || coordinator == holders[1] {
+			sessionID = candidate
+		}
+	}
+
+	processes = []tss.TssProcess{}
+	for i, host := range s.Hosts {
+		msg := big.NewInt(0).SetBytes([]byte("Message"))
+		signing, err := signing.NewSigning(msg, sessionID, host, communicationMap[host.ID()], fetchers[i], pools[i], nil, nil)
+		if err != nil {
+			panic(err)
+		}
+		processes = append(processes, signing)
+	}
+
+	resultChn := make(chan interface{})
+	for i, coordinator := range coordinators {
+		go coordinator.Execute(ctx, processes[i], resultChn, statusChn)
+	}
+
+	sig := <-resultChn
+	s.NotNil(sig.(*tssSigning.SignatureData).Signature)
+	err := <-statusChn
+	s.Nil(err)
+	for _, pool := range pools {
+		size, _ := pool.Size()
+		s.Equal(0, size)
+	}
+	time.Sleep(time.Millisecond * 50)
+}
+
+func (s *PresigningTestSuite) Test_Manager_FillsPoolOnStartup() {
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	managers := []*presign.Manager{}
+	for i, host := range s.Hosts {
+		communication := tsstest.TestCommunication{
+			Host:          host,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+		communicationMap[host.ID()] = &communication
+		fetcher := keyshare.NewKeyshareStore(fmt.Sprintf("../test/keyshares/%d.keyshare", i))
+		db, err := lvldb.NewLvlDB(s.T().TempDir())
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		coordinator := tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil)
+		managers = append(managers, presign.NewManager(presign.NewPool(db, fetcher, 1), host, &communication, coordinator, fetcher, 1))
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, manager := range managers {
+		go manager.Start(ctx)
+	}
+
+	s.Eventually(func() bool {
+		holders := 0
+		for _, manager := range managers {
+			size, _ := manager.Size()
+			holders += size
+		}
+		return holders == s.Threshold+1
+	}, time.Minute, time.Millisecond*100)
+}
+
+func fetcherPeers(fetcher *keyshare.KeyshareStore) []peer.ID {
+	key, _ := fetcher.GetKeyshare()
+	return key.Peers
+}
@@ -0,0 +1,230 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package tss
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var finishedSessionRetention = time.Hour
+
+type SessionState string
+
+const (
+	SessionElecting        SessionState = "electing"
+	SessionInitiating      SessionState = "initiating"
+	SessionWaitingForStart SessionState = "waiting-for-start"
+	SessionRunning         SessionState = "running"
+	SessionRetrying        SessionState = "retrying"
+	SessionFailed          SessionState = "failed"
+	SessionDone            SessionState = "done"
+)
+
+// SessionInfo is a snapshot of a single tss session tracked by the coordinator
+type SessionInfo struct {
+	SessionID     string       `json:"sessionID"`
+	State         SessionState `json:"state"`
+	Coordinator   peer.ID      `json:"coordinator"`
+	ExcludedPeers []peer.ID    `json:"excludedPeers"`
+	Retries       int          `json:"retries"`
+	Error         string       `json:"error,omitempty"`
+	StartedAt     time.Time    `json:"startedAt"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
+}
+
+// Live returns true if session has not yet ended
+func (si SessionInfo) Live() bool {
+	return si.State != SessionDone && si.State != SessionFailed
+}
+
+type registeredSession struct {
+	info        SessionInfo
+	retryLocked bool
+}
+
+// SessionRegistry keeps track of tss sessions executed by the coordinator
+// and is safe for concurrent use.
+type SessionRegistry struct {
+	mu       sync.RWMutex
+	sessions map[string]*registeredSession
+}
+
+func NewSessionRegistry() *SessionRegistry {
+	return &SessionRegistry{
+		sessions: make(map[string]*registeredSession),
+	}
+}
+
+// Register adds a new session to the registry. Error is returned if
+// a live session with the same ID already exists.
+func (r *SessionRegistry) Register(sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	session, ok := r.sessions[sessionID]
+	if ok && session.info.Live() {
+		return fmt.Errorf("process %s already pending", sessionID)
+	}
+
+	now := time.Now()
+	r.sessions[sessionID] = &registeredSession{
+		info: SessionInfo{
+			SessionID:     sessionID,
+			State:         SessionElecting,
+			ExcludedPeers: []peer.ID{},
+			StartedAt:     now,
+			UpdatedAt:     now,
+		},
+	}
+	return nil
+}
+
+// UpdateState changes the state of a live session.
+func (r *SessionRegistry) UpdateState(sessionID string, state SessionState) {
+	r.update(sessionID, func(s *registeredSession) {
+		s.info.State = state
+	})
+}
+
+// SetCoordinator sets the currently elected coordinator of a live session.
+func (r *SessionRegistry) SetCoordinator(sessionID string, coordinator peer.ID) {
+	r.update(sessionID, func(s *registeredSession) {
+		s.info.Coordinator = coordinator
+	})
+}
+
+// SetExcludedPeers sets the peers excluded from the current session attempt.
+func (r *SessionRegistry) SetExcludedPeers(sessionID string, excludedPeers []peer.ID) {
+	r.update(sessionID, func(s *registeredSession) {
+		s.info.ExcludedPeers = excludedPeers
+	})
+}
+
+// LockRetry checks if a retry already happened and prevents multiple retries happening
+// at the same time. On success session is moved to retrying state.
+func (r *SessionRegistry) LockRetry(sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[sessionID]
+	if !ok || !session.info.Live() {
+		return fmt.Errorf("process %s is not pending", sessionID)
+	}
+	if session.retryLocked {
+		return fmt.Errorf("process %s has pending retry", sessionID)
+	}
+
+	session.retryLocked = true
+	session.info.Retries++
+	session.info.State = SessionRetrying
+	session.info.UpdatedAt = time.Now()
+	return nil
+}
+
+// Finish ends the session with failed or done state based on the provided error.
+func (r *SessionRegistry) Finish(sessionID string, err error) {
+	r.update(sessionID, func(s *registeredSession) {
+		if err != nil {
+			s.info.State = SessionFailed
+			s.info.Error = err.Error()
+			return
+		}
+
+		s.info.State = SessionDone
+	})
+}
+
+// Release removes the session from the registry if it was stopped
+// without finishing.
+func (r *SessionRegistry) Release(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[sessionID]
+	if ok && session.info.Live() {
+		delete(r.sessions, sessionID)
+	}
+}
+
+// Session returns a snapshot of the session with the provided ID.
+func (r *SessionRegistry) Session(sessionID string) (SessionInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		return SessionInfo{}, false
+	}
+
+	return session.info.clone(), true
+}
+
+// Sessions returns snapshots of all live and recently finished sessions sorted
+// by start time.
+func (r *SessionRegistry) Sessions() []SessionInfo {
+	return r.filter(func(SessionInfo) bool { return true })
+}
+
+// LiveSessions returns snapshots of all sessions that have not yet ended sorted
+// by start time.
+func (r *SessionRegistry) LiveSessions() []SessionInfo {
+	return r.filter(SessionInfo.Live)
+}
+
+func (r *SessionRegistry) filter(include func(SessionInfo) bool) []SessionInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]SessionInfo, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		if !include(session.info) {
+			continue
+		}
+
+		sessions = append(sessions, session.info.clone())
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+	return sessions
+}
+
+func (r *SessionRegistry) update(sessionID string, update func(s *registeredSession)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[sessionID]
+	if !ok || !session.info.Live() {
+		return
+	}
+
+	update(session)
+	session.info.UpdatedAt = time.Now()
+}
+
+// prune removes finished sessions older than the retention period
+func (r *SessionRegistry) prune() {
+	for sessionID, session := range r.sessions {
+		if session.info.Live() {
+			continue
+		}
+
+		if time.Since(session.info.UpdatedAt) > finishedSessionRetention {
+			delete(r.sessions, sessionID)
+		}
+	}
+}
+
+func (si SessionInfo) clone() SessionInfo {
+	excludedPeers := make([]peer.ID, len(si.ExcludedPeers))
+	copy(excludedPeers, si.ExcludedPeers)
+	si.ExcludedPeers = excludedPeers
+	return si
+}
@@ -0,0 +1,125 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package tss_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionRegistryTestSuite struct {
+	suite.Suite
+	registry *tss.SessionRegistry
+}
+
+func TestRunSessionRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionRegistryTestSuite))
+}
+
+func (s *SessionRegistryTestSuite) SetupTest() {
+	s.registry = tss.NewSessionRegistry()
+}
+
+func (s *SessionRegistryTestSuite) Test_Register_PendingSessionExists() {
+	err := s.registry.Register("signing-1")
+	s.Nil(err)
+
+	err = s.registry.Register("signing-1")
+	s.NotNil(err)
+}
+
+func (s *SessionRegistryTestSuite) Test_Register_FinishedSessionReregistered() {
+	_ = s.registry.Register("signing-1")
+	s.registry.Finish("signing-1", nil)
+
+	err := s.registry.Register("signing-1")
+	s.Nil(err)
+
+	session, ok := s.registry.Session("signing-1")
+	s.True(ok)
+	s.Equal(tss.SessionElecting, session.State)
+}
+
+func (s *SessionRegistryTestSuite) Test_LockRetry_OnlyOnce() {
+	_ = s.registry.Register("signing-1")
+
+	err := s.registry.LockRetry("signing-1")
+	s.Nil(err)
+	err = s.registry.LockRetry("signing-1")
+	s.NotNil(err)
+
+	session, _ := s.registry.Session("signing-1")
+	s.Equal(tss.SessionRetrying, session.State)
+	s.Equal(1, session.Retries)
+}
+
+func (s *SessionRegistryTestSuite) Test_LockRetry_MissingSession() {
+	err := s.registry.LockRetry("signing-1")
+	s.NotNil(err)
+}
+
+func (s *SessionRegistryTestSuite) Test_SessionDetails() {
+	peerID, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	_ = s.registry.Register("signing-1")
+
+	s.registry.SetCoordinator("signing-1", peerID)
+	s.registry.SetExcludedPeers("signing-1", []peer.ID{peerID})
+	s.registry.UpdateState("signing-1", tss.SessionRunning)
+
+	session, _ := s.registry.Session("signing-1")
+	s.Equal(peerID, session.Coordinator)
+	s.Equal([]peer.ID{peerID}, session.ExcludedPeers)
+	s.Equal(tss.SessionRunning, session.State)
+}
+
+func (s *SessionRegistryTestSuite) Test_Finish_FinishedSessionNotUpdated() {
+	_ = s.registry.Register("signing-1")
+	s.registry.Finish("signing-1", errors.New("error"))
+
+	s.registry.UpdateState("signing-1", tss.SessionRunning)
+
+	session, _ := s.registry.Session("signing-1")
+	s.Equal(tss.SessionFailed, session.State)
+	s.Equal("error", session.Error)
+}
+
+func (s *SessionRegistryTestSuite) Test_LiveSessions() {
+	_ = s.registry.Register("signing-1")
+	_ = s.registry.Register("signing-2")
+	_ = s.registry.Register("signing-3")
+	s.registry.Finish("signing-2", nil)
+	s.registry.Release("signing-3")
+
+	sessions := s.registry.Sessions()
+	s.Equal(2, len(sessions))
+
+	liveSessions := s.registry.LiveSessions()
+	s.Equal(1, len(liveSessions))
+	s.Equal("signing-1", liveSessions[0].SessionID)
+}
+
+func (s *SessionRegistryTestSuite) Test_ConcurrentAccess() {
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sessionID := fmt.Sprintf("signing-%d", i%5)
+			_ = s.registry.Register(sessionID)
+			_ = s.registry.LockRetry(sessionID)
+			s.registry.UpdateState(sessionID, tss.SessionRunning)
+			_ = s.registry.LiveSessions()
+			s.registry.Finish(sessionID, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	s.Equal(0, len(s.registry.LiveSessions()))
+}
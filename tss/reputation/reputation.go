@@ -0,0 +1,169 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package reputation
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+type Offense string
+
+const (
+	// Culprit is recorded when tss-lib blames the peer for a failed tss process.
+	Culprit Offense = "culprit"
+	// CoordinatorTimeout is recorded when the peer elected as coordinator didn't start the process.
+	CoordinatorTimeout Offense = "coordinatorTimeout"
+	// CommunicationFailure is recorded when sending messages to the peer fails.
+	CommunicationFailure Offense = "communicationFailure"
+)
+
+// weights of offenses when calculating peer penalty
+var weights = map[Offense]float64{
+	Culprit:              3,
+	CoordinatorTimeout:   2,
+	CommunicationFailure: 1,
+}
+
+type offense struct {
+	offense Offense
+	time    time.Time
+}
+
+// PeerScore contains reputation of a single peer over the tracker window.
+// Score is 1 for peers without offenses and decreases towards 0 with each offense.
+type PeerScore struct {
+	Peer     peer.ID         `json:"peer"`
+	Score    float64         `json:"score"`
+	Offenses map[Offense]int `json:"offenses"`
+}
+
+// Tracker records offenses of peers over a sliding window and ranks peers by
+// their reputation. Reputation is local to the relayer and is not shared with other peers,
+// so it is only used for decisions made by a single relayer, like subset selection of the
+// coordinator, and never for decisions peers have to agree on, like coordinator election.
+type Tracker struct {
+	mu       sync.Mutex
+	window   time.Duration
+	offenses map[peer.ID][]offense
+}
+
+func NewTracker(window time.Duration) *Tracker {
+	return &Tracker{
+		window:   window,
+		offenses: make(map[peer.ID][]offense),
+	}
+}
+
+// Record records offense for the provided peers.
+func (t *Tracker) Record(offenseType Offense, peers ...peer.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, p := range peers {
+		if p == "" {
+			continue
+		}
+
+		t.offenses[p] = append(t.prune(p, now), offense{
+			offense: offenseType,
+			time:    now,
+		})
+	}
+}
+
+// Penalty returns sum of weighted peer offenses inside the window.
+func (t *Tracker) Penalty(p peer.ID) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.penalty(p, time.Now())
+}
+
+// Prioritize sorts peers by their penalty. Peers with the same penalty keep their
+// original order so the result matches the original order if there are no offenses.
+func (t *Tracker) Prioritize(peers []peer.ID) []peer.ID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	penalties := make(map[peer.ID]float64)
+	for _, p := range peers {
+		penalties[p] = t.penalty(p, now)
+	}
+
+	prioritized := make([]peer.ID, len(peers))
+	copy(prioritized, peers)
+	sort.SliceStable(prioritized, func(i, j int) bool {
+		return penalties[prioritized[i]] < penalties[prioritized[j]]
+	})
+	return prioritized
+}
+
+// Scores returns reputation of all peers with offenses inside the window
+// sorted from the worst one.
+func (t *Tracker) Scores() []PeerScore {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	scores := []PeerScore{}
+	for p := range t.offenses {
+		offenses := t.prune(p, now)
+		if len(offenses) == 0 {
+			delete(t.offenses, p)
+			continue
+		}
+		t.offenses[p] = offenses
+
+		score := PeerScore{
+			Peer:     p,
+			Score:    1 / (1 + t.penalty(p, now)),
+			Offenses: make(map[Offense]int),
+		}
+		for _, o := range offenses {
+			score.Offenses[o.offense]++
+		}
+		scores = append(scores, score)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score == scores[j].Score {
+			return scores[i].Peer < scores[j].Peer
+		}
+		return scores[i].Score < scores[j].Score
+	})
+	return scores
+}
+
+// ServeHTTP returns current peer scores as JSON.
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(t.Scores())
+}
+
+func (t *Tracker) penalty(p peer.ID, now time.Time) float64 {
+	var penalty float64
+	for _, o := range t.prune(p, now) {
+		penalty += weights[o.offense]
+	}
+	return penalty
+}
+
+// prune returns peer offenses that are inside the window
+func (t *Tracker) prune(p peer.ID, now time.Time) []offense {
+	offenses := t.offenses[p]
+	for i, o := range offenses {
+		if now.Sub(o.time) <= t.window {
+			return offenses[i:]
+		}
+	}
+	return []offense{}
+}
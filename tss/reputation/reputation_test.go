@@ -0,0 +1,107 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: BUSL-1.1
+
+package reputation_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/tss/reputation"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrackerTestSuite struct {
+	suite.Suite
+	tracker *reputation.Tracker
+	peer1   peer.ID
+	peer2   peer.ID
+	peer3   peer.ID
+}
+
+func TestRunTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(TrackerTestSuite))
+}
+
+func (s *TrackerTestSuite) SetupTest() {
+	s.tracker = reputation.NewTracker(time.Hour)
+	s.peer1, _ = peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	s.peer2, _ = peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	s.peer3, _ = peer.Decode("QmYayosTHxL2xa4jyrQ2PmbhGbrkSxsGM1kzXLTT8SsLVy")
+}
+
+func (s *TrackerTestSuite) Test_Penalty_NoOffenses() {
+	s.Equal(float64(0), s.tracker.Penalty(s.peer1))
+}
+
+func (s *TrackerTestSuite) Test_Penalty_WeightedOffenses() {
+	s.tracker.Record(reputation.Culprit, s.peer1, s.peer2)
+	s.tracker.Record(reputation.CoordinatorTimeout, s.peer1)
+	s.tracker.Record(reputation.CommunicationFailure, s.peer1, peer.ID(""))
+
+	s.Equal(float64(6), s.tracker.Penalty(s.peer1))
+	s.Equal(float64(3), s.tracker.Penalty(s.peer2))
+	s.Equal(float64(0), s.tracker.Penalty(s.peer3))
+}
+
+func (s *TrackerTestSuite) Test_Penalty_OffensesOutsideWindowExpire() {
+	s.tracker = reputation.NewTracker(time.Millisecond * 50)
+	s.tracker.Record(reputation.Culprit, s.peer1)
+
+	time.Sleep(time.Millisecond * 100)
+
+	s.Equal(float64(0), s.tracker.Penalty(s.peer1))
+	s.Equal([]reputation.PeerScore{}, s.tracker.Scores())
+}
+
+func (s *TrackerTestSuite) Test_Prioritize_NoOffensesKeepsOrder() {
+	peers := []peer.ID{s.peer3, s.peer1, s.peer2}
+
+	s.Equal(peers, s.tracker.Prioritize(peers))
+}
+
+func (s *TrackerTestSuite) Test_Prioritize_PeersWithOffensesLast() {
+	s.tracker.Record(reputation.Culprit, s.peer3)
+	s.tracker.Record(reputation.CommunicationFailure, s.peer1)
+
+	prioritized := s.tracker.Prioritize([]peer.ID{s.peer3, s.peer1, s.peer2})
+
+	s.Equal([]peer.ID{s.peer2, s.peer1, s.peer3}, prioritized)
+}
+
+func (s *TrackerTestSuite) Test_Scores_SortedFromWorst() {
+	s.tracker.Record(reputation.CommunicationFailure, s.peer1)
+	s.tracker.Record(reputation.Culprit, s.peer2)
+	s.tracker.Record(reputation.Culprit, s.peer2)
+
+	scores := s.tracker.Scores()
+
+	s.Equal([]reputation.PeerScore{
+		{
+			Peer:     s.peer2,
+			Score:    1.0 / 7,
+			Offenses: map[reputation.Offense]int{reputation.Culprit: 2},
+		},
+		{
+			Peer:     s.peer1,
+			Score:    1.0 / 2,
+			Offenses: map[reputation.Offense]int{reputation.CommunicationFailure: 1},
+		},
+	}, scores)
+}
+
+func (s *TrackerTestSuite) Test_ServeHTTP() {
+	s.tracker.Record(reputation.CoordinatorTimeout, s.peer1)
+	recorder := httptest.NewRecorder()
+
+	s.tracker.ServeHTTP(recorder, httptest.NewRequest("GET", "/reputation", nil))
+
+	var scores []reputation.PeerScore
+	err := json.Unmarshal(recorder.Body.Bytes(), &scores)
+	s.Nil(err)
+	s.Equal("application/json", recorder.Header().Get("Content-Type"))
+	s.Equal(s.tracker.Scores(), scores)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
 	"github.com/binance-chain/tss-lib/ecdsa/resharing"
 	"github.com/binance-chain/tss-lib/tss"
@@ -20,6 +21,16 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+// messageTypes are tss-lib messages exchanged during the process
+var messageTypes = []tss.MessageContent{
+	&resharing.DGRound1Message{},
+	&resharing.DGRound2Message1{},
+	&resharing.DGRound2Message2{},
+	&resharing.DGRound3Message1{},
+	&resharing.DGRound3Message2{},
+	&resharing.DGRound4Message{},
+}
+
 type startParams struct {
 	OldThreshold int       `json:"oldThreshold"`
 	OldSubset    []peer.ID `json:"oldSubset"`
@@ -27,7 +38,7 @@ type startParams struct {
 
 type SaveDataStorer interface {
 	GetKeyshare() (keyshare.Keyshare, error)
-	StoreKeyshare(keyshare keyshare.Keyshare) error
+	StoreKeyshare(keyshare keyshare.Keyshare, sessionID string) error
 	LockKeyshare()
 	UnlockKeyshare()
 }
@@ -64,6 +75,7 @@ func NewResharing(
 			Peers:         host.Peerstore().Peers(),
 			SID:           sessionID,
 			Log:           log.With().Str("SessionID", sessionID).Str("Process", "resharing").Logger(),
+			MessageTypes:  messageTypes,
 			Cancel:        func() {},
 		},
 		key:          key,
@@ -90,6 +102,10 @@ func (r *Resharing) Start(
 		return
 	}
 
+	if err := r.AcquireCurve(ctx, curve.Secp256k1); err != nil {
+		return
+	}
+
 	oldParties := common.PartiesFromPeers(startParams.OldSubset)
 	oldCtx := tss.NewPeerContext(oldParties)
 	newParties := r.sortParties(common.PartiesFromPeers(r.Host.Peerstore().Peers()), oldParties)
@@ -128,6 +144,7 @@ func (r *Resharing) Stop() {
 	r.Communication.UnSubscribe(r.subscriptionID)
 	r.storer.UnlockKeyshare()
 	r.Cancel()
+	r.ReleaseCurve()
 }
 
 // Ready returns true if all parties from peerstore are ready
@@ -193,7 +210,7 @@ func (r *Resharing) processEndMessage(ctx context.Context, endChn chan keygen.Lo
 				r.Log.Info().Msg("Successfully reshared key")
 
 				keyshare := keyshare.NewKeyshare(key, r.newThreshold, r.Peers)
-				err := r.storer.StoreKeyshare(keyshare)
+				err := r.storer.StoreKeyshare(keyshare, r.SessionID())
 				r.ErrChn <- err
 				return
 			}
@@ -54,10 +54,10 @@ func (s *ResharingTestSuite) Test_ValidResharingProcess_OldAndNewSubset() {
 		s.MockStorer.EXPECT().LockKeyshare()
 		s.MockStorer.EXPECT().UnlockKeyshare()
 		s.MockStorer.EXPECT().GetKeyshare().Return(share, nil)
-		s.MockStorer.EXPECT().StoreKeyshare(gomock.Any()).Return(nil)
+		s.MockStorer.EXPECT().StoreKeyshare(gomock.Any(), gomock.Any()).Return(nil)
 		resharing := resharing.NewResharing("resharing2", 1, host, &communication, s.MockStorer)
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
 		processes = append(processes, resharing)
 	}
 	tsstest.SetupCommunication(communicationMap)
@@ -115,7 +115,7 @@ func (s *ResharingTestSuite) Test_InvalidResharingProcess_InvalidOldThreshold_Le
 		s.MockStorer.EXPECT().GetKeyshare().Return(share, nil)
 		resharing := resharing.NewResharing("resharing3", 1, host, &communication, s.MockStorer)
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
 		processes = append(processes, resharing)
 	}
 	tsstest.SetupCommunication(communicationMap)
@@ -177,7 +177,7 @@ func (s *ResharingTestSuite) Test_InvalidResharingProcess_InvalidOldThreshold_Bi
 		s.MockStorer.EXPECT().GetKeyshare().Return(share, nil)
 		resharing := resharing.NewResharing("resharing4", 1, host, &communication, s.MockStorer)
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
 		processes = append(processes, resharing)
 	}
 	tsstest.SetupCommunication(communicationMap)
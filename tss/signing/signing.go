@@ -5,12 +5,15 @@ package signing
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
+	"sync"
 	"time"
 
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
 	"github.com/binance-chain/tss-lib/ecdsa/signing"
 	"github.com/binance-chain/tss-lib/tss"
 	"github.com/libp2p/go-libp2p-core/host"
@@ -22,29 +25,91 @@ import (
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	errors "github.com/ChainSafe/sygma-relayer/tss"
 	"github.com/ChainSafe/sygma-relayer/tss/common"
+	"github.com/ChainSafe/sygma-relayer/tss/curve"
+	"github.com/ChainSafe/sygma-relayer/tss/presign"
 )
 
+var (
+	presignatureWaitPeriod = 10 * time.Second
+	shareResendPeriod      = 5 * time.Second
+	shareTimeout           = 3 * time.Minute
+)
+
+// messageTypes are tss-lib messages exchanged during the process
+var messageTypes = []tss.MessageContent{
+	&signing.SignRound1Message1{},
+	&signing.SignRound1Message2{},
+	&signing.SignRound2Message{},
+	&signing.SignRound3Message{},
+	&signing.SignRound4Message{},
+	&signing.SignRound5Message{},
+	&signing.SignRound6Message{},
+	&signing.SignRound7Message{},
+}
+
 type SaveDataFetcher interface {
 	GetKeyshare() (keyshare.Keyshare, error)
 	LockKeyshare()
 	UnlockKeyshare()
 }
 
+type PresignaturePool interface {
+	Select(peers []peer.ID) (presign.Presignature, error)
+	Release(presignatureID string)
+	Take(presignatureID string) (presign.Presignature, error)
+}
+
+type PeerPrioritizer interface {
+	Prioritize(peers []peer.ID) []peer.ID
+}
+
+// ProposalVerifier verifies proposals received from the coordinator against
+// proposals derived from locally observed deposits.
+type ProposalVerifier interface {
+	Proposals() []*proposal.Proposal
+	VerifyProposals(proposals []*proposal.Proposal) ([]byte, error)
+	// Required returns true once all relayers send proposals with start params so
+	// start params without proposals are rejected.
+	Required() bool
+}
+
+// StartParams contains peer subset selected by the coordinator, presignature used
+// to sign the message in a single round if one was available and proposals that are signed.
+type StartParams struct {
+	Peers          []peer.ID            `json:"peers"`
+	PresignatureID string               `json:"presignatureID,omitempty"`
+	Proposals      []*proposal.Proposal `json:"proposals,omitempty"`
+}
+
 type Signing struct {
 	common.BaseTss
 	coordinator    bool
 	key            keyshare.Keyshare
 	msg            *big.Int
+	presignatures  PresignaturePool
+	reservedLock   sync.Mutex
+	reserved       *presign.Presignature
+	prioritizer    PeerPrioritizer
+	verifier       ProposalVerifier
+	readyAt        time.Time
 	resultChn      chan interface{}
 	subscriptionID comm.SubscriptionID
 }
 
+// NewSigning creates a signing process. If presignature pool is provided signing is
+// finished in a single round when the coordinator has a presignature for ready peers.
+// If peer prioritizer is provided peers with worse reputation are selected into the subset last.
+// If proposal verifier is provided participants verify proposals sent by the coordinator
+// match the signed message before joining the session.
 func NewSigning(
 	msg *big.Int,
 	sessionID string,
 	host host.Host,
 	comm comm.Communication,
 	fetcher SaveDataFetcher,
+	presignatures PresignaturePool,
+	prioritizer PeerPrioritizer,
+	verifier ProposalVerifier,
 ) (*Signing, error) {
 	fetcher.LockKeyshare()
 	defer fetcher.UnlockKeyshare()
@@ -62,10 +127,14 @@ func NewSigning(
 			Peers:         key.Peers,
 			SID:           sessionID,
 			Log:           log.With().Str("SessionID", sessionID).Str("Process", "signing").Logger(),
+			MessageTypes:  messageTypes,
 			Cancel:        func() {},
 		},
-		key: key,
-		msg: msg,
+		key:           key,
+		msg:           msg,
+		presignatures: presignatures,
+		prioritizer:   prioritizer,
+		verifier:      verifier,
 	}, nil
 }
 
@@ -83,18 +152,36 @@ func (s *Signing) Start(
 	s.resultChn = resultChn
 	ctx, s.Cancel = context.WithCancel(ctx)
 
-	peerSubset, err := s.unmarshallStartParams(params)
+	startParams, legacy, err := s.unmarshallStartParams(params)
 	if err != nil {
 		s.ErrChn <- err
 		return
 	}
 
-	if !common.IsParticipant(common.CreatePartyID(s.Host.ID().Pretty()), common.PartiesFromPeers(peerSubset)) {
+	if !common.IsParticipant(common.CreatePartyID(s.Host.ID().Pretty()), common.PartiesFromPeers(startParams.Peers)) {
 		s.ErrChn <- &errors.SubsetError{Peer: s.Host.ID()}
 		return
 	}
 
-	s.Peers = peerSubset
+	if !coordinator && s.verifier != nil {
+		err := s.verifyProposals(startParams.Proposals, legacy)
+		if err != nil {
+			s.Log.Error().Err(err).Msgf("Refusing to join signing session")
+			s.ErrChn <- &errors.ProposalMismatchError{Err: err}
+			return
+		}
+	}
+
+	if err := s.AcquireCurve(ctx, curve.Secp256k1); err != nil {
+		return
+	}
+
+	s.Peers = startParams.Peers
+	if startParams.PresignatureID != "" {
+		s.startOneRound(ctx, startParams.PresignatureID)
+		return
+	}
+
 	parties := common.PartiesFromPeers(s.Peers)
 	s.PopulatePartyStore(parties)
 	pCtx := tss.NewPeerContext(parties)
@@ -127,12 +214,31 @@ func (s *Signing) Stop() {
 	log.Info().Str("sessionID", s.SessionID()).Msgf("Stopping tss process.")
 	s.Communication.UnSubscribe(s.subscriptionID)
 	s.Cancel()
+	s.ReleaseCurve()
+	s.releasePresignature()
 }
 
 // Ready returns true if threshold+1 parties are ready to start the signing process.
+// If there is no presignature for the ready parties, coordinator waits for a limited
+// time for presignature parties to become ready before starting the full signing process.
 func (s *Signing) Ready(readyMap map[peer.ID]bool, excludedPeers []peer.ID) (bool, error) {
 	readyMap = s.readyParticipants(readyMap)
-	return len(readyMap) == s.key.Threshold+1, nil
+	if len(readyMap) < s.key.Threshold+1 {
+		return false, nil
+	}
+	if s.presignatures == nil {
+		return true, nil
+	}
+
+	_, ok := s.reservePresignature(peersFromReadyMap(readyMap))
+	if ok {
+		return true, nil
+	}
+
+	if s.readyAt.IsZero() {
+		s.readyAt = time.Now()
+	}
+	return len(readyMap) == len(s.key.Peers) || time.Since(s.readyAt) >= presignatureWaitPeriod, nil
 }
 
 // ValidCoordinators returns only peers that have a valid keyshare
@@ -143,34 +249,231 @@ func (s *Signing) ValidCoordinators() []peer.ID {
 // StartParams returns peer subset for this tss process. It is calculated
 // by sorting hashes of peer IDs and session ID and chosing ready peers alphabetically
 // until threshold is satisfied.
+// If coordinator has a presignature for ready peers, peer subset holding the presignature
+// is returned instead.
+// Without a presignature, params are encoded as the peer subset list understood by relayers
+// running older versions, unless proposal verification is required and proposals have to be sent.
 func (s *Signing) StartParams(readyMap map[peer.ID]bool) []byte {
 	readyMap = s.readyParticipants(readyMap)
-	peers := []peer.ID{}
-	for peer := range readyMap {
-		peers = append(peers, peer)
+	peers := peersFromReadyMap(readyMap)
+	if s.presignatures != nil {
+		presignature, ok := s.reservePresignature(peers)
+		if ok {
+			paramBytes, _ := json.Marshal(StartParams{
+				Peers:          presignature.Peers,
+				PresignatureID: presignature.ID,
+				Proposals:      s.proposals(),
+			})
+			return paramBytes
+		}
+	}
+
+	sortedPeers := []peer.ID{}
+	for _, peer := range common.SortPeersForSession(peers, s.SessionID()) {
+		sortedPeers = append(sortedPeers, peer.ID)
+	}
+	if s.prioritizer != nil {
+		sortedPeers = s.prioritizer.Prioritize(sortedPeers)
 	}
 
-	sortedPeers := common.SortPeersForSession(peers, s.SessionID())
 	peerSubset := []peer.ID{}
 	for _, peer := range sortedPeers {
-		peerSubset = append(peerSubset, peer.ID)
+		peerSubset = append(peerSubset, peer)
 		if len(peerSubset) == s.key.Threshold+1 {
 			break
 		}
 	}
 
-	paramBytes, _ := json.Marshal(peerSubset)
+	if s.verifier == nil || !s.verifier.Required() {
+		paramBytes, _ := json.Marshal(peerSubset)
+		return paramBytes
+	}
+
+	paramBytes, _ := json.Marshal(StartParams{
+		Peers:     peerSubset,
+		Proposals: s.proposals(),
+	})
 	return paramBytes
 }
 
-func (s *Signing) unmarshallStartParams(paramBytes []byte) ([]peer.ID, error) {
+// reservePresignature selects presignature for the peers and keeps it reserved for the session
+// until the process is stopped, so concurrent sessions select different presignatures.
+func (s *Signing) reservePresignature(peers []peer.ID) (presign.Presignature, bool) {
+	s.reservedLock.Lock()
+	defer s.reservedLock.Unlock()
+
+	if s.reserved != nil {
+		return *s.reserved, true
+	}
+	presignature, err := s.presignatures.Select(peers)
+	if err != nil {
+		return presign.Presignature{}, false
+	}
+
+	s.reserved = &presignature
+	return presignature, true
+}
+
+// releasePresignature releases presignature reserved for the session. Presignature that
+// was already taken is not in the pool anymore so releasing it has no effect.
+func (s *Signing) releasePresignature() {
+	s.reservedLock.Lock()
+	defer s.reservedLock.Unlock()
+
+	if s.reserved == nil {
+		return
+	}
+	s.presignatures.Release(s.reserved.ID)
+	s.reserved = nil
+}
+
+func (s *Signing) proposals() []*proposal.Proposal {
+	if s.verifier == nil {
+		return nil
+	}
+	return s.verifier.Proposals()
+}
+
+// verifyProposals checks that proposals received from the coordinator are derived from
+// locally observed deposits and that their recomputed hash matches the signed message.
+// Legacy start params of coordinators that don't send proposals are accepted without
+// verification until verification is required.
+func (s *Signing) verifyProposals(proposals []*proposal.Proposal, legacy bool) error {
+	if legacy && !s.verifier.Required() {
+		s.Log.Warn().Msgf("Coordinator sent start params without proposals, skipping proposal verification")
+		return nil
+	}
+
+	hash, err := s.verifier.VerifyProposals(proposals)
+	if err != nil {
+		return err
+	}
+
+	if new(big.Int).SetBytes(hash).Cmp(s.msg) != 0 {
+		return fmt.Errorf("proposals hash %x doesn't match signed message %x", hash, s.msg.Bytes())
+	}
+	return nil
+}
+
+// unmarshallStartParams parses start params sent by the coordinator. Coordinators
+// without presignature support send only the peer subset, in which case legacy is true.
+func (s *Signing) unmarshallStartParams(paramBytes []byte) (startParams StartParams, legacy bool, err error) {
+	err = json.Unmarshal(paramBytes, &startParams)
+	if err == nil {
+		return startParams, false, nil
+	}
+
 	var peerSubset []peer.ID
-	err := json.Unmarshal(paramBytes, &peerSubset)
+	err = json.Unmarshal(paramBytes, &peerSubset)
 	if err != nil {
-		return []peer.ID{}, err
+		return StartParams{}, false, err
 	}
 
-	return peerSubset, nil
+	return StartParams{Peers: peerSubset}, true, nil
+}
+
+// startOneRound calculates the signature share from the presignature and exchanges
+// it with other presignature holders.
+// Participant without the presignature waits for the next start message as if it
+// was not selected in the subset.
+func (s *Signing) startOneRound(ctx context.Context, presignatureID string) {
+	if s.presignatures == nil {
+		s.ErrChn <- &errors.SubsetError{Peer: s.Host.ID()}
+		return
+	}
+
+	presignature, err := s.presignatures.Take(presignatureID)
+	if err != nil {
+		s.Log.Warn().Err(err).Msgf("Unable to use presignature %s", presignatureID)
+		s.ErrChn <- &errors.SubsetError{Peer: s.Host.ID()}
+		return
+	}
+	data, err := presignature.OneRoundData()
+	if err != nil {
+		s.ErrChn <- err
+		return
+	}
+
+	parties := common.PartiesFromPeers(s.Peers)
+	s.PopulatePartyStore(parties)
+
+	shareChn := make(chan *comm.WrappedMessage)
+	s.subscriptionID = s.Communication.Subscribe(s.SessionID(), comm.TssOneRoundSignMsg, shareChn)
+
+	s.Log.Info().Msgf("Started one round signing process with presignature %s", presignatureID)
+
+	share := signing.FinalizeGetOurSigShare(data, s.msg)
+	go s.processShares(ctx, data, share, shareChn)
+}
+
+// processShares broadcasts signature share until shares from all other
+// presignature holders are received and the final signature is calculated.
+func (s *Signing) processShares(
+	ctx context.Context,
+	data *signing.SignatureData_OneRoundData,
+	share *big.Int,
+	shareChn chan *comm.WrappedMessage,
+) {
+	otherPeers := common.ExcludePeers(s.Peers, []peer.ID{s.Host.ID()})
+	go s.Communication.Broadcast(otherPeers, share.Bytes(), comm.TssOneRoundSignMsg, s.SessionID(), nil)
+
+	resendTicker := time.NewTicker(shareResendPeriod)
+	defer resendTicker.Stop()
+	timeout := time.NewTimer(shareTimeout)
+	defer timeout.Stop()
+
+	shares := make(map[*tss.PartyID]*big.Int)
+	for {
+		select {
+		case msg := <-shareChn:
+			{
+				party, ok := s.PartyStore[msg.From.Pretty()]
+				if !ok || msg.From == s.Host.ID() {
+					continue
+				}
+
+				shares[party] = new(big.Int).SetBytes(msg.Payload)
+				if len(shares) != len(otherPeers) {
+					continue
+				}
+
+				pk := &ecdsa.PublicKey{
+					Curve: tss.EC(),
+					X:     s.key.Key.ECDSAPub.X(),
+					Y:     s.key.Key.ECDSAPub.Y(),
+				}
+				sig, _, tssErr := signing.FinalizeGetAndVerifyFinalSig(
+					&signing.SignatureData{OneRoundData: data}, pk, s.msg, s.PartyStore[s.Host.ID().Pretty()], share, shares,
+				)
+				if tssErr != nil {
+					s.ErrChn <- tssErr
+					return
+				}
+
+				s.Log.Info().Msg("Successfully generated signature")
+				if s.coordinator {
+					s.resultChn <- sig
+				}
+				s.ErrChn <- nil
+				return
+			}
+		case <-resendTicker.C:
+			{
+				go s.Communication.Broadcast(otherPeers, share.Bytes(), comm.TssOneRoundSignMsg, s.SessionID(), nil)
+			}
+		case <-timeout.C:
+			{
+				s.ErrChn <- &comm.CommunicationError{
+					Err: fmt.Errorf("waiting for signature shares timed out after %s", shareTimeout),
+				}
+				return
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
 }
 
 // processEndMessage routes signature to result channel.
@@ -213,6 +516,15 @@ func (s *Signing) readyParticipants(readyMap map[peer.ID]bool) map[peer.ID]bool
 	return readyParticipants
 }
 
+func peersFromReadyMap(readyMap map[peer.ID]bool) []peer.ID {
+	peers := []peer.ID{}
+	for peer := range readyMap {
+		peers = append(peers, peer)
+	}
+
+	return peers
+}
+
 func (s *Signing) Retryable() bool {
 	return true
 }
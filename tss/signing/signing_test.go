@@ -2,18 +2,24 @@ package signing_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"testing"
 	"time"
 
+	"github.com/ChainSafe/chainbridge-core/chains/evm/executor/proposal"
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/memory"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	"github.com/ChainSafe/sygma-relayer/tss"
 	"github.com/ChainSafe/sygma-relayer/tss/keygen"
 	"github.com/ChainSafe/sygma-relayer/tss/signing"
 	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/suite"
 )
 
 type SigningTestSuite struct {
@@ -36,12 +42,12 @@ func (s *SigningTestSuite) Test_ValidSigningProcess() {
 		msgBytes := []byte("Message")
 		msg := big.NewInt(0)
 		msg.SetBytes(msgBytes)
-		signing, err := signing.NewSigning(msg, "signing1", host, &communication, fetcher)
+		signing, err := signing.NewSigning(msg, "signing1", host, &communication, fetcher, nil, nil, nil)
 		if err != nil {
 			panic(err)
 		}
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
 		processes = append(processes, signing)
 	}
 	tsstest.SetupCommunication(communicationMap)
@@ -77,12 +83,12 @@ func (s *SigningTestSuite) Test_SigningTimeout() {
 		msgBytes := []byte("Message")
 		msg := big.NewInt(0)
 		msg.SetBytes(msgBytes)
-		signing, err := signing.NewSigning(msg, "signing2", host, &communication, fetcher)
+		signing, err := signing.NewSigning(msg, "signing2", host, &communication, fetcher, nil, nil, nil)
 		if err != nil {
 			panic(err)
 		}
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinator := tss.NewCoordinator(host, &communication, electorFactory)
+		coordinator := tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil)
 		coordinator.TssTimeout = time.Nanosecond
 		coordinators = append(coordinators, coordinator)
 		processes = append(processes, signing)
@@ -118,7 +124,7 @@ func (s *SigningTestSuite) Test_PendingProcessExists() {
 		communicationMap[host.ID()] = &communication
 		keygen := keygen.NewKeygen("keygen3", s.Threshold, host, &communication, s.MockStorer)
 		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
-		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
+		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory, s.MockJournal, nil))
 		processes = append(processes, keygen)
 	}
 	tsstest.SetupCommunication(communicationMap)
@@ -139,3 +145,136 @@ func (s *SigningTestSuite) Test_PendingProcessExists() {
 	time.Sleep(time.Millisecond * 50)
 	cancel()
 }
+
+type testProposalVerifier struct {
+	required bool
+	verified bool
+}
+
+func (v *testProposalVerifier) Proposals() []*proposal.Proposal {
+	return []*proposal.Proposal{{Source: 1, DepositNonce: 1}}
+}
+
+func (v *testProposalVerifier) VerifyProposals(proposals []*proposal.Proposal) ([]byte, error) {
+	v.verified = true
+	return nil, fmt.Errorf("proposals don't match")
+}
+
+func (v *testProposalVerifier) Required() bool {
+	return v.required
+}
+
+type StartParamsTestSuite struct {
+	tsstest.CoordinatorTestSuite
+}
+
+func TestRunStartParamsTestSuite(t *testing.T) {
+	suite.Run(t, new(StartParamsTestSuite))
+}
+
+func (s *StartParamsTestSuite) TearDownTest() {
+	for _, h := range s.Hosts {
+		_ = h.Close()
+	}
+}
+
+func (s *StartParamsTestSuite) participant(verifier signing.ProposalVerifier) *signing.Signing {
+	host := s.Hosts[1]
+	fetcher := keyshare.NewKeyshareStore("../test/keyshares/1.keyshare")
+	process, err := signing.NewSigning(
+		big.NewInt(1), "signing1", host, memory.NewCommunication(memory.NewNetwork(), host.ID()), fetcher, nil, nil, verifier,
+	)
+	s.Nil(err)
+	return process
+}
+
+func (s *StartParamsTestSuite) legacyParams() []byte {
+	params, err := json.Marshal([]peer.ID{s.Hosts[0].ID(), s.Hosts[1].ID()})
+	s.Nil(err)
+	return params
+}
+
+func (s *StartParamsTestSuite) Test_Start_LegacyParamsRejectedWhenVerificationRequired() {
+	verifier := &testProposalVerifier{required: true}
+	process := s.participant(verifier)
+	errChn := make(chan error, 1)
+
+	process.Start(context.Background(), false, make(chan interface{}), errChn, s.legacyParams())
+
+	err := <-errChn
+	var mismatchErr *tss.ProposalMismatchError
+	s.True(errors.As(err, &mismatchErr))
+	s.True(verifier.verified)
+}
+
+func (s *StartParamsTestSuite) Test_Start_LegacyParamsAcceptedWithoutVerification() {
+	verifier := &testProposalVerifier{required: false}
+	process := s.participant(verifier)
+	errChn := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	process.Start(ctx, false, make(chan interface{}), errChn, s.legacyParams())
+	defer process.Stop()
+
+	s.False(verifier.verified)
+	select {
+	case err := <-errChn:
+		var mismatchErr *tss.ProposalMismatchError
+		s.False(errors.As(err, &mismatchErr))
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func (s *StartParamsTestSuite) Test_Start_ParamsWithProposalsVerified() {
+	verifier := &testProposalVerifier{required: false}
+	process := s.participant(verifier)
+	errChn := make(chan error, 1)
+	params, err := json.Marshal(signing.StartParams{
+		Peers:     []peer.ID{s.Hosts[0].ID(), s.Hosts[1].ID()},
+		Proposals: []*proposal.Proposal{{Source: 1, DepositNonce: 1}},
+	})
+	s.Nil(err)
+
+	process.Start(context.Background(), false, make(chan interface{}), errChn, params)
+
+	err = <-errChn
+	var mismatchErr *tss.ProposalMismatchError
+	s.True(errors.As(err, &mismatchErr))
+	s.True(verifier.verified)
+}
+
+func (s *StartParamsTestSuite) readyMap() map[peer.ID]bool {
+	readyMap := make(map[peer.ID]bool)
+	for _, h := range s.Hosts {
+		readyMap[h.ID()] = true
+	}
+	return readyMap
+}
+
+// baselineStartParams parses start params the way relayers without presignature support do.
+func baselineStartParams(params []byte) ([]peer.ID, error) {
+	var peerSubset []peer.ID
+	err := json.Unmarshal(params, &peerSubset)
+	return peerSubset, err
+}
+
+func (s *StartParamsTestSuite) Test_StartParams_LegacyFormParsedByBaselineRelayers() {
+	for _, verifier := range []signing.ProposalVerifier{nil, &testProposalVerifier{required: false}} {
+		params := s.participant(verifier).StartParams(s.readyMap())
+
+		peerSubset, err := baselineStartParams(params)
+		s.Nil(err)
+		s.Len(peerSubset, s.Threshold+1)
+	}
+}
+
+func (s *StartParamsTestSuite) Test_StartParams_ProposalsSentWhenVerificationRequired() {
+	params := s.participant(&testProposalVerifier{required: true}).StartParams(s.readyMap())
+
+	startParams := signing.StartParams{}
+	err := json.Unmarshal(params, &startParams)
+	s.Nil(err)
+	s.Len(startParams.Peers, s.Threshold+1)
+	s.Len(startParams.Proposals, 1)
+}
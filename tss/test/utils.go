@@ -3,6 +3,8 @@ package tsstest
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/ChainSafe/sygma-relayer/config/relayer"
@@ -24,6 +26,7 @@ type CoordinatorTestSuite struct {
 	MockStorer        *mock_tss.MockSaveDataStorer
 	MockCommunication *mock_comm.MockCommunication
 	MockTssProcess    *mock_tss.MockTssProcess
+	MockJournal       *mock_tss.MockSessionJournal
 
 	Hosts       []host.Host
 	Threshold   int
@@ -36,6 +39,9 @@ func (s *CoordinatorTestSuite) SetupTest() {
 	s.MockStorer = mock_tss.NewMockSaveDataStorer(s.GomockController)
 	s.MockCommunication = mock_comm.NewMockCommunication(s.GomockController)
 	s.MockTssProcess = mock_tss.NewMockTssProcess(s.GomockController)
+	s.MockJournal = mock_tss.NewMockSessionJournal(s.GomockController)
+	s.MockJournal.EXPECT().UpdatePhase(gomock.Any(), gomock.Any()).AnyTimes()
+	s.MockJournal.EXPECT().Finish(gomock.Any(), gomock.Any()).AnyTimes()
 	s.PartyNumber = 3
 	s.Threshold = 1
 
@@ -60,7 +66,7 @@ func (s *CoordinatorTestSuite) SetupTest() {
 }
 
 func NewHost(i int) (host.Host, error) {
-	privBytes, err := ioutil.ReadFile(fmt.Sprintf("../test/pks/%d.pk", i))
+	privBytes, err := ioutil.ReadFile(filepath.Join(testDir(), fmt.Sprintf("pks/%d.pk", i)))
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +88,13 @@ func NewHost(i int) (host.Host, error) {
 	return h, nil
 }
 
+// testDir returns directory of test fixtures so they can be loaded
+// from tests in nested packages
+func testDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}
+
 func SetupCommunication(commMap map[peer.ID]*TestCommunication) {
 	for self, comm := range commMap {
 		peerComms := make(map[string]Receiver)